@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/guilhermealvess/guicpay/infra/eventbus"
+	"github.com/guilhermealvess/guicpay/infra/repository"
+	"github.com/guilhermealvess/guicpay/infra/service"
+	"github.com/guilhermealvess/guicpay/internal/database"
+	"github.com/guilhermealvess/guicpay/internal/properties"
+)
+
+const usage = `guicpay-admin: operator tooling
+
+Usage:
+  admin <command> [flags]
+
+Commands:
+  lookup          find an account by --email or --document
+  balance         print the balance of --account, optionally --at an instant
+  status          change the status of --account to --status (ACTIVE, CANCELED)
+  role            grant --account the --role (CUSTOMER, SELLER, SUPPORT, ADMIN)
+  snapshot        force a snapshot of --account
+  reverse         reverse the transfer identified by --transfer (correlated id)
+  reset-password  set a new password for --account (random when --password is empty)
+  unlock          clear the failed logins locking out --account
+
+Global flags (after the command):
+  --json          print results as JSON
+  --dry-run       run mutating commands without committing
+`
+
+type command struct {
+	flags *flag.FlagSet
+	json  *bool
+	dry   *bool
+	run   func(ctx context.Context, u usecase.AccountUseCase) (any, error)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	commands := buildCommands()
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err := cmd.flags.Parse(os.Args[2:]); err != nil {
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	ctx = usecase.InjectActor(ctx, operator())
+
+	output, err := cmd.run(ctx, buildUseCase())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := printOutput(output, *cmd.json); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func buildUseCase() usecase.AccountUseCase {
+	db := database.NewConnectionDB()
+	return usecase.NewAccountUseCase(usecase.Dependencies{
+		Accounts:      repository.NewAccountRepository(db),
+		Webhooks:      repository.NewWebhookRepository(db),
+		MFA:           repository.NewMFARepository(db),
+		LoginAttempts: repository.NewLoginAttemptRepository(db),
+		Tokens:        repository.NewTokenRepository(db),
+		Verifications: repository.NewVerificationRepository(db),
+		Audit:         repository.NewAuditRepository(db),
+		Authorizer:    service.NewAuthorizationService(properties.Props.AuthorizeServiceURL),
+		Notifier:      service.NewNotificationService(properties.Props.NotificationServiceURL),
+		// operator actions are not broadcast to notification subscribers
+		Bus: eventbus.NewInMemoryEventBus(),
+	})
+}
+
+// operator is the actor of the audit entries of a command: it has no account, so entries name the
+// system user running it.
+func operator() entity.AuditActor {
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+
+	return entity.AuditActor{UserAgent: "guicpay-admin (" + name + ")", RequestID: uuid.NewString()}
+}
+
+func newCommand(name string, mutating bool) *command {
+	c := &command{flags: flag.NewFlagSet(name, flag.ExitOnError)}
+	c.json = c.flags.Bool("json", false, "print results as JSON")
+	dry := false
+	c.dry = &dry
+	if mutating {
+		c.dry = c.flags.Bool("dry-run", false, "run without committing")
+	}
+
+	return c
+}
+
+func buildCommands() map[string]*command {
+	commands := make(map[string]*command)
+
+	lookup := newCommand("lookup", false)
+	email := lookup.flags.String("email", "", "account email")
+	document := lookup.flags.String("document", "", "account document number")
+	lookup.run = func(ctx context.Context, u usecase.AccountUseCase) (any, error) {
+		switch {
+		case *email != "":
+			return u.FindByEmail(ctx, *email)
+		case *document != "":
+			return u.FindByDocument(ctx, *document)
+		}
+
+		return nil, fmt.Errorf("lookup requires --email or --document")
+	}
+	commands["lookup"] = lookup
+
+	balance := newCommand("balance", false)
+	balanceAccount := balance.flags.String("account", "", "account id")
+	at := balance.flags.String("at", "", "RFC 3339 instant, defaults to now")
+	balance.run = func(ctx context.Context, u usecase.AccountUseCase) (any, error) {
+		accountID, err := uuid.Parse(*balanceAccount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --account: %w", err)
+		}
+
+		var instant time.Time
+		if *at != "" {
+			if instant, err = time.Parse(time.RFC3339, *at); err != nil {
+				return nil, fmt.Errorf("invalid --at: %w", err)
+			}
+		}
+
+		return u.FindBalanceAt(ctx, accountID, instant)
+	}
+	commands["balance"] = balance
+
+	status := newCommand("status", true)
+	statusAccount := status.flags.String("account", "", "account id")
+	newStatus := status.flags.String("status", "", "new status (ACTIVE, CANCELED)")
+	status.run = func(ctx context.Context, u usecase.AccountUseCase) (any, error) {
+		accountID, err := uuid.Parse(*statusAccount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --account: %w", err)
+		}
+
+		return u.ExecuteChangeStatus(ctx, accountID, *newStatus, *status.dry)
+	}
+	commands["status"] = status
+
+	role := newCommand("role", true)
+	roleAccount := role.flags.String("account", "", "account id")
+	newRole := role.flags.String("role", "", "new role (CUSTOMER, SELLER, SUPPORT, ADMIN)")
+	role.run = func(ctx context.Context, u usecase.AccountUseCase) (any, error) {
+		accountID, err := uuid.Parse(*roleAccount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --account: %w", err)
+		}
+
+		return u.ExecuteChangeRole(ctx, accountID, *newRole, *role.dry)
+	}
+	commands["role"] = role
+
+	snapshot := newCommand("snapshot", true)
+	snapshotAccount := snapshot.flags.String("account", "", "account id")
+	snapshot.run = func(ctx context.Context, u usecase.AccountUseCase) (any, error) {
+		accountID, err := uuid.Parse(*snapshotAccount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --account: %w", err)
+		}
+
+		return u.ExecuteForceSnapshot(ctx, accountID, *snapshot.dry)
+	}
+	commands["snapshot"] = snapshot
+
+	reverse := newCommand("reverse", true)
+	transfer := reverse.flags.String("transfer", "", "correlated id of the transfer")
+	reverse.run = func(ctx context.Context, u usecase.AccountUseCase) (any, error) {
+		correlatedID, err := uuid.Parse(*transfer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --transfer: %w", err)
+		}
+
+		return u.ExecuteReverseTransfer(ctx, correlatedID, *reverse.dry)
+	}
+	commands["reverse"] = reverse
+
+	reset := newCommand("reset-password", true)
+	resetAccount := reset.flags.String("account", "", "account id")
+	password := reset.flags.String("password", "", "new password, random when empty")
+	reset.run = func(ctx context.Context, u usecase.AccountUseCase) (any, error) {
+		accountID, err := uuid.Parse(*resetAccount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --account: %w", err)
+		}
+
+		return u.ExecuteResetPassword(ctx, accountID, *password, *reset.dry)
+	}
+	commands["reset-password"] = reset
+
+	unlock := newCommand("unlock", true)
+	unlockAccount := unlock.flags.String("account", "", "account id")
+	unlock.run = func(ctx context.Context, u usecase.AccountUseCase) (any, error) {
+		accountID, err := uuid.Parse(*unlockAccount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --account: %w", err)
+		}
+
+		return u.ExecuteUnlockLogin(ctx, accountID, *unlock.dry)
+	}
+	commands["unlock"] = unlock
+
+	return commands
+}
+
+// printOutput writes the output as indented JSON or as aligned "field  value" lines.
+func printOutput(output any, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(output)
+	}
+
+	raw, err := json.Marshal(output)
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s\t%v\n", k, fields[k])
+	}
+
+	return w.Flush()
+}
@@ -3,47 +3,102 @@ package main
 import (
 	"context"
 	"fmt"
+	"log"
+	"os"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
 	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/guilhermealvess/guicpay/infra/eventbus"
 	"github.com/guilhermealvess/guicpay/infra/repository"
 	"github.com/guilhermealvess/guicpay/infra/service"
 	grpcport "github.com/guilhermealvess/guicpay/interface/grpc_port"
 	"github.com/guilhermealvess/guicpay/interface/http"
 	"github.com/guilhermealvess/guicpay/internal/database"
 	"github.com/guilhermealvess/guicpay/internal/properties"
+	"github.com/guilhermealvess/guicpay/internal/ratelimit"
+	"github.com/guilhermealvess/guicpay/internal/token"
+	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4/middleware"
 	_ "go.uber.org/automaxprocs"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+
 	fmt.Println("Guic Pay Simplificado ...")
 
 	queue, snapshotBackgroundWorker := buildSnapShotWorker()
 
 	// Gateway
-	repo := repository.NewAccountRepository(database.NewConnectionDB())
+	db := database.NewConnectionDB()
+	if properties.Props.MigrateOnStart {
+		if code := migrateUp(db); code != 0 {
+			os.Exit(code)
+		}
+	}
+
+	repo := repository.NewAccountRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	tokenRepo := repository.NewTokenRepository(db)
+	mfaRepo := repository.NewMFARepository(db)
+	loginRepo := repository.NewLoginAttemptRepository(db)
+	verificationRepo := repository.NewVerificationRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	token.UseRevocationStore(tokenRepo)
 	notificationService := service.NewNotificationService(properties.Props.NotificationServiceURL)
 	authService := service.NewAuthorizationService(properties.Props.AuthorizeServiceURL)
+	webhookService := service.NewWebhookService(properties.Props.Webhook.Timeout, properties.Props.Webhook.InsecureTargets)
+
+	bus := eventbus.NewInMemoryEventBus()
+	bus.Subscribe(usecase.NewNotificationSubscriber(notificationService), entity.EventDepositCompleted, entity.EventTransferCompleted, entity.EventTransferReversed)
+	bus.Subscribe(usecase.NewSnapshotSubscriber(queue), entity.EventDepositCompleted, entity.EventTransferCompleted, entity.EventTransferReversed)
 
 	// UseCase
-	usecase := usecase.NewAccountUseCase(repo, notificationService, authService, queue)
+	deps := usecase.Dependencies{
+		Accounts:      repo,
+		Webhooks:      webhookRepo,
+		MFA:           mfaRepo,
+		LoginAttempts: loginRepo,
+		Tokens:        tokenRepo,
+		Verifications: verificationRepo,
+		APIKeys:       apiKeyRepo,
+		Audit:         auditRepo,
+		Authorizer:    authService,
+		Notifier:      notificationService,
+		WebhookSender: webhookService,
+		Signer:        token.JWT,
+		Bus:           bus,
+	}
+	webhookUseCase := usecase.NewWebhookUseCase(deps)
+	authUseCase := usecase.NewAuthUseCase(deps)
+	apiKeyUseCase := usecase.NewAPIKeyUseCase(deps)
+	token.UseAPIKeys(apiKeyUseCase)
+	usecase := usecase.NewAccountUseCase(deps)
 	go snapshotBackgroundWorker(usecase)
+	go webhookBackgroundWorker(webhookUseCase)
 
 	// Handler
 	handler := http.NewAccountHandler(usecase)
+	webhookHandler := http.NewWebhookHandler(webhookUseCase)
+	authHandler := http.NewAuthHandler(authUseCase)
+	apiKeyHandler := http.NewAPIKeyHandler(apiKeyUseCase)
 
 	// Application Server
-	server := http.NewServer(handler)
+	limiter := buildRateLimiter(db)
+	server := http.NewServer(handler, webhookHandler, authHandler, apiKeyHandler, limiter)
 	server.Use(middleware.Logger())
-	server.Use(middleware.RequestID())
 	server.Use(middleware.Recover())
 	server.Use(middleware.AddTrailingSlash())
 	go func() {
 		server.Logger.Fatal(server.Start(fmt.Sprintf(":%d", properties.Props.RestPort)))
 	}()
 
-	grpcApp := grpcport.NewApp(usecase)
+	grpcApp := grpcport.NewApp(usecase, authUseCase, limiter)
 	grpcApp.Start(properties.Props.GRPCPort)
 	close(queue)
 }
@@ -57,3 +112,41 @@ func buildSnapShotWorker() (chan uuid.UUID, func(usecase.AccountUseCase)) {
 		}
 	}
 }
+
+// buildRateLimiter reads the limits from properties; RATE_LIMIT_STORE=database keeps the buckets in
+// the database so they hold across replicas.
+func buildRateLimiter(db *sqlx.DB) *ratelimit.Limiter {
+	config := properties.Props.RateLimit
+	parse := func(v string) ratelimit.Limit {
+		limit, err := ratelimit.ParseLimit(v)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return limit
+	}
+
+	var store ratelimit.Store
+	switch config.Store {
+	case "memory":
+		store = ratelimit.NewMemoryStore()
+	case "database":
+		store = repository.NewRateLimitStore(db)
+	default:
+		log.Fatalf("unknown rate limit store %q", config.Store)
+	}
+
+	return ratelimit.NewLimiter(store, parse(config.Client), map[ratelimit.Class]ratelimit.Limit{
+		ratelimit.ClassAuth:  parse(config.Auth),
+		ratelimit.ClassMoney: parse(config.Money),
+		ratelimit.ClassRead:  parse(config.Read),
+	})
+}
+
+func webhookBackgroundWorker(u usecase.WebhookUseCase) {
+	ticker := time.NewTicker(properties.Props.Webhook.PollInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		u.ExecutePendingDeliveries(context.Background())
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/guilhermealvess/guicpay/infra/repository/sql/migrations"
+	"github.com/guilhermealvess/guicpay/internal/database"
+	"github.com/guilhermealvess/guicpay/internal/logger"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+const migrateUsage = `Usage:
+  guicpay migrate up                apply every pending migration
+  guicpay migrate down [--steps N]  roll back the last N migrations (default 1)
+  guicpay migrate status            list migrations and whether they are applied
+`
+
+// runMigrate handles "guicpay migrate <command>" and returns the process exit code.
+func runMigrate(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	db := database.NewConnectionDB()
+	defer db.Close()
+
+	switch args[0] {
+	case "up":
+		return migrateUp(db)
+
+	case "down":
+		flags := flag.NewFlagSet("down", flag.ExitOnError)
+		steps := flags.Int("steps", 1, "number of migrations to roll back")
+		flags.Parse(args[1:])
+		return migrateDown(db, *steps)
+
+	case "status":
+		return migrateStatus(db)
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown migrate command %q\n\n%s", args[0], migrateUsage)
+	return 2
+}
+
+func migrateUp(db *sqlx.DB) int {
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		logger.Logger.Error("Error in load migrations", zap.Error(err))
+		return 1
+	}
+
+	done, err := migrator.Up(context.Background())
+	for _, m := range done {
+		logger.Logger.Info("Migration applied", zap.Int64("version", m.Version), zap.String("name", m.Name))
+	}
+
+	if err != nil {
+		logger.Logger.Error("Error in apply migrations", zap.Error(err))
+		return 1
+	}
+
+	return 0
+}
+
+func migrateDown(db *sqlx.DB, steps int) int {
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		logger.Logger.Error("Error in load migrations", zap.Error(err))
+		return 1
+	}
+
+	done, err := migrator.Down(context.Background(), steps)
+	for _, m := range done {
+		logger.Logger.Info("Migration rolled back", zap.Int64("version", m.Version), zap.String("name", m.Name))
+	}
+
+	if err != nil {
+		logger.Logger.Error("Error in roll back migrations", zap.Error(err))
+		return 1
+	}
+
+	return 0
+}
+
+func migrateStatus(db *sqlx.DB) int {
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		logger.Logger.Error("Error in load migrations", zap.Error(err))
+		return 1
+	}
+
+	status, err := migrator.Status(context.Background())
+	if err != nil {
+		logger.Logger.Error("Error in migrations status", zap.Error(err))
+		return 1
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, s := range status {
+		state, appliedAt := "pending", ""
+		if s.Applied {
+			state, appliedAt = "applied", s.AppliedAt.Format(time.RFC3339)
+		}
+		if s.Drifted {
+			state = "checksum mismatch"
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+
+	if err := w.Flush(); err != nil {
+		return 1
+	}
+
+	return 0
+}
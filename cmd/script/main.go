@@ -165,7 +165,7 @@ func (p *processor) Authenticate(payload usecase.NewAccountInput) (string, error
 func (p *processor) Deposit(account Account) {
 	for {
 		payload := map[string]interface{}{
-			"value": gofakeit.Price(1, 1000),
+			"amount": fmt.Sprintf("%.2f", gofakeit.Price(1, 1000)),
 		}
 
 		res, err := p.client.Request(context.Background(), http.MethodPost, "/transactions/deposit", clienthttp.WithPayload(payload), clienthttp.WithUserAgent("guicpay-script"), clienthttp.WithToken(account.Token))
@@ -198,8 +198,8 @@ func (p *processor) Deposit(account Account) {
 func (p *processor) Transfer(payer, payee Account) {
 	for {
 		payload := map[string]interface{}{
-			"value": gofakeit.Price(1, 1000),
-			"payee": payee.ID,
+			"amount": fmt.Sprintf("%.2f", gofakeit.Price(1, 1000)),
+			"payee":  payee.ID,
 		}
 
 		res, err := p.client.Request(context.Background(), http.MethodPost, "/transactions/transfer", clienthttp.WithPayload(payload), clienthttp.WithUserAgent("guicpay-script"), clienthttp.WithToken(payer.Token))
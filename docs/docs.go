@@ -3,12 +3,16 @@
 package docs
 
 import (
-	"log"
-	"os"
+	_ "embed"
 
 	"github.com/swaggo/swag"
 )
 
+// swagger is embedded so the spec does not depend on the working directory of the binary.
+//
+//go:embed swagger.yaml
+var swagger string
+
 var SwaggerInfo_swagger = &swag.Spec{
 	Version:          "1.0",
 	Host:             "petstore.swagger.io",
@@ -17,17 +21,7 @@ var SwaggerInfo_swagger = &swag.Spec{
 	Title:            "Swagger Example API",
 	Description:      "This is a sample server Petstore server.",
 	InfoInstanceName: "swagger",
-	SwaggerTemplate:  read(),
-}
-
-func read() string {
-	filePath := "./docs/swagger.yaml"
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		log.Fatal("Error reading file:", err)
-	}
-	fileContent := string(content)
-	return fileContent
+	SwaggerTemplate:  swagger,
 }
 
 func init() {
@@ -3,6 +3,7 @@ package entity
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,7 +18,10 @@ const (
 
 type AccountStatus string
 
+// New accounts wait in AccountStatusPending until their email and phone are verified; meanwhile
+// they can receive money but not send it.
 const (
+	AccountStatusPending  AccountStatus = "PENDING_VERIFICATION"
 	AccountStatusActive   AccountStatus = "ACTIVE"
 	AccountStatusCanceled AccountStatus = "CANCELED"
 )
@@ -25,6 +29,7 @@ const (
 type Account struct {
 	ID              uuid.UUID
 	AccountType     AccountType
+	Role            Role
 	CustomerName    string
 	DocumentNumber  string
 	Email           string
@@ -34,52 +39,121 @@ type Account struct {
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 	Wallet          Wallet
+	events          []Event
 }
 
 func NewAccount(t AccountType, name, doc, email, pass, phone string) Account {
 	now := time.Now().UTC()
-	return Account{
+	account := Account{
 		ID:              uuid.New(),
 		AccountType:     t,
+		Role:            DefaultRole(t),
 		CustomerName:    name,
 		DocumentNumber:  doc,
 		Email:           email,
 		PasswordEncoded: generatePasswordEncoded(pass),
 		PhoneNumber:     phone,
-		Status:          AccountStatusActive,
+		Status:          AccountStatusPending,
 		CreatedAt:       now,
 		UpdatedAt:       now,
 		Wallet:          []*Transaction{},
 	}
+
+	account.record(AccountCreated{Account: account.copy(), Timestamp: now})
+	return account
 }
 
 func (a *Account) Deposit(v Money) (*Transaction, error) {
 	if a.Status == AccountStatusCanceled {
-		return nil, errors.Join(ErrUnprocessableEntity, NewDepositError("account canceled cant deposit", a.ID, v))
+		return nil, ErrAccountCanceled.Wrap(NewDepositError("account canceled cant deposit", a.ID, v))
 	}
 
 	t := factoryDepositTransaction(*a, v)
 	a.Wallet = append(a.Wallet, &t)
+	a.record(DepositCompleted{
+		Account:     a.copy(),
+		Transaction: t,
+		WalletSize:  len(a.Wallet),
+		Timestamp:   t.Timestamp,
+	})
 
 	return &t, nil
 }
 
 func (a *Account) Transfer(payee *Account, v Money) (*TransferOutput, error) {
 	if a.AccountType == Seller {
-		return nil, errors.Join(ErrUnprocessableEntity, NewTransferError("account seller cant make transfer", a.ID, v))
+		return nil, ErrSellerCannotTransfer.Wrap(NewTransferError("account seller cant make transfer", a.ID, v))
+	}
+
+	if a.Status == AccountStatusCanceled {
+		return nil, ErrAccountCanceled.Wrap(NewTransferError("account canceled cant make transfer", a.ID, v))
+	}
+
+	if payee.Status == AccountStatusCanceled {
+		return nil, ErrAccountCanceled.Wrap(NewTransferError("account canceled cant receive transfer", payee.ID, v))
+	}
+
+	if a.Status == AccountStatusPending {
+		return nil, ErrAccountUnverified.Wrap(NewTransferError("account pending verification cant make transfer", a.ID, v))
 	}
 
 	if a.ID == payee.ID {
-		return nil, errors.Join(ErrUnprocessableEntity, NewTransferError("account cant transfer to itself", a.ID, v))
+		return nil, ErrSelfTransfer.Wrap(NewTransferError("account cant transfer to itself", a.ID, v))
 	}
 
 	if a.Wallet.Balance() < v {
-		return nil, errors.Join(ErrUnprocessableEntity, NewTransferError("insuficient balance", a.ID, v))
+		return nil, ErrInsufficientBalance.Wrap(NewTransferError("insufficient balance", a.ID, v))
 	}
 
 	t1, t2 := factoryTransferTransactions(*a, *payee, v, a.Wallet.FindParent())
 	a.Wallet = append(a.Wallet, &t1)
 	payee.Wallet = append(payee.Wallet, &t2)
+	a.record(TransferCompleted{
+		Payer:           a.copy(),
+		Payee:           payee.copy(),
+		PayerTransfer:   t1,
+		PayeeTransfer:   t2,
+		CorrelatedID:    t1.CorrelatedID.UUID,
+		PayerWalletSize: len(a.Wallet),
+		PayeeWalletSize: len(payee.Wallet),
+		Timestamp:       t1.Timestamp,
+	})
+
+	return &TransferOutput{
+		Payer:        &t1,
+		Payee:        &t2,
+		CorrelatedID: t1.CorrelatedID.UUID,
+	}, nil
+}
+
+// Reverse gives back to the original payer a transfer this account received.
+func (a *Account) Reverse(payer *Account, received Transaction) (*TransferOutput, error) {
+	if received.TransactionType != TransferPayee || received.AccountID != a.ID || !received.CorrelatedID.Valid {
+		return nil, ErrNotReversible.Wrap(NewTransferError("transaction is not a transfer received by the account", a.ID, received.Amount))
+	}
+
+	if a.ID == payer.ID {
+		return nil, ErrSelfTransfer.Wrap(NewTransferError("account cant reverse to itself", a.ID, received.Amount))
+	}
+
+	if a.Wallet.Balance() < received.Amount {
+		return nil, ErrInsufficientBalance.Wrap(NewTransferError("insufficient balance", a.ID, received.Amount))
+	}
+
+	t1, t2 := factoryReversalTransactions(*a, *payer, received, a.Wallet.FindParent())
+	a.Wallet = append(a.Wallet, &t1)
+	payer.Wallet = append(payer.Wallet, &t2)
+	a.record(TransferReversed{
+		Payer:                a.copy(),
+		Payee:                payer.copy(),
+		PayerReversal:        t1,
+		PayeeReversal:        t2,
+		CorrelatedID:         t1.CorrelatedID.UUID,
+		TransferCorrelatedID: received.CorrelatedID.UUID,
+		PayerWalletSize:      len(a.Wallet),
+		PayeeWalletSize:      len(payer.Wallet),
+		Timestamp:            t1.Timestamp,
+	})
 
 	return &TransferOutput{
 		Payer:        &t1,
@@ -88,6 +162,42 @@ func (a *Account) Transfer(payee *Account, v Money) (*TransferOutput, error) {
 	}, nil
 }
 
+func (a *Account) ChangeStatus(status AccountStatus) error {
+	switch status {
+	case AccountStatusActive, AccountStatusCanceled:
+	default:
+		return errors.Join(ErrUnprocessableEntity, fmt.Errorf("invalid account status %q", status))
+	}
+
+	if a.Status == status {
+		return errors.Join(ErrUnprocessableEntity, fmt.Errorf("account status already %s", status))
+	}
+
+	now := time.Now().UTC()
+	a.record(AccountStatusChanged{AccountID: a.ID, From: a.Status, To: status, Timestamp: now})
+	a.Status = status
+	a.UpdatedAt = now
+	return nil
+}
+
+func (a *Account) ChangePassword(pass string) {
+	a.PasswordEncoded = generatePasswordEncoded(pass)
+	a.UpdatedAt = time.Now().UTC()
+}
+
+// Snapshot consolidates the wallet into a SNAPSHOT transaction.
+func (a *Account) Snapshot() *Transaction {
+	t := a.Wallet.Snapshot(a.ID)
+	a.record(SnapshotTaken{AccountID: a.ID, Snapshot: *t, Timestamp: t.Timestamp})
+	return t
+}
+
+func (a *Account) copy() Account {
+	account := *a
+	account.events = nil
+	return account
+}
+
 type TransferOutput struct {
 	Payer        *Transaction
 	Payee        *Transaction
@@ -97,6 +207,7 @@ type TransferOutput struct {
 type ResumeAccount struct {
 	ID              uuid.UUID
 	AccountType     AccountType
+	Role            Role
 	Email           string
 	Status          AccountStatus
 	PasswordEncoded Password
@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AccountListDefaultLimit = 50
+	AccountListMaxLimit     = 200
+)
+
+// AccountSummary is an account as listed: the balance is aggregated by the query and the wallet
+// is never loaded.
+type AccountSummary struct {
+	Account
+	Balance Money
+}
+
+type AccountFilter struct {
+	Types       []AccountType
+	Statuses    []AccountStatus
+	NamePrefix  string
+	EmailPrefix string
+	Cursor      *AccountCursor
+	Limit       int
+}
+
+func (f *AccountFilter) Validate() error {
+	if f.Limit <= 0 {
+		f.Limit = AccountListDefaultLimit
+	}
+
+	if f.Limit > AccountListMaxLimit {
+		f.Limit = AccountListMaxLimit
+	}
+
+	for _, t := range f.Types {
+		switch t {
+		case Personal, Seller:
+		default:
+			return errors.Join(ErrUnprocessableEntity, fmt.Errorf("account filter: invalid account type %q", t))
+		}
+	}
+
+	for _, s := range f.Statuses {
+		switch s {
+		case AccountStatusPending, AccountStatusActive, AccountStatusCanceled:
+		default:
+			return errors.Join(ErrUnprocessableEntity, fmt.Errorf("account filter: invalid status %q", s))
+		}
+	}
+
+	return nil
+}
+
+// AccountCursor points at the last account listed; accounts are listed newest first.
+type AccountCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+func (c AccountCursor) Encode() string {
+	return encodeCursor(c.CreatedAt, c.ID)
+}
+
+func ParseAccountCursor(s string) (*AccountCursor, error) {
+	createdAt, id, err := decodeCursor(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountCursor{CreatedAt: createdAt, ID: id}, nil
+}
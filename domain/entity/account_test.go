@@ -39,6 +39,21 @@ func TestAccount(t *testing.T) {
 
 	assert.NotEqual(t, uuid.Nil, personal.ID)
 	assert.NotEqual(t, uuid.Nil, seller.ID)
+	assert.Equal(t, AccountStatusPending, personal.Status)
+
+	t.Run("pending verification", func(t *testing.T) {
+		pa := Account(personal)
+		sa := Account(seller)
+		v := 10 * Real
+		_, err := pa.Deposit(v)
+		assert.NoError(t, err)
+
+		_, err = pa.Transfer(&sa, v)
+		assert.ErrorIs(t, err, ErrAccountUnverified)
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	personal.Status = AccountStatusActive
 
 	t.Run("deposit", func(t *testing.T) {
 		account := Account(personal)
@@ -85,6 +100,54 @@ func TestAccount(t *testing.T) {
 		assert.True(t, -1*v == output.Payer.Amount)
 		assert.True(t, v == output.Payee.Amount)
 	})
+
+	t.Run("canceled accounts cannot transfer", func(t *testing.T) {
+		pa := Account(personal)
+		sa := Account(seller)
+		v := 10 * Real
+		depositInAccount(t, &pa, v)
+
+		sa.Status = AccountStatusCanceled
+		_, err := pa.Transfer(&sa, v)
+		assert.ErrorIs(t, err, ErrAccountCanceled)
+
+		sa.Status = AccountStatusActive
+		pa.Status = AccountStatusCanceled
+		_, err = pa.Transfer(&sa, v)
+		assert.ErrorIs(t, err, ErrAccountCanceled)
+		assert.Equal(t, v, pa.Wallet.Balance())
+	})
+
+	t.Run("reverse", func(t *testing.T) {
+		pa := Account(personal)
+		sa := Account(seller)
+		v := 120 * Real
+		depositInAccount(t, &pa, v)
+
+		output, err := pa.Transfer(&sa, v)
+		assert.NoError(t, err)
+
+		reversal, err := sa.Reverse(&pa, *output.Payee)
+		assert.NoError(t, err)
+		assert.Equal(t, ReversalCorrelatedID(output.CorrelatedID), reversal.CorrelatedID)
+		assert.Equal(t, ReversalPayer, reversal.Payer.TransactionType)
+		assert.True(t, -1*v == reversal.Payer.Amount)
+		assert.True(t, v == reversal.Payee.Amount)
+		assert.Equal(t, Money(0), sa.Wallet.Balance())
+		assert.Equal(t, v, pa.Wallet.Balance())
+
+		_, err = sa.Reverse(&pa, *output.Payer)
+		assert.ErrorIs(t, err, ErrUnprocessableEntity)
+	})
+
+	t.Run("change status", func(t *testing.T) {
+		account := Account(personal)
+		assert.NoError(t, account.ChangeStatus(AccountStatusCanceled))
+		assert.Equal(t, AccountStatusCanceled, account.Status)
+
+		assert.ErrorIs(t, account.ChangeStatus(AccountStatusCanceled), ErrUnprocessableEntity)
+		assert.ErrorIs(t, account.ChangeStatus("BLOCKED"), ErrUnprocessableEntity)
+	})
 }
 
 func depositInAccount(t testing.TB, account *Account, v Money) {
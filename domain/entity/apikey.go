@@ -0,0 +1,110 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyPrefix starts every API key, so leaked keys are easy to spot.
+const APIKeyPrefix = "gpk_"
+
+// APIKey authenticates the backend of an account without a login. The key is its public prefix,
+// used to find it, followed by a secret of which only the hash is kept. A key is limited to its
+// scopes, always a subset of the permissions of the account role.
+type APIKey struct {
+	ID         uuid.UUID
+	AccountID  uuid.UUID
+	Name       string
+	Prefix     string
+	SecretHash string
+	Scopes     []Permission
+	CreatedAt  time.Time
+	LastUsedAt sql.NullTime
+	RevokedAt  sql.NullTime
+}
+
+// NewAPIKey issues a key with the scopes, returning it along with the key for the client, who sees
+// it this once.
+func NewAPIKey(accountID uuid.UUID, name string, scopes []Permission) (APIKey, string) {
+	id := make([]byte, 6)
+	rand.Read(id)
+	secret := make([]byte, 32)
+	rand.Read(secret)
+
+	prefix := APIKeyPrefix + hex.EncodeToString(id)
+	value := hex.EncodeToString(secret)
+	return APIKey{
+		ID:         uuid.New(),
+		AccountID:  accountID,
+		Name:       name,
+		Prefix:     prefix,
+		SecretHash: HashToken(value),
+		Scopes:     slices.Clone(scopes),
+		CreatedAt:  time.Now().UTC(),
+	}, prefix + "_" + value
+}
+
+// SplitAPIKey separates a key into the prefix it is stored under and its secret.
+func SplitAPIKey(key string) (prefix, secret string, ok bool) {
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return "", "", false
+	}
+
+	i := strings.LastIndexByte(key, '_')
+	if i < len(APIKeyPrefix) {
+		return "", "", false
+	}
+
+	return key[:i], key[i+1:], true
+}
+
+func (k *APIKey) Active() bool {
+	return !k.RevokedAt.Valid
+}
+
+// Matches compares the secret of a key with the hash of this one in constant time.
+func (k *APIKey) Matches(secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashToken(secret)), []byte(k.SecretHash)) == 1
+}
+
+// Scope renders the scopes the key still has under the role, as the scope claim of an access token.
+// A scope the role lost since the key was issued no longer applies.
+func (k *APIKey) Scope(role Role) string {
+	scopes := make([]string, 0, len(k.Scopes))
+	for _, p := range k.Scopes {
+		if role.Can(p) {
+			scopes = append(scopes, string(p))
+		}
+	}
+
+	return strings.Join(scopes, " ")
+}
+
+// ParseAPIKeyScopes checks the scopes asked for a key of an account with the role: each must be a
+// permission of the role, other than managing keys, so a key cannot issue keys.
+func ParseAPIKeyScopes(role Role, scopes []string) ([]Permission, error) {
+	if len(scopes) == 0 {
+		return nil, ErrInvalidArgument.New("api key needs at least one scope")
+	}
+
+	permissions := make([]Permission, 0, len(scopes))
+	for _, s := range scopes {
+		p := Permission(strings.ToLower(strings.TrimSpace(s)))
+		if p == PermissionAPIKeyManage || !role.Can(p) {
+			return nil, ErrInvalidArgument.Errorf("scope %q is not allowed for api keys of a %s account", s, role)
+		}
+
+		if !slices.Contains(permissions, p) {
+			permissions = append(permissions, p)
+		}
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKey(t *testing.T) {
+	t.Run("key", func(t *testing.T) {
+		key, value := NewAPIKey(uuid.New(), "backend", []Permission{PermissionAccountRead})
+
+		prefix, secret, ok := SplitAPIKey(value)
+		require.True(t, ok)
+		assert.Equal(t, key.Prefix, prefix)
+		assert.True(t, key.Matches(secret))
+		assert.False(t, key.Matches(secret+"0"))
+		assert.NotContains(t, key.SecretHash, secret)
+
+		_, _, ok = SplitAPIKey("rt_" + secret)
+		assert.False(t, ok)
+	})
+
+	t.Run("scopes", func(t *testing.T) {
+		scopes, err := ParseAPIKeyScopes(RoleSeller, []string{"account:read", "TRANSACTION:DEPOSIT", "account:read"})
+		require.NoError(t, err)
+		assert.Equal(t, []Permission{PermissionAccountRead, PermissionDeposit}, scopes)
+
+		_, err = ParseAPIKeyScopes(RoleSeller, []string{"transaction:transfer"})
+		assert.ErrorIs(t, err, ErrInvalidArgument)
+		_, err = ParseAPIKeyScopes(RoleSeller, []string{"apikey:manage"})
+		assert.ErrorIs(t, err, ErrInvalidArgument)
+		_, err = ParseAPIKeyScopes(RoleSeller, nil)
+		assert.ErrorIs(t, err, ErrInvalidArgument)
+
+		// scopes the role lost no longer apply
+		key, _ := NewAPIKey(uuid.New(), "backend", []Permission{PermissionAccountRead, PermissionTransfer})
+		assert.Equal(t, "account:read transaction:transfer", key.Scope(RoleCustomer))
+		assert.Equal(t, "account:read", key.Scope(RoleSeller))
+	})
+}
@@ -1,17 +1,150 @@
 package entity
 
 import (
-	"fmt"
-
+	"database/sql"
 	"errors"
+	"fmt"
 
 	"github.com/google/uuid"
 )
 
+// Error kinds tell the adapters how to answer a failure: each maps to one HTTP status and one gRPC code.
 var (
 	ErrUnprocessableEntity = errors.New("unprocessable entity")
+	ErrNotFound            = errors.New("not found")
+	ErrConflict            = errors.New("conflict")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrForbidden           = errors.New("forbidden")
+	ErrTooManyRequests     = errors.New("too many requests")
+	ErrInternal            = errors.New("internal")
+)
+
+// The error catalog. Codes are part of the public API: clients branch on them, so they never change
+// once released, while titles and details are only meant for humans.
+var (
+	ErrInvalidArgument      = NewErrorCode("INVALID_ARGUMENT", ErrUnprocessableEntity, "Invalid argument")
+	ErrInsufficientBalance  = NewErrorCode("INSUFFICIENT_BALANCE", ErrUnprocessableEntity, "Insufficient balance")
+	ErrSellerCannotTransfer = NewErrorCode("SELLER_CANNOT_TRANSFER", ErrUnprocessableEntity, "Seller accounts cannot transfer")
+	ErrSelfTransfer         = NewErrorCode("SELF_TRANSFER", ErrUnprocessableEntity, "Account cannot transfer to itself")
+	ErrAccountCanceled      = NewErrorCode("ACCOUNT_CANCELED", ErrUnprocessableEntity, "Account is canceled")
+	ErrNotReversible        = NewErrorCode("NOT_REVERSIBLE", ErrUnprocessableEntity, "Transaction cannot be reversed")
+	ErrAlreadyReversed      = NewErrorCode("ALREADY_REVERSED", ErrUnprocessableEntity, "Transfer already reversed")
+	ErrNothingToSnapshot    = NewErrorCode("NOTHING_TO_SNAPSHOT", ErrUnprocessableEntity, "Wallet has no transactions to snapshot")
+	ErrTOTPNotEnrolled      = NewErrorCode("TOTP_NOT_ENROLLED", ErrUnprocessableEntity, "Two-factor authentication not enrolled")
+	ErrInvalidVerification  = NewErrorCode("INVALID_VERIFICATION_CODE", ErrUnprocessableEntity, "Invalid verification code")
+	ErrResourceNotFound     = NewErrorCode("NOT_FOUND", ErrNotFound, "Resource not found")
+	ErrAccountNotFound      = NewErrorCode("ACCOUNT_NOT_FOUND", ErrNotFound, "Account not found")
+	ErrTransferNotFound     = NewErrorCode("TRANSFER_NOT_FOUND", ErrNotFound, "Transfer not found")
+	ErrWebhookNotFound      = NewErrorCode("WEBHOOK_NOT_FOUND", ErrNotFound, "Webhook not found")
+	ErrAPIKeyNotFound       = NewErrorCode("API_KEY_NOT_FOUND", ErrNotFound, "API key not found")
+	ErrDuplicateEmail       = NewErrorCode("DUPLICATE_EMAIL", ErrConflict, "Email already registered")
+	ErrDuplicateDocument    = NewErrorCode("DUPLICATE_DOCUMENT", ErrConflict, "Document number already registered")
+	ErrConcurrentUpdate     = NewErrorCode("CONCURRENT_UPDATE", ErrConflict, "Wallet changed concurrently")
+	ErrDuplicateResource    = NewErrorCode("CONFLICT", ErrConflict, "Resource already exists")
+	ErrTOTPAlreadyEnabled   = NewErrorCode("TOTP_ALREADY_ENABLED", ErrConflict, "Two-factor authentication already enabled")
+	ErrAlreadyVerified      = NewErrorCode("ALREADY_VERIFIED", ErrConflict, "Already verified")
+	ErrInvalidCredentials   = NewErrorCode("INVALID_CREDENTIALS", ErrUnauthorized, "Invalid credentials")
+	ErrUnauthenticated      = NewErrorCode("UNAUTHENTICATED", ErrUnauthorized, "Authentication required")
+	ErrInvalidRefreshToken  = NewErrorCode("INVALID_REFRESH_TOKEN", ErrUnauthorized, "Invalid refresh token")
+	ErrRefreshTokenReused   = NewErrorCode("REFRESH_TOKEN_REUSED", ErrUnauthorized, "Refresh token already used")
+	ErrOTPRequired          = NewErrorCode("OTP_REQUIRED", ErrUnauthorized, "One-time password required")
+	ErrInvalidOTP           = NewErrorCode("INVALID_OTP", ErrUnauthorized, "Invalid one-time password")
+	ErrInvalidResetToken    = NewErrorCode("INVALID_RESET_TOKEN", ErrUnauthorized, "Invalid password reset token")
+	ErrAuthorizationDenied  = NewErrorCode("AUTHORIZATION_DENIED", ErrForbidden, "Transaction not authorized")
+	ErrPermissionDenied     = NewErrorCode("PERMISSION_DENIED", ErrForbidden, "Permission denied")
+	ErrStepUpRequired       = NewErrorCode("STEP_UP_REQUIRED", ErrForbidden, "One-time password required for this transfer")
+	ErrAccountUnverified    = NewErrorCode("ACCOUNT_UNVERIFIED", ErrForbidden, "Account email and phone not verified")
+	ErrLoginThrottled       = NewErrorCode("LOGIN_THROTTLED", ErrTooManyRequests, "Too many failed login attempts")
+	ErrLoginLocked          = NewErrorCode("LOGIN_LOCKED", ErrTooManyRequests, "Login temporarily locked")
+	ErrRateLimited          = NewErrorCode("RATE_LIMITED", ErrTooManyRequests, "Too many requests")
+	ErrVerificationLocked   = NewErrorCode("VERIFICATION_LOCKED", ErrTooManyRequests, "Too many wrong verification codes")
+	ErrVerificationCooldown = NewErrorCode("VERIFICATION_RESEND_TOO_SOON", ErrTooManyRequests, "Verification code sent recently")
+	ErrInternalServer       = NewErrorCode("INTERNAL", ErrInternal, "Internal error")
 )
 
+// ErrorCode is one entry of the error catalog. It is itself an error so callers can test for it
+// with errors.Is(err, entity.ErrInsufficientBalance).
+type ErrorCode struct {
+	Code  string
+	Kind  error
+	Title string
+}
+
+func NewErrorCode(code string, kind error, title string) *ErrorCode {
+	return &ErrorCode{Code: code, Kind: kind, Title: title}
+}
+
+func (c *ErrorCode) Error() string {
+	return c.Code
+}
+
+// New builds an occurrence of the code; detail describes what went wrong this time.
+func (c *ErrorCode) New(detail string) *Error {
+	return &Error{ErrorCode: c, Detail: detail}
+}
+
+func (c *ErrorCode) Errorf(format string, args ...any) *Error {
+	return c.New(fmt.Sprintf(format, args...))
+}
+
+// Wrap builds an occurrence caused by err, which stays reachable through errors.Is and errors.As.
+func (c *ErrorCode) Wrap(err error) *Error {
+	return &Error{ErrorCode: c, Detail: err.Error(), cause: err}
+}
+
+// Error is a catalogued failure: it matches its code, the code's kind and its cause.
+type Error struct {
+	*ErrorCode
+	Detail string
+	cause  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Detail)
+}
+
+func (e *Error) Unwrap() []error {
+	errs := []error{e.ErrorCode, e.Kind}
+	if e.cause != nil {
+		errs = append(errs, e.cause)
+	}
+
+	return errs
+}
+
+// ErrorOf finds the catalogued error inside err. Errors outside the catalog are classified by
+// kind, so errors.Join(ErrUnprocessableEntity, ...) still reads as INVALID_ARGUMENT, and anything
+// unknown is INTERNAL.
+func ErrorOf(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, ErrNotFound):
+		return ErrResourceNotFound.Wrap(err)
+
+	case errors.Is(err, ErrUnprocessableEntity):
+		return ErrInvalidArgument.Wrap(err)
+
+	case errors.Is(err, ErrConflict):
+		return ErrDuplicateResource.Wrap(err)
+
+	case errors.Is(err, ErrUnauthorized):
+		return ErrUnauthenticated.Wrap(err)
+
+	case errors.Is(err, ErrForbidden):
+		return ErrPermissionDenied.Wrap(err)
+
+	case errors.Is(err, ErrTooManyRequests):
+		return ErrRateLimited.Wrap(err)
+
+	default:
+		return ErrInternalServer.Wrap(err)
+	}
+}
+
 type TransactionError struct {
 	Message         string
 	AccountID       uuid.UUID
@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorCatalog(t *testing.T) {
+	t.Run("matches code, kind and cause", func(t *testing.T) {
+		payer := NewAccount(Personal, "payer", "00000000001", "payer@example.com", "PASSWORD", "+5511999999999")
+		payee := NewAccount(Personal, "payee", "00000000002", "payee@example.com", "PASSWORD", "+5511999999999")
+		payer.Status = AccountStatusActive
+
+		_, err := payer.Transfer(&payee, Real)
+		assert.ErrorIs(t, err, ErrInsufficientBalance)
+		assert.ErrorIs(t, err, ErrUnprocessableEntity)
+		assert.NotErrorIs(t, err, ErrSellerCannotTransfer)
+
+		var transactionErr TransactionError
+		require.ErrorAs(t, err, &transactionErr)
+		assert.Equal(t, payer.ID, transactionErr.AccountID)
+
+		e := ErrorOf(fmt.Errorf("usecase: %w", err))
+		assert.Equal(t, "INSUFFICIENT_BALANCE", e.Code)
+	})
+
+	t.Run("classifies errors outside the catalog by kind", func(t *testing.T) {
+		for err, code := range map[error]string{
+			errors.Join(ErrUnprocessableEntity, errors.New("bad")): "INVALID_ARGUMENT",
+			errors.Join(ErrNotFound, errors.New("missing")):        "NOT_FOUND",
+			fmt.Errorf("database: %w", sql.ErrNoRows):              "NOT_FOUND",
+			errors.Join(ErrTooManyRequests, errors.New("slow")):    "RATE_LIMITED",
+			errors.New("boom"): "INTERNAL",
+		} {
+			e := ErrorOf(err)
+			assert.Equal(t, code, e.Code, err.Error())
+			assert.ErrorIs(t, e, err)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventAccountCreated    = "account.created"
+	EventDepositCompleted  = "deposit.completed"
+	EventTransferCompleted = "transfer.completed"
+	EventSnapshotTaken     = "snapshot.taken"
+	EventTransferReversed  = "transfer.reversed"
+	EventStatusChanged     = "account.status_changed"
+)
+
+type Event interface {
+	EventName() string
+	OccurredAt() time.Time
+}
+
+type AccountCreated struct {
+	Account   Account
+	Timestamp time.Time
+}
+
+func (e AccountCreated) EventName() string     { return EventAccountCreated }
+func (e AccountCreated) OccurredAt() time.Time { return e.Timestamp }
+
+type DepositCompleted struct {
+	Account     Account
+	Transaction Transaction
+	WalletSize  int
+	Timestamp   time.Time
+}
+
+func (e DepositCompleted) EventName() string     { return EventDepositCompleted }
+func (e DepositCompleted) OccurredAt() time.Time { return e.Timestamp }
+
+type TransferCompleted struct {
+	Payer           Account
+	Payee           Account
+	PayerTransfer   Transaction
+	PayeeTransfer   Transaction
+	CorrelatedID    uuid.UUID
+	PayerWalletSize int
+	PayeeWalletSize int
+	Timestamp       time.Time
+}
+
+func (e TransferCompleted) EventName() string     { return EventTransferCompleted }
+func (e TransferCompleted) OccurredAt() time.Time { return e.Timestamp }
+
+type TransferReversed struct {
+	Payer                Account
+	Payee                Account
+	PayerReversal        Transaction
+	PayeeReversal        Transaction
+	CorrelatedID         uuid.UUID
+	TransferCorrelatedID uuid.UUID
+	PayerWalletSize      int
+	PayeeWalletSize      int
+	Timestamp            time.Time
+}
+
+func (e TransferReversed) EventName() string     { return EventTransferReversed }
+func (e TransferReversed) OccurredAt() time.Time { return e.Timestamp }
+
+type AccountStatusChanged struct {
+	AccountID uuid.UUID
+	From      AccountStatus
+	To        AccountStatus
+	Timestamp time.Time
+}
+
+func (e AccountStatusChanged) EventName() string     { return EventStatusChanged }
+func (e AccountStatusChanged) OccurredAt() time.Time { return e.Timestamp }
+
+type SnapshotTaken struct {
+	AccountID uuid.UUID
+	Snapshot  Transaction
+	Timestamp time.Time
+}
+
+func (e SnapshotTaken) EventName() string     { return EventSnapshotTaken }
+func (e SnapshotTaken) OccurredAt() time.Time { return e.Timestamp }
+
+func (a *Account) record(e Event) {
+	a.events = append(a.events, e)
+}
+
+// PullEvents returns the events recorded by the account operations and clears them.
+func (a *Account) PullEvents() []Event {
+	events := a.events
+	a.events = nil
+	return events
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountEvents(t *testing.T) {
+	personal := factoryFakePersonalAccount(t)
+	seller := factoryFakeSellerAccount(t)
+
+	events := personal.PullEvents()
+	assert.Len(t, events, 1)
+	assert.Equal(t, EventAccountCreated, events[0].EventName())
+	assert.Empty(t, personal.PullEvents())
+	seller.PullEvents()
+
+	t.Run("deposit", func(t *testing.T) {
+		account := personal
+		tr, err := account.Deposit(100 * Real)
+		assert.NoError(t, err)
+
+		events := account.PullEvents()
+		assert.Len(t, events, 1)
+		e, ok := events[0].(DepositCompleted)
+		assert.True(t, ok)
+		assert.Equal(t, tr.ID, e.Transaction.ID)
+		assert.Equal(t, 1, e.WalletSize)
+	})
+
+	t.Run("transfer", func(t *testing.T) {
+		payer, payee := personal, seller
+		depositInAccount(t, &payer, 100*Real)
+		output, err := payer.Transfer(&payee, 40*Real)
+		assert.NoError(t, err)
+
+		events := payer.PullEvents()
+		assert.Len(t, events, 1)
+		e, ok := events[0].(TransferCompleted)
+		assert.True(t, ok)
+		assert.Equal(t, output.CorrelatedID, e.CorrelatedID)
+		assert.Equal(t, payee.ID, e.Payee.ID)
+		assert.Equal(t, 40*Real, e.PayeeTransfer.Amount)
+		assert.Empty(t, payee.PullEvents())
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		account := personal
+		depositInAccount(t, &account, 100*Real)
+		snapshot := account.Snapshot()
+
+		events := account.PullEvents()
+		assert.Len(t, events, 1)
+		e, ok := events[0].(SnapshotTaken)
+		assert.True(t, ok)
+		assert.Equal(t, snapshot.ID, e.Snapshot.ID)
+		assert.Equal(t, 100*Real, e.Snapshot.Amount)
+	})
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoginAttempts counts the recent failed logins of a key: the email tried, whether an account has it
+// or not, the client IP the attempts came from, or the account a wrong one-time password was sent
+// for.
+type LoginAttempts struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+}
+
+// LoginPolicy throttles a key: after FreeAttempts failures each further attempt waits BaseDelay,
+// doubling every failure, and LockAfter failures lock the key for Lockout. Failures are forgotten
+// once Lockout passes without a new one.
+type LoginPolicy struct {
+	FreeAttempts int
+	LockAfter    int
+	BaseDelay    time.Duration
+	Lockout      time.Duration
+}
+
+func LoginEmailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func LoginIPKey(ip string) string {
+	return "ip:" + ip
+}
+
+func LoginOTPKey(accountID uuid.UUID) string {
+	return "otp:" + accountID.String()
+}
+
+// Locked reports whether the key reached the lockout, which lasts until BlockedUntil.
+func (a LoginAttempts) Locked(p LoginPolicy) bool {
+	return a.Failures >= p.LockAfter
+}
+
+// BlockedUntil is the instant the key may try again, zero when it is not throttled.
+func (a LoginAttempts) BlockedUntil(p LoginPolicy) time.Time {
+	if a.Locked(p) {
+		return a.LastFailureAt.Add(p.Lockout)
+	}
+
+	if a.Failures <= p.FreeAttempts {
+		return time.Time{}
+	}
+
+	delay := p.BaseDelay
+	for i := p.FreeAttempts + 1; i < a.Failures && delay < p.Lockout; i++ {
+		delay *= 2
+	}
+
+	return a.LastFailureAt.Add(min(delay, p.Lockout))
+}
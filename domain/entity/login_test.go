@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginAttempts(t *testing.T) {
+	now := time.Now()
+	policy := LoginPolicy{FreeAttempts: 3, LockAfter: 6, BaseDelay: time.Second, Lockout: 10 * time.Second}
+
+	t.Run("progressive delay", func(t *testing.T) {
+		for failures, delay := range map[int]time.Duration{
+			1: 0,
+			3: 0,
+			4: time.Second,
+			5: 2 * time.Second,
+		} {
+			until := LoginAttempts{Failures: failures, LastFailureAt: now}.BlockedUntil(policy)
+			if delay == 0 {
+				assert.True(t, until.IsZero(), failures)
+				continue
+			}
+
+			assert.Equal(t, now.Add(delay), until, failures)
+		}
+	})
+
+	t.Run("delay never exceeds the lockout", func(t *testing.T) {
+		long := LoginPolicy{FreeAttempts: 0, LockAfter: 100, BaseDelay: time.Second, Lockout: time.Minute}
+		a := LoginAttempts{Failures: 99, LastFailureAt: now}
+		assert.Equal(t, now.Add(time.Minute), a.BlockedUntil(long))
+		assert.False(t, a.Locked(long))
+	})
+
+	t.Run("lockout", func(t *testing.T) {
+		a := LoginAttempts{Failures: 6, LastFailureAt: now}
+		assert.True(t, a.Locked(policy))
+		assert.Equal(t, now.Add(10*time.Second), a.BlockedUntil(policy))
+	})
+
+	t.Run("keys", func(t *testing.T) {
+		assert.Equal(t, "email:jane@example.com", LoginEmailKey(" Jane@Example.com "))
+		assert.Equal(t, "ip:203.0.113.7", LoginIPKey("203.0.113.7"))
+	})
+}
@@ -0,0 +1,100 @@
+package entity
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Role decides what an account may do through the API. Customers and sellers get the role of their
+// account type when they sign up; support and admin are staff roles only an admin can grant.
+type Role string
+
+const (
+	RoleCustomer Role = "CUSTOMER"
+	RoleSeller   Role = "SELLER"
+	RoleSupport  Role = "SUPPORT"
+	RoleAdmin    Role = "ADMIN"
+)
+
+// Permission is one action of the API. Access tokens carry the permissions of the account role as
+// their space separated scope claim.
+type Permission string
+
+const (
+	PermissionAccountRead     Permission = "account:read"
+	PermissionDeposit         Permission = "transaction:deposit"
+	PermissionTransfer        Permission = "transaction:transfer"
+	PermissionWebhookManage   Permission = "webhook:manage"
+	PermissionAPIKeyManage    Permission = "apikey:manage"
+	PermissionAccountsList    Permission = "accounts:list"
+	PermissionAccountsManage  Permission = "accounts:manage"
+	PermissionTransferReverse Permission = "transfers:reverse"
+	PermissionRolesManage     Permission = "roles:manage"
+	PermissionAuditRead       Permission = "audit:read"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleCustomer: {PermissionAccountRead, PermissionDeposit, PermissionTransfer, PermissionWebhookManage, PermissionAPIKeyManage},
+	RoleSeller:   {PermissionAccountRead, PermissionDeposit, PermissionWebhookManage, PermissionAPIKeyManage},
+	RoleSupport:  {PermissionAccountRead, PermissionAccountsList},
+	RoleAdmin: {
+		PermissionAccountRead, PermissionAccountsList, PermissionAccountsManage,
+		PermissionTransferReverse, PermissionRolesManage, PermissionAuditRead,
+	},
+}
+
+func ParseRole(v string) (Role, error) {
+	role := Role(strings.ToUpper(v))
+	if _, ok := rolePermissions[role]; !ok {
+		return "", ErrInvalidArgument.Errorf("invalid role %q", v)
+	}
+
+	return role, nil
+}
+
+// DefaultRole is the role of a new account of the type.
+func DefaultRole(t AccountType) Role {
+	if t == Seller {
+		return RoleSeller
+	}
+
+	return RoleCustomer
+}
+
+func (r Role) Permissions() []Permission {
+	return rolePermissions[r]
+}
+
+func (r Role) Can(p Permission) bool {
+	return slices.Contains(rolePermissions[r], p)
+}
+
+// Scope renders the permissions of the role as an OAuth scope claim.
+func (r Role) Scope() string {
+	scopes := make([]string, 0, len(rolePermissions[r]))
+	for _, p := range rolePermissions[r] {
+		scopes = append(scopes, string(p))
+	}
+
+	return strings.Join(scopes, " ")
+}
+
+// GrantedBy reports whether a scope claim includes the permission.
+func (p Permission) GrantedBy(scope string) bool {
+	return slices.Contains(strings.Fields(scope), string(p))
+}
+
+func (a *Account) ChangeRole(role Role) error {
+	if _, ok := rolePermissions[role]; !ok {
+		return ErrInvalidArgument.Errorf("invalid role %q", role)
+	}
+
+	if a.Role == role {
+		return ErrInvalidArgument.Errorf("account role already %s", role)
+	}
+
+	a.Role = role
+	a.UpdatedAt = time.Now().UTC()
+	return nil
+}
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRole(t *testing.T) {
+	t.Run("parse", func(t *testing.T) {
+		role, err := ParseRole("support")
+		require.NoError(t, err)
+		assert.Equal(t, RoleSupport, role)
+
+		_, err = ParseRole("root")
+		assert.ErrorIs(t, err, ErrInvalidArgument)
+	})
+
+	t.Run("default role follows the account type", func(t *testing.T) {
+		assert.Equal(t, RoleCustomer, NewAccount(Personal, "name", "doc", "email", "pass", "phone").Role)
+		assert.Equal(t, RoleSeller, NewAccount(Seller, "name", "doc", "email", "pass", "phone").Role)
+	})
+
+	t.Run("permissions", func(t *testing.T) {
+		assert.True(t, RoleCustomer.Can(PermissionTransfer))
+		assert.False(t, RoleSeller.Can(PermissionTransfer))
+		assert.False(t, RoleCustomer.Can(PermissionAccountsList))
+		assert.True(t, RoleSupport.Can(PermissionAccountsList))
+		assert.False(t, RoleSupport.Can(PermissionAccountsManage))
+		assert.True(t, RoleAdmin.Can(PermissionRolesManage))
+	})
+
+	t.Run("scope", func(t *testing.T) {
+		scope := RoleSupport.Scope()
+		assert.Equal(t, "account:read accounts:list", scope)
+		assert.True(t, PermissionAccountsList.GrantedBy(scope))
+		assert.False(t, PermissionAccountsManage.GrantedBy(scope))
+		assert.False(t, Permission("accounts").GrantedBy(scope))
+	})
+
+	t.Run("change role", func(t *testing.T) {
+		account := NewAccount(Personal, "name", "doc", "email", "pass", "phone")
+		require.NoError(t, account.ChangeRole(RoleAdmin))
+		assert.Equal(t, RoleAdmin, account.Role)
+
+		assert.ErrorIs(t, account.ChangeRole(RoleAdmin), ErrInvalidArgument)
+		assert.ErrorIs(t, account.ChangeRole(Role("ROOT")), ErrInvalidArgument)
+	})
+}
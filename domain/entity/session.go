@@ -0,0 +1,100 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is one link of the rotation chain of a login session, its family. Only the hash of
+// the token handed to the client is kept. Refreshing uses the token up and issues the next one of
+// the same family; a used token presented again was leaked, so the whole family is revoked.
+type RefreshToken struct {
+	ID        uuid.UUID
+	FamilyID  uuid.UUID
+	AccountID uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    sql.NullTime
+	RevokedAt sql.NullTime
+}
+
+// NewRefreshToken issues a token of the family, returning it along with the value for the client.
+func NewRefreshToken(accountID, familyID uuid.UUID, ttl time.Duration) (RefreshToken, string) {
+	now := time.Now().UTC()
+	value := generateRefreshToken()
+	return RefreshToken{
+		ID:        uuid.New(),
+		FamilyID:  familyID,
+		AccountID: accountID,
+		TokenHash: HashToken(value),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, value
+}
+
+// Active reports whether the token can still be exchanged; a used token is not active either.
+func (t *RefreshToken) Active(now time.Time) bool {
+	return !t.UsedAt.Valid && !t.RevokedAt.Valid && now.Before(t.ExpiresAt)
+}
+
+func HashToken(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateRefreshToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return "rt_" + hex.EncodeToString(b)
+}
+
+// RevokedToken stops an access token id (jti) or a whole session id (sid) from being accepted.
+// It only needs to be kept until ExpiresAt, when every token it covers has expired anyway.
+type RevokedToken struct {
+	ID        uuid.UUID
+	ExpiresAt time.Time
+	RevokedAt time.Time
+}
+
+func NewRevokedToken(id uuid.UUID, expiresAt time.Time) RevokedToken {
+	return RevokedToken{
+		ID:        id,
+		ExpiresAt: expiresAt.UTC(),
+		RevokedAt: time.Now().UTC(),
+	}
+}
+
+// PasswordResetToken lets whoever reads the account notifications set a new password once, before
+// it expires. An account has at most one: asking for another replaces it. Only its hash is kept.
+type PasswordResetToken struct {
+	AccountID uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    sql.NullTime
+}
+
+// NewPasswordResetToken issues a reset token, returning it along with the value sent to the account.
+func NewPasswordResetToken(accountID uuid.UUID, ttl time.Duration) (PasswordResetToken, string) {
+	now := time.Now().UTC()
+	b := make([]byte, 32)
+	rand.Read(b)
+	value := "pr_" + hex.EncodeToString(b)
+
+	return PasswordResetToken{
+		AccountID: accountID,
+		TokenHash: HashToken(value),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, value
+}
+
+func (t *PasswordResetToken) Active(now time.Time) bool {
+	return !t.UsedAt.Valid && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,146 @@
+package entity
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	StatementDefaultLimit = 50
+	StatementMaxLimit     = 200
+)
+
+type Counterparty struct {
+	AccountID    uuid.UUID
+	AccountType  AccountType
+	CustomerName string
+}
+
+// StatementLine is a wallet transaction enriched with the account balance right after it
+// and, for transfers, the account on the other side.
+type StatementLine struct {
+	Transaction
+	Balance      Money
+	Counterparty *Counterparty
+}
+
+type StatementFilter struct {
+	From      time.Time
+	To        time.Time
+	Types     []TransactionType
+	MinAmount Money
+	MaxAmount Money
+	Cursor    *StatementCursor
+	Limit     int
+}
+
+func (f *StatementFilter) Validate() error {
+	if f.Limit <= 0 {
+		f.Limit = StatementDefaultLimit
+	}
+
+	if f.Limit > StatementMaxLimit {
+		f.Limit = StatementMaxLimit
+	}
+
+	if !f.From.IsZero() && !f.To.IsZero() && f.From.After(f.To) {
+		return errors.Join(ErrUnprocessableEntity, errors.New("statement filter: from must be before to"))
+	}
+
+	if f.MinAmount < 0 || f.MaxAmount < 0 || (f.MaxAmount > 0 && f.MinAmount > f.MaxAmount) {
+		return errors.Join(ErrUnprocessableEntity, errors.New("statement filter: invalid amount range"))
+	}
+
+	for _, t := range f.Types {
+		switch t {
+		case Deposit, TransferPayer, TransferPayee, ReversalPayer, ReversalPayee:
+		default:
+			return errors.Join(ErrUnprocessableEntity, fmt.Errorf("statement filter: invalid transaction type %q", t))
+		}
+	}
+
+	return nil
+}
+
+// StatementCursor points at the last line returned; the next page starts right after it.
+type StatementCursor struct {
+	Timestamp time.Time
+	ID        uuid.UUID
+}
+
+func (c StatementCursor) Encode() string {
+	return encodeCursor(c.Timestamp, c.ID)
+}
+
+func ParseStatementCursor(s string) (*StatementCursor, error) {
+	timestamp, id, err := decodeCursor(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StatementCursor{Timestamp: timestamp, ID: id}, nil
+}
+
+// FeedCursor points at the last transaction of an account feed a client received.
+type FeedCursor struct {
+	Sequence int64
+}
+
+func (c FeedCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("feed|%d", c.Sequence)))
+}
+
+func ParseFeedCursor(s string) (*FeedCursor, error) {
+	invalid := errors.Join(ErrUnprocessableEntity, errors.New("invalid cursor"))
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, invalid
+	}
+
+	seq, ok := strings.CutPrefix(string(raw), "feed|")
+	if !ok {
+		return nil, invalid
+	}
+
+	var cursor FeedCursor
+	if _, err := fmt.Sscanf(seq, "%d", &cursor.Sequence); err != nil || cursor.Sequence < 0 {
+		return nil, invalid
+	}
+
+	return &cursor, nil
+}
+
+func encodeCursor(t time.Time, id uuid.UUID) string {
+	raw := fmt.Sprintf("%d|%s", t.UnixNano(), id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(s string) (time.Time, uuid.UUID, error) {
+	invalid := errors.Join(ErrUnprocessableEntity, errors.New("invalid cursor"))
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return time.Time{}, uuid.Nil, invalid
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 2 {
+		return time.Time{}, uuid.Nil, invalid
+	}
+
+	var nanos int64
+	if _, err := fmt.Sscanf(parts[0], "%d", &nanos); err != nil {
+		return time.Time{}, uuid.Nil, invalid
+	}
+
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return time.Time{}, uuid.Nil, invalid
+	}
+
+	return time.Unix(0, nanos).UTC(), id, nil
+}
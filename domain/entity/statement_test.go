@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatement(t *testing.T) {
+	t.Run("cursor", func(t *testing.T) {
+		cursor := StatementCursor{Timestamp: time.Now().UTC(), ID: uuid.New()}
+		parsed, err := ParseStatementCursor(cursor.Encode())
+
+		assert.NoError(t, err)
+		assert.Equal(t, cursor.ID, parsed.ID)
+		assert.True(t, cursor.Timestamp.Equal(parsed.Timestamp))
+
+		_, err = ParseStatementCursor("not-a-cursor")
+		assert.True(t, errors.Is(err, ErrUnprocessableEntity))
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		filter := StatementFilter{}
+		assert.NoError(t, filter.Validate())
+		assert.Equal(t, StatementDefaultLimit, filter.Limit)
+
+		filter = StatementFilter{Limit: 10 * StatementMaxLimit}
+		assert.NoError(t, filter.Validate())
+		assert.Equal(t, StatementMaxLimit, filter.Limit)
+
+		now := time.Now()
+		filter = StatementFilter{From: now, To: now.Add(-time.Hour)}
+		assert.True(t, errors.Is(filter.Validate(), ErrUnprocessableEntity))
+
+		filter = StatementFilter{MinAmount: 10 * Real, MaxAmount: 5 * Real}
+		assert.True(t, errors.Is(filter.Validate(), ErrUnprocessableEntity))
+
+		filter = StatementFilter{Types: []TransactionType{Snapshot}}
+		assert.True(t, errors.Is(filter.Validate(), ErrUnprocessableEntity))
+	})
+}
@@ -0,0 +1,161 @@
+package entity
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RFC 6238 parameters every authenticator app supports.
+const (
+	totpPeriod        = 30 * time.Second
+	totpDigits        = 6
+	totpSkew          = 1
+	recoveryCodeCount = 10
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTP is the authenticator app credential of an account. It is pending until a first code confirms
+// the account holder saved the secret; only then login and transfers start asking for codes.
+// Recovery codes are kept hashed and each works once, in place of a code.
+type TOTP struct {
+	AccountID     uuid.UUID
+	Secret        string
+	RecoveryCodes []string
+	LastStep      int64
+	ConfirmedAt   sql.NullTime
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// NewTOTP generates a pending credential, returning it along with the recovery codes for the account
+// holder, who sees them this once.
+func NewTOTP(accountID uuid.UUID) (TOTP, []string) {
+	secret := make([]byte, 20)
+	rand.Read(secret)
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		code := generateRecoveryCode()
+		codes = append(codes, code)
+		hashes = append(hashes, HashToken(code))
+	}
+
+	now := time.Now().UTC()
+	return TOTP{
+		AccountID:     accountID,
+		Secret:        totpEncoding.EncodeToString(secret),
+		RecoveryCodes: hashes,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, codes
+}
+
+func (t *TOTP) Enabled() bool {
+	return t.ConfirmedAt.Valid
+}
+
+// URI is the otpauth:// key URI authenticator apps read from a QR code.
+func (t *TOTP) URI(issuer, accountName string) string {
+	label := url.PathEscape(issuer + ":" + accountName)
+	query := url.Values{}
+	query.Set("secret", t.Secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(int(totpPeriod.Seconds())))
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// Verify accepts a code of the current time step, or of the steps next to it to absorb clock drift,
+// or an unused recovery code. A code is accepted once: steps up to the last one used are rejected.
+func (t *TOTP) Verify(code string, now time.Time) error {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if code == "" {
+		return ErrInvalidOTP.New("one-time password is empty")
+	}
+
+	if len(code) != totpDigits {
+		return t.useRecoveryCode(code, now)
+	}
+
+	secret, err := totpEncoding.DecodeString(t.Secret)
+	if err != nil {
+		return fmt.Errorf("totp secret: %w", err)
+	}
+
+	current := now.Unix() / int64(totpPeriod.Seconds())
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= t.LastStep {
+			continue
+		}
+
+		if subtle.ConstantTimeCompare([]byte(TOTPCode(secret, step)), []byte(code)) == 1 {
+			t.LastStep = step
+			t.UpdatedAt = now.UTC()
+			return nil
+		}
+	}
+
+	return ErrInvalidOTP.New("invalid one-time password")
+}
+
+// Confirm enables the credential with a first valid code.
+func (t *TOTP) Confirm(code string, now time.Time) error {
+	if t.Enabled() {
+		return ErrTOTPAlreadyEnabled.New("two-factor authentication already enabled")
+	}
+
+	if err := t.Verify(code, now); err != nil {
+		return err
+	}
+
+	t.ConfirmedAt = sql.NullTime{Time: now.UTC(), Valid: true}
+	return nil
+}
+
+func (t *TOTP) useRecoveryCode(code string, now time.Time) error {
+	i := slices.Index(t.RecoveryCodes, HashToken(strings.ToLower(code)))
+	if i < 0 {
+		return ErrInvalidOTP.New("invalid one-time password")
+	}
+
+	t.RecoveryCodes = slices.Delete(t.RecoveryCodes, i, i+1)
+	t.UpdatedAt = now.UTC()
+	return nil
+}
+
+// TOTPCode computes the RFC 6238 code of the time step: an RFC 4226 HOTP over the step counter.
+func TOTPCode(secret []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1_000_000)
+}
+
+func generateRecoveryCode() string {
+	b := make([]byte, 5)
+	rand.Read(b)
+	code := hex.EncodeToString(b)
+	return code[:5] + "-" + code[5:]
+}
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTP(t *testing.T) {
+	// RFC 6238 appendix B, SHA1 secret, truncated to 6 digits
+	rfcSecret := []byte("12345678901234567890")
+
+	newTOTP := func() (TOTP, []string) {
+		totp, codes := NewTOTP(uuid.New())
+		totp.Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(rfcSecret)
+		return totp, codes
+	}
+
+	t.Run("rfc 6238 codes", func(t *testing.T) {
+		assert.Equal(t, "287082", TOTPCode(rfcSecret, 59/30))
+		assert.Equal(t, "081804", TOTPCode(rfcSecret, 1111111109/30))
+		assert.Equal(t, "005924", TOTPCode(rfcSecret, 1234567890/30))
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		totp, _ := newTOTP()
+		now := time.Unix(1234567890, 0)
+
+		require.NoError(t, totp.Verify("005924", now))
+		assert.ErrorIs(t, totp.Verify("005924", now), ErrInvalidOTP, "a code is accepted once")
+
+		next := TOTPCode(rfcSecret, now.Unix()/30+1)
+		assert.NoError(t, totp.Verify(next, now), "the next step absorbs clock drift")
+
+		late := TOTPCode(rfcSecret, now.Unix()/30+5)
+		assert.ErrorIs(t, totp.Verify(late, now), ErrInvalidOTP)
+		assert.ErrorIs(t, totp.Verify("", now), ErrInvalidOTP)
+	})
+
+	t.Run("recovery codes work once", func(t *testing.T) {
+		totp, codes := newTOTP()
+		require.Len(t, codes, 10)
+
+		require.NoError(t, totp.Verify(codes[0], time.Now()))
+		assert.Len(t, totp.RecoveryCodes, 9)
+		assert.ErrorIs(t, totp.Verify(codes[0], time.Now()), ErrInvalidOTP)
+	})
+
+	t.Run("confirm", func(t *testing.T) {
+		totp, _ := newTOTP()
+		now := time.Unix(1234567890, 0)
+		assert.False(t, totp.Enabled())
+
+		assert.ErrorIs(t, totp.Confirm("000000", now), ErrInvalidOTP)
+		require.NoError(t, totp.Confirm("005924", now))
+		assert.True(t, totp.Enabled())
+		assert.ErrorIs(t, totp.Confirm(TOTPCode(rfcSecret, now.Unix()/30+1), now), ErrTOTPAlreadyEnabled)
+	})
+
+	t.Run("uri", func(t *testing.T) {
+		totp, _ := NewTOTP(uuid.New())
+		uri, err := url.Parse(totp.URI("GuicPay", "ana@example.com"))
+		require.NoError(t, err)
+
+		assert.Equal(t, "otpauth", uri.Scheme)
+		assert.Equal(t, "totp", uri.Host)
+		assert.Equal(t, "/GuicPay:ana@example.com", uri.Path)
+		assert.Equal(t, totp.Secret, uri.Query().Get("secret"))
+		assert.Equal(t, "GuicPay", uri.Query().Get("issuer"))
+	})
+}
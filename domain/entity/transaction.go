@@ -13,8 +13,18 @@ const (
 	TransferPayer TransactionType = "TRANSFER_PAYER"
 	TransferPayee TransactionType = "TRANSFER_PAYEE"
 	Snapshot      TransactionType = "SNAPSHOT"
+	ReversalPayer TransactionType = "REVERSAL_PAYER"
+	ReversalPayee TransactionType = "REVERSAL_PAYEE"
 )
 
+// reversalNamespace derives the correlated id of a reversal from the reversed transfer, so a
+// transfer can be reversed at most once.
+var reversalNamespace = uuid.MustParse("8a1c3f0e-6a53-4f5e-9a43-2f0f1d3b7c21")
+
+func ReversalCorrelatedID(transferCorrelatedID uuid.UUID) uuid.UUID {
+	return uuid.NewSHA1(reversalNamespace, transferCorrelatedID[:])
+}
+
 type Transaction struct {
 	ID              uuid.UUID
 	CorrelatedID    uuid.NullUUID
@@ -24,6 +34,9 @@ type Transaction struct {
 	Amount          Money
 	SnapshotID      uuid.NullUUID
 	ParentID        uuid.NullUUID
+	// Sequence is the position of the transaction in the feed of its account, numbered in commit
+	// order when it is saved.
+	Sequence int64
 }
 
 func factoryDepositTransaction(account Account, v Money) Transaction {
@@ -69,6 +82,36 @@ func factoryTransferTransactions(payerAccount, payeeAccount Account, v Money, pa
 	return
 }
 
+func factoryReversalTransactions(payerAccount, payeeAccount Account, received Transaction, parent *Transaction) (payer Transaction, payee Transaction) {
+	now := time.Now().UTC()
+	correlatedID := ReversalCorrelatedID(received.CorrelatedID.UUID)
+	payer = Transaction{
+		ID:              uuid.New(),
+		CorrelatedID:    uuid.NullUUID{UUID: correlatedID, Valid: true},
+		AccountID:       payerAccount.ID,
+		TransactionType: ReversalPayer,
+		Timestamp:       now,
+		Amount:          -1 * received.Amount.Absolute(),
+	}
+
+	if parent != nil {
+		payer.ParentID = uuid.NullUUID{Valid: true, UUID: parent.ID}
+	}
+
+	transactionPayeeID := uuid.New()
+	payee = Transaction{
+		ID:              transactionPayeeID,
+		CorrelatedID:    uuid.NullUUID{UUID: correlatedID, Valid: true},
+		AccountID:       payeeAccount.ID,
+		TransactionType: ReversalPayee,
+		Timestamp:       now,
+		Amount:          received.Amount.Absolute(),
+		ParentID:        uuid.NullUUID{Valid: true, UUID: transactionPayeeID},
+	}
+
+	return
+}
+
 type Wallet []*Transaction
 
 func (w *Wallet) Balance() Money {
@@ -126,3 +169,14 @@ func (w *Wallet) FindParent() *Transaction {
 
 	return nil
 }
+
+// BalanceFromSnapshot adds the transactions made after a snapshot to the amount it consolidated.
+// A nil snapshot means the account was never consolidated and the wallet holds its whole history.
+func BalanceFromSnapshot(snapshot *Transaction, later Wallet) Money {
+	balance := later.Balance()
+	if snapshot != nil {
+		balance += snapshot.Amount
+	}
+
+	return balance
+}
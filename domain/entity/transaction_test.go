@@ -42,6 +42,21 @@ func TestTransaction(t *testing.T) {
 
 		assert.Equal(t, payeeTransaction.AccountID, seller.ID)
 	})
+
+	t.Run("BalanceFromSnapshot", func(t *testing.T) {
+		account := factoryFakePersonalAccount(t)
+		depositInAccount(t, &account, 100*Real)
+		depositInAccount(t, &account, 50*Real)
+		snapshot := account.Wallet.Snapshot(account.ID)
+
+		later := Wallet{}
+		deposit := factoryDepositTransaction(account, 25*Real)
+		later = append(later, &deposit)
+
+		assert.Equal(t, 175*Real, BalanceFromSnapshot(snapshot, later))
+		assert.Equal(t, 25*Real, BalanceFromSnapshot(nil, later))
+		assert.Equal(t, 150*Real, BalanceFromSnapshot(snapshot, Wallet{}))
+	})
 }
 
 func factoryFakePersonalAccount(t testing.TB) Account {
@@ -56,6 +71,7 @@ func factoryFakePersonalAccount(t testing.TB) Account {
 	)
 
 	assert.NotEqual(t, uuid.Nil, personal.ID)
+	personal.Status = AccountStatusActive
 	return personal
 }
 
@@ -5,12 +5,17 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 )
 
 type Money int64
 
+// Currency is the ISO 4217 code of every amount handled by the ledger.
+const Currency = "BRL"
+
 const (
 	Cent     Money = 1
 	Real     Money = 100 * Cent
@@ -18,7 +23,61 @@ const (
 )
 
 func (m Money) String() string {
-	return fmt.Sprintf("%.2f BRL", float64(m)/100)
+	return fmt.Sprintf("%.2f %s", float64(m)/100, Currency)
+}
+
+// Decimal formats the amount with exactly two decimal places and no currency, e.g. "-10.29".
+func (m Money) Decimal() string {
+	sign := ""
+	if m < 0 {
+		sign = "-"
+	}
+
+	abs := m.Absolute()
+	return fmt.Sprintf("%s%d.%02d", sign, abs/Real, abs%Real)
+}
+
+// ParseMoney reads a non negative decimal amount in reais, such as "10", "10.2" or "10.29",
+// without going through floating point.
+func ParseMoney(s string) (Money, error) {
+	if strings.HasPrefix(s, "-") {
+		return 0, errors.Join(ErrUnprocessableEntity, fmt.Errorf("amount %q must not be negative", s))
+	}
+
+	whole, fraction, found := strings.Cut(s, ".")
+	if !isDigits(whole) || (found && !isDigits(fraction)) {
+		return 0, errors.Join(ErrUnprocessableEntity, fmt.Errorf("invalid amount %q", s))
+	}
+
+	if len(fraction) > 2 {
+		return 0, errors.Join(ErrUnprocessableEntity, fmt.Errorf("amount %q has more than two decimal places", s))
+	}
+
+	reais, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || reais > math.MaxInt64/int64(Real)-1 {
+		return 0, errors.Join(ErrUnprocessableEntity, fmt.Errorf("amount %q is too large", s))
+	}
+
+	var cents int64
+	if fraction != "" {
+		cents, _ = strconv.ParseInt((fraction + "0")[:2], 10, 64)
+	}
+
+	return Money(reais)*Real + Money(cents)*Cent, nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
 }
 
 func (m Money) Absolute() Money {
@@ -38,7 +97,7 @@ func (p *Password) Ok() error {
 func (p *Password) Compare(input string) error {
 	parts := strings.Split(string(*p), ":")
 	if len(parts) != 3 {
-		return errors.New("TODO:")
+		return ErrInvalidCredentials.New("password encoding is malformed")
 	}
 
 	method := parts[0]
@@ -48,7 +107,7 @@ func (p *Password) Compare(input string) error {
 	switch method {
 	case "SHA256":
 		if password != computeSHA256Hash(input+salt) {
-			return errors.New("TODO: password invalid")
+			return ErrInvalidCredentials.New("email or password does not match")
 		}
 	}
 
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoney(t *testing.T) {
+	t.Run("parse", func(t *testing.T) {
+		for input, expected := range map[string]Money{
+			"10.29": 10*Real + 29*Cent,
+			"0.29":  29 * Cent,
+			"10.2":  10*Real + 20*Cent,
+			"10":    10 * Real,
+			"0.01":  Cent,
+		} {
+			m, err := ParseMoney(input)
+			require.NoError(t, err, input)
+			assert.Equal(t, expected, m, input)
+		}
+
+		for _, input := range []string{"", "-1", "1.001", "1,00", "1.", ".5", "1e3", "abc", "92233720368547758.07"} {
+			_, err := ParseMoney(input)
+			assert.True(t, errors.Is(err, ErrUnprocessableEntity), input)
+		}
+	})
+
+	t.Run("decimal", func(t *testing.T) {
+		assert.Equal(t, "-10.29", Money(-1029).Decimal())
+		assert.Equal(t, "0.05", Money(5).Decimal())
+	})
+}
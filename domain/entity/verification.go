@@ -0,0 +1,113 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VerificationChannel is a contact of the account proven at onboarding.
+type VerificationChannel string
+
+const (
+	VerificationEmail VerificationChannel = "EMAIL"
+	VerificationPhone VerificationChannel = "PHONE"
+)
+
+// VerificationChannels are the channels an account verifies before it can send money.
+var VerificationChannels = []VerificationChannel{VerificationEmail, VerificationPhone}
+
+func ParseVerificationChannel(s string) (VerificationChannel, error) {
+	channel := VerificationChannel(strings.ToUpper(strings.TrimSpace(s)))
+	switch channel {
+	case VerificationEmail, VerificationPhone:
+		return channel, nil
+	}
+
+	return "", ErrInvalidArgument.Errorf("invalid verification channel %q", s)
+}
+
+// Verification is the one-time code sent to a channel of the account. Only its hash is kept; a new
+// code replaces the previous one, and a code stops being accepted once it expires or after too many
+// wrong attempts.
+type Verification struct {
+	AccountID  uuid.UUID
+	Channel    VerificationChannel
+	CodeHash   string
+	Attempts   int
+	ExpiresAt  time.Time
+	SentAt     time.Time
+	VerifiedAt sql.NullTime
+}
+
+// NewVerification issues a code for the channel, returning it along with the code sent.
+func NewVerification(accountID uuid.UUID, channel VerificationChannel, ttl time.Duration) (Verification, string) {
+	n, _ := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	code := fmt.Sprintf("%06d", n.Int64())
+
+	now := time.Now().UTC()
+	return Verification{
+		AccountID: accountID,
+		Channel:   channel,
+		CodeHash:  HashToken(verificationCodeKey(accountID, channel, code)),
+		ExpiresAt: now.Add(ttl),
+		SentAt:    now,
+	}, code
+}
+
+func (v *Verification) Verified() bool {
+	return v.VerifiedAt.Valid
+}
+
+// Check counts an attempt and marks the channel verified when the code matches. Attempts beyond
+// maxAttempts are rejected without comparing the code.
+func (v *Verification) Check(code string, now time.Time, maxAttempts int) error {
+	if err := v.Checkable(now, maxAttempts); err != nil {
+		return err
+	}
+
+	v.Attempts++
+	return v.Match(code, now)
+}
+
+// Checkable fails when no code can be checked against the verification anymore: the channel is
+// verified, the attempts ran out or the code expired.
+func (v *Verification) Checkable(now time.Time, maxAttempts int) error {
+	if v.Verified() {
+		return ErrAlreadyVerified.Errorf("%s already verified", strings.ToLower(string(v.Channel)))
+	}
+
+	if v.Attempts >= maxAttempts {
+		return ErrVerificationLocked.New("too many wrong codes, request a new one")
+	}
+
+	if !now.Before(v.ExpiresAt) {
+		return ErrInvalidVerification.New("verification code expired, request a new one")
+	}
+
+	return nil
+}
+
+// Match marks the channel verified when the code matches, without counting the attempt; callers
+// sharing the verification count it in the repository first.
+func (v *Verification) Match(code string, now time.Time) error {
+	hash := HashToken(verificationCodeKey(v.AccountID, v.Channel, strings.TrimSpace(code)))
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(v.CodeHash)) != 1 {
+		return ErrInvalidVerification.New("invalid verification code")
+	}
+
+	v.VerifiedAt = sql.NullTime{Time: now.UTC(), Valid: true}
+	return nil
+}
+
+// verificationCodeKey salts the short code with its account and channel, so equal codes do not
+// share a hash.
+func verificationCodeKey(accountID uuid.UUID, channel VerificationChannel, code string) string {
+	return accountID.String() + ":" + string(channel) + ":" + code
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerification(t *testing.T) {
+	now := time.Now()
+
+	t.Run("matching code", func(t *testing.T) {
+		v, code := NewVerification(uuid.New(), VerificationEmail, time.Minute)
+		assert.Len(t, code, 6)
+		assert.NotContains(t, v.CodeHash, code)
+
+		assert.ErrorIs(t, v.Check("000000x", now, 3), ErrInvalidVerification)
+		require.NoError(t, v.Check(code, now, 3))
+		assert.True(t, v.Verified())
+		assert.Equal(t, 2, v.Attempts)
+
+		assert.ErrorIs(t, v.Check(code, now, 3), ErrAlreadyVerified)
+	})
+
+	t.Run("attempt limit", func(t *testing.T) {
+		v, code := NewVerification(uuid.New(), VerificationPhone, time.Minute)
+		for range 3 {
+			assert.ErrorIs(t, v.Check("wrong", now, 3), ErrInvalidVerification)
+		}
+
+		err := v.Check(code, now, 3)
+		assert.ErrorIs(t, err, ErrVerificationLocked)
+		assert.ErrorIs(t, err, ErrTooManyRequests)
+		assert.False(t, v.Verified())
+	})
+
+	t.Run("expired code", func(t *testing.T) {
+		v, code := NewVerification(uuid.New(), VerificationEmail, time.Minute)
+		assert.ErrorIs(t, v.Check(code, now.Add(2*time.Minute), 3), ErrInvalidVerification)
+		assert.False(t, v.Verified())
+	})
+
+	t.Run("channel", func(t *testing.T) {
+		channel, err := ParseVerificationChannel(" phone ")
+		require.NoError(t, err)
+		assert.Equal(t, VerificationPhone, channel)
+
+		_, err = ParseVerificationChannel("fax")
+		assert.ErrorIs(t, err, ErrInvalidArgument)
+	})
+}
@@ -0,0 +1,242 @@
+package entity
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookEventType string
+
+const (
+	WebhookEventTransferReceived WebhookEventType = "transfer.received"
+	WebhookEventRefundCreated    WebhookEventType = "refund.created"
+)
+
+func (e WebhookEventType) Valid() bool {
+	switch e {
+	case WebhookEventTransferReceived, WebhookEventRefundCreated:
+		return true
+	}
+
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "SUCCEEDED"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+// WebhookSecretMinLength is the shortest secret a caller may choose for a webhook.
+const WebhookSecretMinLength = 24
+
+type Webhook struct {
+	ID        uuid.UUID
+	AccountID uuid.UUID
+	URL       string
+	Secret    string
+	Events    []WebhookEventType
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewWebhook registers a webhook posting to rawURL, which must be https on a public host. insecure
+// also accepts http and private hosts, for receivers running next to a development server.
+func NewWebhook(accountID uuid.UUID, rawURL, secret string, events []WebhookEventType, insecure bool) (Webhook, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Hostname() == "" {
+		return Webhook{}, errors.Join(ErrUnprocessableEntity, fmt.Errorf("webhook url invalid: %q", rawURL))
+	}
+
+	if !insecure {
+		if u.Scheme != "https" {
+			return Webhook{}, errors.Join(ErrUnprocessableEntity, fmt.Errorf("webhook url must use https: %q", rawURL))
+		}
+
+		if !webhookHostAllowed(u.Hostname()) {
+			return Webhook{}, errors.Join(ErrUnprocessableEntity, fmt.Errorf("webhook url must be a public host: %q", rawURL))
+		}
+	}
+
+	if secret != "" && len(secret) < WebhookSecretMinLength {
+		return Webhook{}, errors.Join(ErrUnprocessableEntity, fmt.Errorf("webhook secret must have at least %d characters", WebhookSecretMinLength))
+	}
+
+	if len(events) == 0 {
+		return Webhook{}, errors.Join(ErrUnprocessableEntity, errors.New("webhook must subscribe at least one event"))
+	}
+
+	for _, e := range events {
+		if !e.Valid() {
+			return Webhook{}, errors.Join(ErrUnprocessableEntity, fmt.Errorf("webhook event invalid: %q", e))
+		}
+	}
+
+	if secret == "" {
+		secret = generateWebhookSecret()
+	}
+
+	now := time.Now().UTC()
+	return Webhook{
+		ID:        uuid.New(),
+		AccountID: accountID,
+		URL:       u.String(),
+		Secret:    secret,
+		Events:    events,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// cgnat is the shared address space of carrier-grade NATs, RFC 6598.
+var cgnat = netip.MustParsePrefix("100.64.0.0/10")
+
+// WebhookTargetAllowed reports whether deliveries may connect to the address: loopback, private,
+// link-local and other non-public addresses would let a webhook reach internal services.
+func WebhookTargetAllowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() && addr.IsGlobalUnicast() && !addr.IsPrivate() && !cgnat.Contains(addr)
+}
+
+// webhookHostAllowed rejects the hosts known to be internal before any lookup; names are checked
+// again on the addresses they resolve to when a delivery connects.
+func webhookHostAllowed(host string) bool {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return WebhookTargetAllowed(addr)
+	}
+
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	return host != "localhost" && !strings.HasSuffix(host, ".localhost")
+}
+
+func (w *Webhook) Subscribed(e WebhookEventType) bool {
+	for _, it := range w.Events {
+		if it == e {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Sign computes the HMAC-SHA256 of "<timestamp>.<payload>" using the webhook secret.
+func (w *Webhook) Sign(timestamp time.Time, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(w.Secret))
+	fmt.Fprintf(mac, "%d.", timestamp.Unix())
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func generateWebhookSecret() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return "whsec_" + hex.EncodeToString(b)
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	WebhookID      uuid.UUID
+	EventID        uuid.UUID
+	EventType      WebhookEventType
+	Payload        json.RawMessage
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	LastStatusCode int
+	LastError      string
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type WebhookEvent struct {
+	ID        uuid.UUID        `json:"id"`
+	Type      WebhookEventType `json:"type"`
+	CreatedAt time.Time        `json:"created_at"`
+	Data      any              `json:"data"`
+}
+
+func NewWebhookEvent(t WebhookEventType, data any) WebhookEvent {
+	return WebhookEvent{
+		ID:        uuid.New(),
+		Type:      t,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+}
+
+func NewWebhookDelivery(webhook Webhook, event WebhookEvent) (WebhookDelivery, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return WebhookDelivery{}, err
+	}
+
+	now := time.Now().UTC()
+	return WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     webhook.ID,
+		EventID:       event.ID,
+		EventType:     event.Type,
+		Payload:       payload,
+		Status:        WebhookDeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// Replay returns a new pending delivery carrying the same event, so receivers can deduplicate by event id.
+func (d *WebhookDelivery) Replay() WebhookDelivery {
+	now := time.Now().UTC()
+	return WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     d.WebhookID,
+		EventID:       d.EventID,
+		EventType:     d.EventType,
+		Payload:       d.Payload,
+		Status:        WebhookDeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func (d *WebhookDelivery) Succeeded(statusCode int) {
+	d.Attempts++
+	d.Status = WebhookDeliverySucceeded
+	d.LastStatusCode = statusCode
+	d.LastError = ""
+	d.UpdatedAt = time.Now().UTC()
+}
+
+// Failed records a failed attempt and schedules the next one with exponential backoff,
+// giving up once maxAttempts is reached.
+func (d *WebhookDelivery) Failed(statusCode int, err error, maxAttempts int, backoff time.Duration) {
+	now := time.Now().UTC()
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.LastError = ""
+	if err != nil {
+		d.LastError = err.Error()
+	}
+	d.UpdatedAt = now
+
+	if d.Attempts >= maxAttempts {
+		d.Status = WebhookDeliveryFailed
+		return
+	}
+
+	d.NextAttemptAt = now.Add(backoff * time.Duration(1<<(d.Attempts-1)))
+}
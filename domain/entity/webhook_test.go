@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook(t *testing.T) {
+	accountID := uuid.New()
+	secret := "whsec_0123456789abcdefghij"
+
+	t.Run("new webhook", func(t *testing.T) {
+		webhook, err := NewWebhook(accountID, "https://seller.example.com/hooks", "", []WebhookEventType{WebhookEventTransferReceived}, false)
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, webhook.ID)
+		assert.NotEmpty(t, webhook.Secret)
+		assert.True(t, webhook.Subscribed(WebhookEventTransferReceived))
+		assert.False(t, webhook.Subscribed(WebhookEventRefundCreated))
+	})
+
+	t.Run("invalid webhook", func(t *testing.T) {
+		_, err := NewWebhook(accountID, "ftp://seller.example.com", secret, []WebhookEventType{WebhookEventTransferReceived}, false)
+		assert.True(t, errors.Is(err, ErrUnprocessableEntity))
+
+		_, err = NewWebhook(accountID, "https://seller.example.com", secret, []WebhookEventType{"transfer.unknown"}, false)
+		assert.True(t, errors.Is(err, ErrUnprocessableEntity))
+
+		_, err = NewWebhook(accountID, "https://seller.example.com", secret, nil, false)
+		assert.True(t, errors.Is(err, ErrUnprocessableEntity))
+
+		_, err = NewWebhook(accountID, "https://seller.example.com", "short", []WebhookEventType{WebhookEventTransferReceived}, false)
+		assert.True(t, errors.Is(err, ErrUnprocessableEntity))
+	})
+
+	t.Run("webhook targets", func(t *testing.T) {
+		for _, rawURL := range []string{
+			"http://seller.example.com/hooks",
+			"https://localhost/hooks",
+			"https://api.localhost/hooks",
+			"https://127.0.0.1/hooks",
+			"https://10.0.0.5/hooks",
+			"https://192.168.0.10:8443/hooks",
+			"https://169.254.169.254/latest/meta-data",
+			"https://100.64.0.1/hooks",
+			"https://[::1]/hooks",
+			"https://[fd00::1]/hooks",
+			"https://[::ffff:127.0.0.1]/hooks",
+		} {
+			_, err := NewWebhook(accountID, rawURL, "", []WebhookEventType{WebhookEventTransferReceived}, false)
+			assert.True(t, errors.Is(err, ErrUnprocessableEntity), rawURL)
+		}
+
+		_, err := NewWebhook(accountID, "https://203.0.113.7/hooks", "", []WebhookEventType{WebhookEventTransferReceived}, false)
+		assert.NoError(t, err)
+
+		_, err = NewWebhook(accountID, "http://localhost:8080/hooks", "", []WebhookEventType{WebhookEventTransferReceived}, true)
+		assert.NoError(t, err)
+	})
+
+	t.Run("sign", func(t *testing.T) {
+		webhook, _ := NewWebhook(accountID, "https://seller.example.com", secret, []WebhookEventType{WebhookEventTransferReceived}, false)
+		ts := time.Unix(1700000000, 0)
+		payload := []byte(`{"id":"1"}`)
+
+		mac := hmac.New(sha256.New, []byte(secret))
+		fmt.Fprintf(mac, "%d.%s", ts.Unix(), payload)
+		assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), webhook.Sign(ts, payload))
+	})
+
+	t.Run("delivery backoff", func(t *testing.T) {
+		webhook, _ := NewWebhook(accountID, "https://seller.example.com", secret, []WebhookEventType{WebhookEventTransferReceived}, false)
+		delivery, err := NewWebhookDelivery(webhook, NewWebhookEvent(WebhookEventTransferReceived, map[string]string{"k": "v"}))
+		assert.NoError(t, err)
+		assert.Equal(t, WebhookDeliveryPending, delivery.Status)
+
+		delivery.Failed(500, errors.New("boom"), 3, time.Minute)
+		assert.Equal(t, WebhookDeliveryPending, delivery.Status)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), delivery.NextAttemptAt, time.Second)
+
+		delivery.Failed(500, errors.New("boom"), 3, time.Minute)
+		assert.WithinDuration(t, time.Now().Add(2*time.Minute), delivery.NextAttemptAt, time.Second)
+
+		delivery.Failed(500, errors.New("boom"), 3, time.Minute)
+		assert.Equal(t, WebhookDeliveryFailed, delivery.Status)
+		assert.Equal(t, 3, delivery.Attempts)
+
+		replay := delivery.Replay()
+		assert.NotEqual(t, delivery.ID, replay.ID)
+		assert.Equal(t, delivery.EventID, replay.EventID)
+		assert.Equal(t, WebhookDeliveryPending, replay.Status)
+	})
+}
@@ -0,0 +1,15 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+)
+
+type EventHandler func(ctx context.Context, event entity.Event) error
+
+type EventBus interface {
+	Publish(ctx context.Context, events ...entity.Event) error
+	// Subscribe registers the handler and returns a function that removes it.
+	Subscribe(handler EventHandler, eventNames ...string) (unsubscribe func())
+}
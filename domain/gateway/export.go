@@ -0,0 +1,15 @@
+package gateway
+
+import (
+	"time"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+)
+
+// StatementEncoder writes a statement in a given file format as lines are read,
+// so the whole statement never needs to be held in memory.
+type StatementEncoder interface {
+	Begin(account entity.Account, from, to time.Time) error
+	Line(line *entity.StatementLine) error
+	End(ledgerBalance entity.Money, asOf time.Time) error
+}
@@ -2,6 +2,7 @@ package gateway
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/guilhermealvess/guicpay/domain/entity"
@@ -13,10 +14,25 @@ type AccountRepository interface {
 	FindAccount(ctx context.Context, accountID uuid.UUID) (*entity.Account, error)
 	FindAccountByIDs(ctx context.Context, ids ...uuid.UUID) (map[uuid.UUID]*entity.Account, error)
 	SaveAtomicTransactions(ctx context.Context, transactions ...entity.Transaction) error
-	FindAll(ctx context.Context) ([]*entity.Account, error)
+	ListAccounts(ctx context.Context, filter entity.AccountFilter) ([]*entity.AccountSummary, error)
+	CountAccounts(ctx context.Context, filter entity.AccountFilter) (int, error)
 	SetSnapshotTransactions(ctx context.Context, snapshotID uuid.UUID, transactionIDs uuid.UUIDs) error
 	FindAccountByEmail(ctx context.Context, email string) (*entity.Account, error)
+	FindAccountByDocument(ctx context.Context, document string) (*entity.Account, error)
+	UpdateAccount(ctx context.Context, account entity.Account) error
+	// LockAccount locks the account until the transaction in the context ends. An account read
+	// after locking it reflects every change committed before, so updating it loses none.
+	LockAccount(ctx context.Context, accountID uuid.UUID) error
+	FindTransactionsByCorrelatedID(ctx context.Context, correlatedID uuid.UUID) ([]*entity.Transaction, error)
 	FindResumeAccount(ctx context.Context, email string) (*entity.ResumeAccount, error)
+	FindResumeAccountByID(ctx context.Context, accountID uuid.UUID) (*entity.ResumeAccount, error)
+	FindStatement(ctx context.Context, accountID uuid.UUID, filter entity.StatementFilter) ([]*entity.StatementLine, error)
+	StreamStatement(ctx context.Context, accountID uuid.UUID, filter entity.StatementFilter, fn func(*entity.StatementLine) error) error
+	FindSnapshotBefore(ctx context.Context, accountID uuid.UUID, at time.Time) (*entity.Transaction, error)
+	FindTransactionsAfterSnapshot(ctx context.Context, accountID uuid.UUID, snapshot *entity.Transaction, at time.Time) (entity.Wallet, error)
+	FindTransactionFeed(ctx context.Context, accountID uuid.UUID, cursor *entity.FeedCursor, limit int) ([]*entity.Transaction, error)
+	// HasTransferredTo reports whether the payer ever sent a transfer to the payee.
+	HasTransferredTo(ctx context.Context, payer, payee uuid.UUID) (bool, error)
 }
 
 type Tx interface {
@@ -36,6 +52,11 @@ func InjectTransaction(ctx context.Context, tx Tx) context.Context {
 	return context.WithValue(ctx, TransactionContextKey, tx)
 }
 
+// DetachTransaction returns a context that no longer carries a database transaction.
+func DetachTransaction(ctx context.Context) context.Context {
+	return context.WithValue(ctx, TransactionContextKey, nil)
+}
+
 func GetTransactionContext(ctx context.Context) (Tx, bool) {
 	val := ctx.Value(TransactionContextKey)
 	if val == nil {
@@ -45,3 +66,85 @@ func GetTransactionContext(ctx context.Context) (Tx, bool) {
 	tx := val.(Tx)
 	return tx, true
 }
+
+type WebhookRepository interface {
+	Repository
+	CreateWebhook(ctx context.Context, webhook entity.Webhook) error
+	FindWebhook(ctx context.Context, webhookID uuid.UUID) (*entity.Webhook, error)
+	FindWebhooksByAccount(ctx context.Context, accountID uuid.UUID) ([]*entity.Webhook, error)
+	DeleteWebhook(ctx context.Context, webhookID uuid.UUID) error
+	SaveWebhookDeliveries(ctx context.Context, deliveries ...entity.WebhookDelivery) error
+	UpdateWebhookDelivery(ctx context.Context, delivery entity.WebhookDelivery) error
+	FindWebhookDelivery(ctx context.Context, deliveryID uuid.UUID) (*entity.WebhookDelivery, error)
+	FindWebhookDeliveries(ctx context.Context, webhookID uuid.UUID) ([]*entity.WebhookDelivery, error)
+	ClaimWebhookDeliveries(ctx context.Context, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error)
+}
+
+type TokenRepository interface {
+	Repository
+	SaveRefreshToken(ctx context.Context, token entity.RefreshToken) error
+	FindRefreshToken(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
+	// UseRefreshToken marks the token used and reports false when it was already used or revoked.
+	UseRefreshToken(ctx context.Context, tokenID uuid.UUID, at time.Time) (bool, error)
+	RevokeTokenFamily(ctx context.Context, familyID uuid.UUID, at time.Time) error
+	RevokeTokens(ctx context.Context, tokens ...entity.RevokedToken) error
+	// IsTokenRevoked reports whether any of the ids, a token id or a session id, is revoked.
+	IsTokenRevoked(ctx context.Context, ids ...uuid.UUID) (bool, error)
+	// RevokeAccountSessions revokes the refresh tokens of every session of the account and returns
+	// the ids of the sessions that were still open.
+	RevokeAccountSessions(ctx context.Context, accountID uuid.UUID, at time.Time) ([]uuid.UUID, error)
+	// SavePasswordResetToken inserts the reset token of the account or replaces the one it has.
+	SavePasswordResetToken(ctx context.Context, token entity.PasswordResetToken) error
+	FindPasswordResetToken(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error)
+	// UsePasswordResetToken marks the token used and reports false when it was already used.
+	UsePasswordResetToken(ctx context.Context, tokenHash string, at time.Time) (bool, error)
+}
+
+type MFARepository interface {
+	Repository
+	// SaveTOTP inserts the credential of the account or replaces the one it has.
+	SaveTOTP(ctx context.Context, totp entity.TOTP) error
+	FindTOTP(ctx context.Context, accountID uuid.UUID) (*entity.TOTP, error)
+	// UseTOTP saves the credential after one of its codes was accepted and reports false when its
+	// last step or update time are no longer lastStep and updatedAt, because a code was used meanwhile.
+	UseTOTP(ctx context.Context, totp entity.TOTP, lastStep int64, updatedAt time.Time) (bool, error)
+	DeleteTOTP(ctx context.Context, accountID uuid.UUID) error
+}
+
+type LoginAttemptRepository interface {
+	Repository
+	// RecordLoginFailure counts a failure of the key and returns its updated attempts; the count
+	// starts over when the previous failure is older than window.
+	RecordLoginFailure(ctx context.Context, key string, at time.Time, window time.Duration) (*entity.LoginAttempts, error)
+	FindLoginAttempts(ctx context.Context, keys ...string) ([]*entity.LoginAttempts, error)
+	DeleteLoginAttempts(ctx context.Context, keys ...string) error
+}
+
+type VerificationRepository interface {
+	Repository
+	// SaveVerification inserts the code of the account channel or replaces the one it has.
+	SaveVerification(ctx context.Context, verification entity.Verification) error
+	FindVerifications(ctx context.Context, accountID uuid.UUID) ([]*entity.Verification, error)
+	// CountVerificationAttempt adds an attempt to the unverified code of the account channel and
+	// reports false when it was verified meanwhile or already had maxAttempts.
+	CountVerificationAttempt(ctx context.Context, accountID uuid.UUID, channel entity.VerificationChannel, maxAttempts int) (bool, error)
+}
+
+type APIKeyRepository interface {
+	Repository
+	CreateAPIKey(ctx context.Context, key entity.APIKey) error
+	FindAPIKey(ctx context.Context, keyID uuid.UUID) (*entity.APIKey, error)
+	FindAPIKeyByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error)
+	FindAPIKeysByAccount(ctx context.Context, accountID uuid.UUID) ([]*entity.APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyID uuid.UUID, at time.Time) error
+	// TouchAPIKey records when the key was last used.
+	TouchAPIKey(ctx context.Context, keyID uuid.UUID, at time.Time) error
+}
+
+// AuditRepository is append-only: entries are saved in the transaction of the action they record
+// and never changed.
+type AuditRepository interface {
+	Repository
+	SaveAuditEntry(ctx context.Context, entry entity.AuditEntry) error
+	FindAuditEntries(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, error)
+}
@@ -2,14 +2,29 @@ package gateway
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/guilhermealvess/guicpay/domain/entity"
 )
 
 type NotificationService interface {
 	Notify(ctx context.Context, account entity.Account, transaction entity.Transaction) error
+	// NotifyPasswordReset sends the account holder the token that resets their password.
+	NotifyPasswordReset(ctx context.Context, account entity.Account, token string, expiresAt time.Time) error
+	// NotifyVerificationCode sends the code verifying the email or the phone of the account.
+	NotifyVerificationCode(ctx context.Context, account entity.Account, channel entity.VerificationChannel, code string, expiresAt time.Time) error
 }
 
 type AuthorizationService interface {
 	Authorize(ctx context.Context, account entity.Account) error
 }
+
+type WebhookService interface {
+	Send(ctx context.Context, webhook entity.Webhook, delivery entity.WebhookDelivery) (int, error)
+}
+
+// TokenSigner signs the claims of an access token, adding its expiration.
+type TokenSigner interface {
+	Generate(data json.RawMessage) (string, error)
+}
@@ -0,0 +1,319 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/internal/properties"
+)
+
+type refundCreatedData struct {
+	RefundID             uuid.UUID `json:"refund_id"`
+	TransferCorrelatedID uuid.UUID `json:"transfer_correlated_id"`
+	TransactionID        uuid.UUID `json:"transaction_id"`
+	PayerID              uuid.UUID `json:"payer_id"`
+	PayeeID              uuid.UUID `json:"payee_id"`
+	Amount               int64     `json:"amount"`
+	Currency             string    `json:"currency"`
+	Timestamp            time.Time `json:"timestamp"`
+}
+
+func (u *accountUseCase) FindByEmail(ctx context.Context, email string) (*AccountOutput, error) {
+	account, err := u.repository.FindAccountByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildAccountOutput(account), nil
+}
+
+func (u *accountUseCase) FindByDocument(ctx context.Context, document string) (*AccountOutput, error) {
+	account, err := u.repository.FindAccountByDocument(ctx, document)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildAccountOutput(account), nil
+}
+
+func (u *accountUseCase) ExecuteChangeStatus(ctx context.Context, accountID uuid.UUID, status string, dryRun bool) (*StatusOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, properties.Props.TransactionTimeout)
+	defer cancel()
+
+	tx, err := u.repository.NewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ctx = gateway.InjectTransaction(ctx, tx)
+	account, err := u.lockAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := StatusOutput{AccountID: account.ID, From: string(account.Status), DryRun: dryRun}
+	if err := account.ChangeStatus(entity.AccountStatus(strings.ToUpper(status))); err != nil {
+		return nil, err
+	}
+	output.To = string(account.Status)
+
+	if err := u.repository.UpdateAccount(ctx, *account); err != nil {
+		return nil, err
+	}
+
+	if account.Status == entity.AccountStatusCanceled {
+		if err := revokeAccountSessions(ctx, u.tokens, account.ID, time.Now().UTC()); err != nil {
+			return nil, err
+		}
+	}
+
+	before, after := auditStatus{Status: entity.AccountStatus(output.From)}, auditStatus{Status: account.Status}
+	if err := audit(ctx, u.audit, entity.AuditAccountStatusChanged, account.ID, before, after); err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return &output, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	u.publish(ctx, account.PullEvents()...)
+	return &output, nil
+}
+
+// ExecuteChangeRole grants the account a role. Open sessions keep the permissions their access token
+// was issued with until it is refreshed.
+func (u *accountUseCase) ExecuteChangeRole(ctx context.Context, accountID uuid.UUID, role string, dryRun bool) (*RoleOutput, error) {
+	newRole, err := entity.ParseRole(role)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := u.repository.NewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	txCtx := gateway.InjectTransaction(ctx, tx)
+	account, err := u.lockAccount(txCtx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := RoleOutput{AccountID: account.ID, From: string(account.Role), DryRun: dryRun}
+	if err := account.ChangeRole(newRole); err != nil {
+		return nil, err
+	}
+	output.To = string(account.Role)
+
+	if dryRun {
+		return &output, nil
+	}
+
+	if err := u.repository.UpdateAccount(txCtx, *account); err != nil {
+		return nil, err
+	}
+
+	before, after := auditRole{Role: entity.Role(output.From)}, auditRole{Role: account.Role}
+	if err := audit(txCtx, u.audit, entity.AuditAccountRoleChanged, account.ID, before, after); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &output, nil
+}
+
+// ExecuteReverseTransfer gives a transfer back: the account that received it pays the same amount
+// back to the original payer. A transfer can only be reversed once.
+func (u *accountUseCase) ExecuteReverseTransfer(ctx context.Context, correlatedID uuid.UUID, dryRun bool) (*ReversalOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, properties.Props.TransactionTimeout)
+	defer cancel()
+
+	tx, err := u.repository.NewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ctx = gateway.InjectTransaction(ctx, tx)
+	transactions, err := u.repository.FindTransactionsByCorrelatedID(ctx, correlatedID)
+	if err != nil {
+		return nil, err
+	}
+
+	var sent, received *entity.Transaction
+	for _, t := range transactions {
+		switch t.TransactionType {
+		case entity.TransferPayer:
+			sent = t
+		case entity.TransferPayee:
+			received = t
+		}
+	}
+
+	if sent == nil || received == nil {
+		return nil, entity.ErrTransferNotFound.Errorf("transfer %s not found", correlatedID)
+	}
+
+	reversals, err := u.repository.FindTransactionsByCorrelatedID(ctx, entity.ReversalCorrelatedID(correlatedID))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reversals) > 0 {
+		return nil, entity.ErrAlreadyReversed.Errorf("transfer %s already reversed", correlatedID)
+	}
+
+	// the account that received the transfer is the one paying it back
+	payer, err := u.repository.FindAccount(ctx, received.AccountID)
+	if err != nil {
+		return nil, notFoundAs(entity.ErrAccountNotFound, err)
+	}
+
+	payee, err := u.repository.FindAccount(ctx, sent.AccountID)
+	if err != nil {
+		return nil, notFoundAs(entity.ErrAccountNotFound, err)
+	}
+
+	before := newAuditBalances(payer, payee)
+	output, err := payer.Reverse(payee, *received)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.repository.SaveAtomicTransactions(ctx, *output.Payer, *output.Payee); err != nil {
+		return nil, err
+	}
+
+	if err := audit(ctx, u.audit, entity.AuditTransferReversed, payer.ID, before, auditTransfer{
+		CorrelatedID:         output.CorrelatedID,
+		TransferCorrelatedID: &correlatedID,
+		PayerID:              payer.ID,
+		PayeeID:              payee.ID,
+		Amount:               Money(output.Payee.Amount),
+		auditBalances:        newAuditBalances(payer, payee),
+	}); err != nil {
+		return nil, err
+	}
+
+	event := entity.NewWebhookEvent(entity.WebhookEventRefundCreated, refundCreatedData{
+		RefundID:             output.CorrelatedID,
+		TransferCorrelatedID: correlatedID,
+		TransactionID:        output.Payer.ID,
+		PayerID:              payer.ID,
+		PayeeID:              payee.ID,
+		Amount:               int64(output.Payer.Amount.Absolute()),
+		Currency:             "BRL",
+		Timestamp:            output.Payer.Timestamp,
+	})
+	if err := enqueueWebhookEvent(ctx, u.webhooks, payer.ID, event); err != nil {
+		return nil, err
+	}
+
+	result := ReversalOutput{
+		CorrelatedID:         output.CorrelatedID,
+		TransferCorrelatedID: correlatedID,
+		PayerID:              payer.ID,
+		PayeeID:              payee.ID,
+		Amount:               Money(output.Payee.Amount),
+		DryRun:               dryRun,
+	}
+
+	if dryRun {
+		return &result, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	u.publish(ctx, payer.PullEvents()...)
+	return &result, nil
+}
+
+// ExecuteResetPassword sets a new password for the account; when none is given a random one is
+// generated and returned so the operator can hand it over. It also ends the sessions of the
+// account and lifts a login lockout.
+func (u *accountUseCase) ExecuteResetPassword(ctx context.Context, accountID uuid.UUID, password string, dryRun bool) (*PasswordResetOutput, error) {
+	tx, err := u.repository.NewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	txCtx := gateway.InjectTransaction(ctx, tx)
+	account, err := u.lockAccount(txCtx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := PasswordResetOutput{AccountID: account.ID, DryRun: dryRun}
+	if dryRun {
+		return &output, nil
+	}
+
+	if password == "" {
+		password = generateRandomPassword()
+		output.Password = password
+	}
+
+	account.ChangePassword(password)
+	if err := u.repository.UpdateAccount(txCtx, *account); err != nil {
+		return nil, err
+	}
+
+	if err := revokeAccountSessions(txCtx, u.tokens, account.ID, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	if err := u.attempts.DeleteLoginAttempts(txCtx, entity.LoginEmailKey(account.Email)); err != nil {
+		return nil, err
+	}
+
+	// the password itself is never audited, only that one was generated
+	after := auditPasswordReset{Generated: output.Password != ""}
+	if err := audit(txCtx, u.audit, entity.AuditPasswordReset, account.ID, nil, after); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &output, nil
+}
+
+// lockAccount locks the account in the transaction of ctx before reading it, so the update that
+// follows does not overwrite a change committed meanwhile.
+func (u *accountUseCase) lockAccount(ctx context.Context, accountID uuid.UUID) (*entity.Account, error) {
+	if err := u.repository.LockAccount(ctx, accountID); err != nil {
+		return nil, notFoundAs(entity.ErrAccountNotFound, err)
+	}
+
+	account, err := u.repository.FindAccount(ctx, accountID)
+	if err != nil {
+		return nil, notFoundAs(entity.ErrAccountNotFound, err)
+	}
+
+	return account, nil
+}
+
+func generateRandomPassword() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
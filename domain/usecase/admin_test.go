@@ -0,0 +1,97 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteReverseTransfer(t *testing.T) {
+	ctx := context.Background()
+
+	transfer := func(t *testing.T, f *fixture, amount entity.Money) (entity.Account, entity.Account, uuid.UUID) {
+		t.Helper()
+		payer := f.account(t, entity.Personal, 100*entity.Real)
+		payee := f.account(t, entity.Personal, 0)
+		correlatedID, err := f.usecase.ExecuteTransfer(ctx, payer.ID, payee.ID, uint64(amount), "")
+		require.NoError(t, err)
+		return payer, payee, correlatedID
+	}
+
+	t.Run("pays the transfer back", func(t *testing.T) {
+		f := newFixture(t)
+		payer, payee, correlatedID := transfer(t, f, 40*entity.Real)
+
+		webhook, err := entity.NewWebhook(payee.ID, "https://example.com/hook", "", []entity.WebhookEventType{entity.WebhookEventRefundCreated}, false)
+		require.NoError(t, err)
+		require.NoError(t, f.store.CreateWebhook(ctx, webhook))
+
+		output, err := f.usecase.ExecuteReverseTransfer(ctx, correlatedID, false)
+		require.NoError(t, err)
+		assert.Equal(t, correlatedID, output.TransferCorrelatedID)
+		assert.Equal(t, payee.ID, output.PayerID)
+		assert.Equal(t, payer.ID, output.PayeeID)
+		assert.Equal(t, 100*entity.Real, f.balance(t, payer.ID))
+		assert.Equal(t, entity.Money(0), f.balance(t, payee.ID))
+
+		deliveries, err := f.store.FindWebhookDeliveries(ctx, webhook.ID)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, entity.WebhookEventRefundCreated, deliveries[0].EventType)
+	})
+
+	t.Run("dry run rolls back", func(t *testing.T) {
+		f := newFixture(t)
+		payer, payee, correlatedID := transfer(t, f, 40*entity.Real)
+
+		output, err := f.usecase.ExecuteReverseTransfer(ctx, correlatedID, true)
+		require.NoError(t, err)
+		assert.True(t, output.DryRun)
+		assert.Equal(t, 60*entity.Real, f.balance(t, payer.ID))
+		assert.Equal(t, 40*entity.Real, f.balance(t, payee.ID))
+
+		reversals, err := f.store.FindTransactionsByCorrelatedID(ctx, entity.ReversalCorrelatedID(correlatedID))
+		require.NoError(t, err)
+		assert.Empty(t, reversals)
+
+		_, err = f.usecase.ExecuteReverseTransfer(ctx, correlatedID, false)
+		assert.NoError(t, err, "a dry run does not count as the reversal")
+	})
+
+	t.Run("a transfer is reversed once", func(t *testing.T) {
+		f := newFixture(t)
+		payer, payee, correlatedID := transfer(t, f, 40*entity.Real)
+
+		_, err := f.usecase.ExecuteReverseTransfer(ctx, correlatedID, false)
+		require.NoError(t, err)
+
+		_, err = f.usecase.ExecuteReverseTransfer(ctx, correlatedID, false)
+		assert.ErrorIs(t, err, entity.ErrAlreadyReversed)
+		assert.Equal(t, 100*entity.Real, f.balance(t, payer.ID))
+		assert.Equal(t, entity.Money(0), f.balance(t, payee.ID))
+	})
+
+	t.Run("insufficient balance", func(t *testing.T) {
+		f := newFixture(t)
+		payer, payee, correlatedID := transfer(t, f, 40*entity.Real)
+		other := f.account(t, entity.Personal, 0)
+		_, err := f.usecase.ExecuteTransfer(ctx, payee.ID, other.ID, uint64(30*entity.Real), "")
+		require.NoError(t, err)
+
+		_, err = f.usecase.ExecuteReverseTransfer(ctx, correlatedID, false)
+		assert.ErrorIs(t, err, entity.ErrInsufficientBalance)
+		assert.Equal(t, 60*entity.Real, f.balance(t, payer.ID))
+		assert.Equal(t, 10*entity.Real, f.balance(t, payee.ID))
+	})
+
+	t.Run("unknown transfer", func(t *testing.T) {
+		f := newFixture(t)
+
+		_, err := f.usecase.ExecuteReverseTransfer(ctx, uuid.New(), false)
+		assert.ErrorIs(t, err, entity.ErrTransferNotFound)
+	})
+}
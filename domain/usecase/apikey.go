@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/internal/logger"
+	"go.uber.org/zap"
+)
+
+// apiKeyTouchInterval limits how often a key in use writes its last use.
+const apiKeyTouchInterval = time.Minute
+
+type APIKeyUseCase interface {
+	ExecuteNewAPIKey(ctx context.Context, accountID uuid.UUID, input NewAPIKeyInput) (*APIKeyOutput, error)
+	FindAPIKeys(ctx context.Context, accountID uuid.UUID) ([]*APIKeyOutput, error)
+	ExecuteRotateAPIKey(ctx context.Context, accountID, keyID uuid.UUID) (*APIKeyOutput, error)
+	ExecuteRevokeAPIKey(ctx context.Context, accountID, keyID uuid.UUID) error
+	AuthenticateAPIKey(ctx context.Context, key string) (json.RawMessage, error)
+}
+
+type apiKeyUseCase struct {
+	accounts gateway.AccountRepository
+	keys     gateway.APIKeyRepository
+}
+
+func NewAPIKeyUseCase(d Dependencies) APIKeyUseCase {
+	return &apiKeyUseCase{
+		accounts: d.Accounts,
+		keys:     d.APIKeys,
+	}
+}
+
+// apiKeyClaims are the claims a request authenticated with an API key carries, in place of those of
+// an access token.
+type apiKeyClaims struct {
+	AccountID   uuid.UUID          `json:"account_id"`
+	AccountType entity.AccountType `json:"account_type"`
+	Role        entity.Role        `json:"role"`
+	Scope       string             `json:"scope"`
+	APIKeyID    uuid.UUID          `json:"api_key_id"`
+}
+
+func (u *apiKeyUseCase) ExecuteNewAPIKey(ctx context.Context, accountID uuid.UUID, input NewAPIKeyInput) (*APIKeyOutput, error) {
+	account, err := u.accounts.FindResumeAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, notFoundAs(entity.ErrAccountNotFound, err)
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, entity.ErrInvalidArgument.New("api key name is empty")
+	}
+
+	scopes, err := entity.ParseAPIKeyScopes(accountRole(account), input.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.create(ctx, account.ID, name, scopes)
+}
+
+func (u *apiKeyUseCase) FindAPIKeys(ctx context.Context, accountID uuid.UUID) ([]*APIKeyOutput, error) {
+	keys, err := u.keys.FindAPIKeysByAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*APIKeyOutput, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, buildAPIKeyOutput(*key))
+	}
+
+	return result, nil
+}
+
+// ExecuteRotateAPIKey issues a key with the name and scopes of an active one and revokes the old key.
+// The old key stops working right away, so clients should deploy the new key before rotating again.
+func (u *apiKeyUseCase) ExecuteRotateAPIKey(ctx context.Context, accountID, keyID uuid.UUID) (*APIKeyOutput, error) {
+	key, err := u.findOwnedAPIKey(ctx, accountID, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !key.Active() {
+		return nil, entity.ErrAPIKeyNotFound.Errorf("api key %s is revoked", keyID)
+	}
+
+	tx, err := u.keys.NewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	txCtx := gateway.InjectTransaction(ctx, tx)
+	output, err := u.create(txCtx, accountID, key.Name, key.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.keys.RevokeAPIKey(txCtx, key.ID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+func (u *apiKeyUseCase) ExecuteRevokeAPIKey(ctx context.Context, accountID, keyID uuid.UUID) error {
+	if _, err := u.findOwnedAPIKey(ctx, accountID, keyID); err != nil {
+		return err
+	}
+
+	return u.keys.RevokeAPIKey(ctx, keyID, time.Now())
+}
+
+// AuthenticateAPIKey checks the key and returns the claims of its account. The scope is narrowed
+// to the permissions the account role still grants, and canceled accounts are rejected. Every
+// failure reads the same, so a caller cannot tell a wrong secret from an unknown prefix.
+func (u *apiKeyUseCase) AuthenticateAPIKey(ctx context.Context, value string) (json.RawMessage, error) {
+	prefix, secret, ok := entity.SplitAPIKey(value)
+	if !ok {
+		return nil, errInvalidAPIKey()
+	}
+
+	key, err := u.keys.FindAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errInvalidAPIKey()
+		}
+		return nil, err
+	}
+
+	if !key.Active() || !key.Matches(secret) {
+		return nil, errInvalidAPIKey()
+	}
+
+	// every request with a key reads the account, so only the columns the claims need are read
+	account, err := u.accounts.FindResumeAccountByID(ctx, key.AccountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errInvalidAPIKey()
+		}
+		return nil, err
+	}
+
+	if account.Status == entity.AccountStatusCanceled {
+		return nil, errInvalidAPIKey()
+	}
+
+	role := accountRole(account)
+	scope := key.Scope(role)
+	if scope == "" {
+		// an empty scope would fall back to the permissions of the role
+		return nil, errInvalidAPIKey()
+	}
+
+	now := time.Now().UTC()
+	if !key.LastUsedAt.Valid || now.Sub(key.LastUsedAt.Time) >= apiKeyTouchInterval {
+		if err := u.keys.TouchAPIKey(ctx, key.ID, now); err != nil {
+			logger.Logger.Error("Error in touch api key", zap.String("api_key_id", key.ID.String()), zap.Error(err))
+		}
+	}
+
+	return json.Marshal(apiKeyClaims{
+		AccountID:   account.ID,
+		AccountType: account.AccountType,
+		Role:        role,
+		Scope:       scope,
+		APIKeyID:    key.ID,
+	})
+}
+
+func (u *apiKeyUseCase) create(ctx context.Context, accountID uuid.UUID, name string, scopes []entity.Permission) (*APIKeyOutput, error) {
+	key, value := entity.NewAPIKey(accountID, name, scopes)
+	if err := u.keys.CreateAPIKey(ctx, key); err != nil {
+		return nil, err
+	}
+
+	output := buildAPIKeyOutput(key)
+	output.Key = value
+	return output, nil
+}
+
+func (u *apiKeyUseCase) findOwnedAPIKey(ctx context.Context, accountID, keyID uuid.UUID) (*entity.APIKey, error) {
+	key, err := u.keys.FindAPIKey(ctx, keyID)
+	if err != nil {
+		return nil, notFoundAs(entity.ErrAPIKeyNotFound, err)
+	}
+
+	if key.AccountID != accountID {
+		return nil, entity.ErrAPIKeyNotFound.Errorf("api key %s not found", keyID)
+	}
+
+	return key, nil
+}
+
+// accountRole is the role of the account, or the default one of its type for accounts created before
+// roles existed.
+func accountRole(account *entity.ResumeAccount) entity.Role {
+	if account.Role == "" {
+		return entity.DefaultRole(account.AccountType)
+	}
+
+	return account.Role
+}
+
+func errInvalidAPIKey() error {
+	return entity.ErrUnauthenticated.New("invalid api key")
+}
+
+func buildAPIKeyOutput(key entity.APIKey) *APIKeyOutput {
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, p := range key.Scopes {
+		scopes = append(scopes, string(p))
+	}
+
+	output := &APIKeyOutput{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    scopes,
+		CreatedAt: key.CreatedAt,
+	}
+
+	if key.LastUsedAt.Valid {
+		output.LastUsedAt = &key.LastUsedAt.Time
+	}
+
+	if key.RevokedAt.Valid {
+		output.RevokedAt = &key.RevokedAt.Time
+	}
+
+	return output
+}
@@ -0,0 +1,135 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/guilhermealvess/guicpay/internal/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKey(t *testing.T) {
+	ctx := context.Background()
+	token.InitJWT("secret", time.Minute)
+
+	type keyClaims struct {
+		AccountID uuid.UUID   `json:"account_id"`
+		Role      entity.Role `json:"role"`
+		Scope     string      `json:"scope"`
+		APIKeyID  uuid.UUID   `json:"api_key_id"`
+	}
+
+	setup := func(t *testing.T) (*fixture, usecase.APIKeyUseCase, entity.Account) {
+		f := newFixture(t)
+		keys := usecase.NewAPIKeyUseCase(f.deps)
+		token.UseAPIKeys(keys)
+		t.Cleanup(func() { token.UseAPIKeys(nil) })
+		return f, keys, f.account(t, entity.Personal, 0)
+	}
+
+	authenticate := func(key string) (keyClaims, error) {
+		var c keyClaims
+		err := token.Middle(ctx, "Bearer "+key, &c)
+		return c, err
+	}
+
+	t.Run("authenticates with its scopes", func(t *testing.T) {
+		f, keys, account := setup(t)
+
+		created, err := keys.ExecuteNewAPIKey(ctx, account.ID, usecase.NewAPIKeyInput{Name: "backend", Scopes: []string{"account:read"}})
+		require.NoError(t, err)
+		assert.NotEmpty(t, created.Key)
+		assert.Contains(t, created.Key, created.Prefix)
+
+		c, err := authenticate(created.Key)
+		require.NoError(t, err)
+		assert.Equal(t, account.ID, c.AccountID)
+		assert.Equal(t, created.ID, c.APIKeyID)
+		assert.Equal(t, "account:read", c.Scope)
+		assert.False(t, entity.PermissionTransfer.GrantedBy(c.Scope))
+
+		stored, err := f.store.FindAPIKey(ctx, created.ID)
+		require.NoError(t, err)
+		assert.True(t, stored.LastUsedAt.Valid)
+
+		listed, err := keys.FindAPIKeys(ctx, account.ID)
+		require.NoError(t, err)
+		require.Len(t, listed, 1)
+		assert.Empty(t, listed[0].Key)
+		assert.NotNil(t, listed[0].LastUsedAt)
+
+		_, err = authenticate(created.Prefix + "_wrong")
+		assert.ErrorIs(t, err, entity.ErrUnauthenticated)
+	})
+
+	t.Run("scopes", func(t *testing.T) {
+		_, keys, account := setup(t)
+
+		for _, scopes := range [][]string{nil, {"accounts:list"}, {"apikey:manage"}} {
+			_, err := keys.ExecuteNewAPIKey(ctx, account.ID, usecase.NewAPIKeyInput{Name: "backend", Scopes: scopes})
+			assert.ErrorIs(t, err, entity.ErrInvalidArgument, scopes)
+		}
+	})
+
+	t.Run("role change narrows the key", func(t *testing.T) {
+		f, keys, account := setup(t)
+
+		created, err := keys.ExecuteNewAPIKey(ctx, account.ID, usecase.NewAPIKeyInput{Name: "backend", Scopes: []string{"account:read", "transaction:transfer"}})
+		require.NoError(t, err)
+
+		_, err = f.usecase.ExecuteChangeRole(ctx, account.ID, string(entity.RoleSeller), false)
+		require.NoError(t, err)
+
+		c, err := authenticate(created.Key)
+		require.NoError(t, err)
+		assert.Equal(t, "account:read", c.Scope)
+	})
+
+	t.Run("rotate and revoke", func(t *testing.T) {
+		_, keys, account := setup(t)
+		other := uuid.New()
+
+		created, err := keys.ExecuteNewAPIKey(ctx, account.ID, usecase.NewAPIKeyInput{Name: "backend", Scopes: []string{"transaction:deposit"}})
+		require.NoError(t, err)
+
+		_, err = keys.ExecuteRotateAPIKey(ctx, other, created.ID)
+		assert.ErrorIs(t, err, entity.ErrAPIKeyNotFound)
+
+		rotated, err := keys.ExecuteRotateAPIKey(ctx, account.ID, created.ID)
+		require.NoError(t, err)
+		assert.NotEqual(t, created.ID, rotated.ID)
+		assert.Equal(t, "backend", rotated.Name)
+		assert.Equal(t, []string{"transaction:deposit"}, rotated.Scopes)
+
+		_, err = authenticate(created.Key)
+		assert.ErrorIs(t, err, entity.ErrUnauthenticated)
+		_, err = authenticate(rotated.Key)
+		require.NoError(t, err)
+
+		_, err = keys.ExecuteRotateAPIKey(ctx, account.ID, created.ID)
+		assert.ErrorIs(t, err, entity.ErrAPIKeyNotFound)
+
+		assert.ErrorIs(t, keys.ExecuteRevokeAPIKey(ctx, other, rotated.ID), entity.ErrAPIKeyNotFound)
+		require.NoError(t, keys.ExecuteRevokeAPIKey(ctx, account.ID, rotated.ID))
+		_, err = authenticate(rotated.Key)
+		assert.ErrorIs(t, err, entity.ErrUnauthenticated)
+	})
+
+	t.Run("canceled account", func(t *testing.T) {
+		f, keys, account := setup(t)
+
+		created, err := keys.ExecuteNewAPIKey(ctx, account.ID, usecase.NewAPIKeyInput{Name: "backend", Scopes: []string{"transaction:transfer"}})
+		require.NoError(t, err)
+
+		_, err = f.usecase.ExecuteChangeStatus(ctx, account.ID, string(entity.AccountStatusCanceled), false)
+		require.NoError(t, err)
+
+		_, err = authenticate(created.Key)
+		assert.ErrorIs(t, err, entity.ErrUnauthenticated)
+	})
+}
@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+)
+
+type actorContextKey string
+
+const ActorContextKey actorContextKey = "ActorContextKey"
+
+// InjectActor records who makes the request, for the audit entries of the actions it runs.
+func InjectActor(ctx context.Context, actor entity.AuditActor) context.Context {
+	return context.WithValue(ctx, ActorContextKey, actor)
+}
+
+// GetActor returns the actor of the request, the zero actor when none was injected.
+func GetActor(ctx context.Context) entity.AuditActor {
+	actor, _ := ctx.Value(ActorContextKey).(entity.AuditActor)
+	return actor
+}
+
+// audit appends an entry for the action on the subject account. With a transaction in the context
+// the entry is written in it, so it commits or rolls back with the action.
+func audit(ctx context.Context, repository gateway.AuditRepository, action entity.AuditAction, subjectID uuid.UUID, before, after any) error {
+	entry, err := entity.NewAuditEntry(action, GetActor(ctx), subjectID, before, after)
+	if err != nil {
+		return err
+	}
+
+	return repository.SaveAuditEntry(ctx, entry)
+}
+
+// auditAccount is the data of an account audit entries keep: never its password.
+type auditAccount struct {
+	ID          uuid.UUID            `json:"account_id"`
+	AccountType entity.AccountType   `json:"account_type"`
+	Role        entity.Role          `json:"role"`
+	Name        string               `json:"customer_name"`
+	Email       string               `json:"email"`
+	Status      entity.AccountStatus `json:"status"`
+}
+
+type auditStatus struct {
+	Status entity.AccountStatus `json:"status"`
+}
+
+type auditRole struct {
+	Role entity.Role `json:"role"`
+}
+
+type auditPasswordReset struct {
+	Generated bool `json:"generated"`
+}
+
+type auditLoginAttempts struct {
+	Email    string `json:"email,omitempty"`
+	Failures int    `json:"failures,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type auditBalance struct {
+	Balance Money `json:"balance"`
+}
+
+type auditDeposit struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	Amount        Money     `json:"amount"`
+	Balance       Money     `json:"balance"`
+}
+
+type auditBalances struct {
+	PayerBalance Money `json:"payer_balance"`
+	PayeeBalance Money `json:"payee_balance"`
+}
+
+// auditTransfer is the data of a transfer or of a reversal, which also names the transfer it gives
+// back.
+type auditTransfer struct {
+	CorrelatedID         uuid.UUID  `json:"correlated_id"`
+	TransferCorrelatedID *uuid.UUID `json:"transfer_correlated_id,omitempty"`
+	PayerID              uuid.UUID  `json:"payer_id"`
+	PayeeID              uuid.UUID  `json:"payee_id"`
+	Amount               Money      `json:"amount"`
+	auditBalances
+}
+
+type auditSnapshot struct {
+	SnapshotID   uuid.UUID `json:"snapshot_id"`
+	Transactions int       `json:"transactions"`
+	Balance      Money     `json:"balance"`
+}
+
+func newAuditAccount(account entity.Account) auditAccount {
+	return auditAccount{
+		ID:          account.ID,
+		AccountType: account.AccountType,
+		Role:        account.Role,
+		Name:        account.CustomerName,
+		Email:       account.Email,
+		Status:      account.Status,
+	}
+}
+
+func newAuditBalances(payer, payee *entity.Account) auditBalances {
+	return auditBalances{PayerBalance: Money(payer.Wallet.Balance()), PayeeBalance: Money(payee.Wallet.Balance())}
+}
+
+func (u *accountUseCase) ListAuditEntries(ctx context.Context, input AuditListInput) (*AuditListOutput, error) {
+	filter := entity.AuditFilter{
+		ActorID:   input.ActorID,
+		SubjectID: input.SubjectID,
+		RequestID: input.RequestID,
+		From:      input.From,
+		To:        input.To,
+		Limit:     input.Limit,
+	}
+
+	for _, a := range input.Actions {
+		filter.Actions = append(filter.Actions, entity.AuditAction(a))
+	}
+
+	if input.Cursor != "" {
+		cursor, err := entity.ParseAuditCursor(input.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.Cursor = cursor
+	}
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	// one extra entry tells whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+	entries, err := u.audit.FindAuditEntries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	output := AuditListOutput{Entries: make([]*AuditEntryOutput, 0, limit)}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		last := entries[limit-1]
+		output.NextCursor = entity.AuditCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	for _, e := range entries {
+		output.Entries = append(output.Entries, buildAuditEntryOutput(e))
+	}
+
+	return &output, nil
+}
+
+func buildAuditEntryOutput(e *entity.AuditEntry) *AuditEntryOutput {
+	return &AuditEntryOutput{
+		ID:        e.ID,
+		Action:    string(e.Action),
+		ActorID:   optionalID(e.Actor.AccountID),
+		ActorRole: string(e.Actor.Role),
+		APIKeyID:  optionalID(e.Actor.APIKeyID),
+		SubjectID: optionalID(e.SubjectID),
+		IP:        e.Actor.IP,
+		UserAgent: e.Actor.UserAgent,
+		RequestID: e.Actor.RequestID,
+		Before:    e.Before,
+		After:     e.After,
+		CreatedAt: e.CreatedAt.In(time.UTC),
+	}
+}
+
+func optionalID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+
+	return &id
+}
@@ -0,0 +1,183 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/guilhermealvess/guicpay/internal/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	token.InitJWT("secret", time.Minute)
+	admin := entity.AuditActor{AccountID: uuid.New(), Role: entity.RoleAdmin, IP: "10.0.0.1", UserAgent: "curl/8.0", RequestID: "req-1"}
+	ctx := usecase.InjectActor(context.Background(), admin)
+
+	entries := func(t *testing.T, f *fixture, filter entity.AuditFilter) []*entity.AuditEntry {
+		t.Helper()
+		found, err := f.store.FindAuditEntries(context.Background(), filter)
+		require.NoError(t, err)
+		return found
+	}
+
+	t.Run("transfer", func(t *testing.T) {
+		f := newFixture(t)
+		payer := f.account(t, entity.Personal, 100*entity.Real)
+		payee := f.account(t, entity.Seller, 0)
+
+		correlatedID, err := f.usecase.ExecuteTransfer(ctx, payer.ID, payee.ID, uint64(30*entity.Real), "")
+		require.NoError(t, err)
+
+		found := entries(t, f, entity.AuditFilter{Actions: []entity.AuditAction{entity.AuditTransfer}})
+		require.Len(t, found, 1)
+		assert.Equal(t, admin, found[0].Actor)
+		assert.Equal(t, payer.ID, found[0].SubjectID)
+		assert.JSONEq(t, `{"payer_balance":{"amount":10000,"currency":"BRL"},"payee_balance":{"amount":0,"currency":"BRL"}}`, string(found[0].Before))
+
+		var after struct {
+			CorrelatedID uuid.UUID       `json:"correlated_id"`
+			PayerBalance json.RawMessage `json:"payer_balance"`
+			PayeeBalance json.RawMessage `json:"payee_balance"`
+		}
+		require.NoError(t, json.Unmarshal(found[0].After, &after))
+		assert.Equal(t, correlatedID, after.CorrelatedID)
+		assert.JSONEq(t, `{"amount":7000,"currency":"BRL"}`, string(after.PayerBalance))
+		assert.JSONEq(t, `{"amount":3000,"currency":"BRL"}`, string(after.PayeeBalance))
+	})
+
+	t.Run("failed actions leave no entry", func(t *testing.T) {
+		f := newFixture(t)
+		payer := f.account(t, entity.Personal, 100*entity.Real)
+		payee := f.account(t, entity.Seller, 0)
+
+		f.authorizer.Fail(assert.AnError)
+		_, err := f.usecase.ExecuteTransfer(ctx, payer.ID, payee.ID, uint64(30*entity.Real), "")
+		require.Error(t, err)
+
+		_, err = f.usecase.ExecuteChangeStatus(ctx, payer.ID, string(entity.AccountStatusCanceled), true)
+		require.NoError(t, err)
+
+		assert.Empty(t, entries(t, f, entity.AuditFilter{SubjectID: payer.ID}))
+	})
+
+	t.Run("admin actions", func(t *testing.T) {
+		f := newFixture(t)
+		account := f.account(t, entity.Personal, 0)
+
+		_, err := f.usecase.ExecuteChangeStatus(ctx, account.ID, string(entity.AccountStatusCanceled), false)
+		require.NoError(t, err)
+		_, err = f.usecase.ExecuteChangeRole(ctx, account.ID, string(entity.RoleSupport), false)
+		require.NoError(t, err)
+		_, err = f.usecase.ExecuteResetPassword(ctx, account.ID, "", false)
+		require.NoError(t, err)
+
+		found := entries(t, f, entity.AuditFilter{SubjectID: account.ID})
+		actions := make(map[entity.AuditAction]*entity.AuditEntry)
+		for _, e := range found {
+			actions[e.Action] = e
+		}
+
+		require.Contains(t, actions, entity.AuditAccountStatusChanged)
+		assert.JSONEq(t, `{"status":"ACTIVE"}`, string(actions[entity.AuditAccountStatusChanged].Before))
+		assert.JSONEq(t, `{"status":"CANCELED"}`, string(actions[entity.AuditAccountStatusChanged].After))
+		require.Contains(t, actions, entity.AuditAccountRoleChanged)
+		assert.JSONEq(t, `{"role":"SUPPORT"}`, string(actions[entity.AuditAccountRoleChanged].After))
+		require.Contains(t, actions, entity.AuditPasswordReset)
+		assert.JSONEq(t, `{"generated":true}`, string(actions[entity.AuditPasswordReset].After))
+	})
+
+	t.Run("forced snapshot", func(t *testing.T) {
+		f := newFixture(t)
+		account := f.account(t, entity.Personal, 10*entity.Real)
+
+		_, err := f.usecase.ExecuteForceSnapshot(ctx, account.ID, true)
+		require.NoError(t, err)
+		assert.Empty(t, entries(t, f, entity.AuditFilter{Actions: []entity.AuditAction{entity.AuditSnapshotForced}}))
+
+		snapshot, err := f.usecase.ExecuteForceSnapshot(ctx, account.ID, false)
+		require.NoError(t, err)
+
+		found := entries(t, f, entity.AuditFilter{Actions: []entity.AuditAction{entity.AuditSnapshotForced}})
+		require.Len(t, found, 1)
+		assert.Equal(t, admin, found[0].Actor)
+		assert.Equal(t, account.ID, found[0].SubjectID)
+		assert.JSONEq(t, `{"snapshot_id":"`+snapshot.ID.String()+`","transactions":1,"balance":{"amount":1000,"currency":"BRL"}}`, string(found[0].After))
+
+		// automatic snapshots are not audited
+		f.usecase.ExecuteSnapshotTransaction(ctx, account.ID)
+		assert.Len(t, entries(t, f, entity.AuditFilter{SubjectID: account.ID}), 1)
+	})
+
+	t.Run("verification activates the account", func(t *testing.T) {
+		f := newFixture(t)
+		id := uuid.NewString()
+		accountID, err := f.usecase.ExecuteNewAccount(ctx, usecase.NewAccountInput{
+			Name: "Fulano De Tal", Email: id + "@example.com", Password: "PASSWORD", Type: "personal", DocumentNumber: id, PhoneNumber: "+5511999999999",
+		})
+		require.NoError(t, err)
+
+		for _, n := range f.notifier.Codes() {
+			_, err := f.usecase.ExecuteVerify(ctx, accountID, usecase.VerifyInput{Channel: string(n.Channel), Code: n.Code})
+			require.NoError(t, err)
+		}
+
+		found := entries(t, f, entity.AuditFilter{Actions: []entity.AuditAction{entity.AuditAccountStatusChanged}})
+		require.Len(t, found, 1)
+		assert.Equal(t, accountID, found[0].SubjectID)
+		assert.JSONEq(t, `{"status":"PENDING_VERIFICATION"}`, string(found[0].Before))
+		assert.JSONEq(t, `{"status":"ACTIVE"}`, string(found[0].After))
+	})
+
+	t.Run("logins", func(t *testing.T) {
+		f := newFixture(t)
+		auth := usecase.NewAuthUseCase(f.deps)
+		account := f.account(t, entity.Personal, 0)
+		anonymous := usecase.InjectActor(context.Background(), entity.AuditActor{IP: "10.0.0.2", UserAgent: "app/1.0"})
+
+		_, err := auth.ExecuteLogin(anonymous, usecase.LoginInput{Email: account.Email, Password: "WRONG"})
+		require.Error(t, err)
+		_, err = auth.ExecuteLogin(anonymous, usecase.LoginInput{Email: account.Email, Password: "PASSWORD"})
+		require.NoError(t, err)
+
+		failed := entries(t, f, entity.AuditFilter{Actions: []entity.AuditAction{entity.AuditLoginFailed}})
+		require.Len(t, failed, 1)
+		assert.Equal(t, uuid.Nil, failed[0].Actor.AccountID)
+		assert.Equal(t, "10.0.0.2", failed[0].Actor.IP)
+		assert.JSONEq(t, `{"email":"`+account.Email+`","error":"INVALID_CREDENTIALS"}`, string(failed[0].After))
+
+		login := entries(t, f, entity.AuditFilter{Actions: []entity.AuditAction{entity.AuditLogin}})
+		require.Len(t, login, 1)
+		assert.Equal(t, account.ID, login[0].Actor.AccountID)
+		assert.Equal(t, account.ID, login[0].SubjectID)
+		assert.Equal(t, "app/1.0", login[0].Actor.UserAgent)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		f := newFixture(t)
+		account := f.account(t, entity.Personal, 0)
+		for range 3 {
+			_, err := f.usecase.ExecuteDeposit(ctx, account.ID, uint64(entity.Real))
+			require.NoError(t, err)
+		}
+
+		page, err := f.usecase.ListAuditEntries(ctx, usecase.AuditListInput{Actions: []string{"transaction.deposit"}, SubjectID: account.ID, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, page.Entries, 2)
+		require.NotEmpty(t, page.NextCursor)
+		assert.Equal(t, admin.AccountID, *page.Entries[0].ActorID)
+
+		rest, err := f.usecase.ListAuditEntries(ctx, usecase.AuditListInput{SubjectID: account.ID, Cursor: page.NextCursor})
+		require.NoError(t, err)
+		require.Len(t, rest.Entries, 1)
+		assert.Empty(t, rest.NextCursor)
+
+		_, err = f.usecase.ListAuditEntries(ctx, usecase.AuditListInput{Actions: []string{"unknown"}})
+		assert.ErrorIs(t, err, entity.ErrUnprocessableEntity)
+	})
+}
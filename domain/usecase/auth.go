@@ -0,0 +1,289 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/internal/logger"
+	"github.com/guilhermealvess/guicpay/internal/properties"
+	"go.uber.org/zap"
+)
+
+type AuthUseCase interface {
+	ExecuteLogin(ctx context.Context, input LoginInput) (*SessionOutput, error)
+	ExecuteRefresh(ctx context.Context, refreshToken string) (*SessionOutput, error)
+	ExecuteLogout(ctx context.Context, input LogoutInput) error
+	ExecuteEnrollTOTP(ctx context.Context, accountID uuid.UUID) (*TOTPEnrollmentOutput, error)
+	ExecuteConfirmTOTP(ctx context.Context, accountID uuid.UUID, code string) error
+	ExecuteDisableTOTP(ctx context.Context, accountID uuid.UUID, code string) error
+	ExecuteRequestPasswordReset(ctx context.Context, email string) error
+	ExecutePasswordReset(ctx context.Context, input PasswordResetInput) error
+	ExecuteChangePassword(ctx context.Context, input ChangePasswordInput) error
+}
+
+type authUseCase struct {
+	accounts gateway.AccountRepository
+	tokens   gateway.TokenRepository
+	mfa      gateway.MFARepository
+	attempts gateway.LoginAttemptRepository
+	audit    gateway.AuditRepository
+	signer   gateway.TokenSigner
+	notifier gateway.NotificationService
+}
+
+func NewAuthUseCase(d Dependencies) AuthUseCase {
+	return &authUseCase{
+		accounts: d.Accounts,
+		tokens:   d.Tokens,
+		mfa:      d.MFA,
+		attempts: d.LoginAttempts,
+		audit:    d.Audit,
+		signer:   d.Signer,
+		notifier: d.Notifier,
+	}
+}
+
+// accessClaims are the claims of an access token: jti identifies the token and sid the session,
+// the refresh token family, so either can be revoked. The scope lists the permissions of the role
+// when the token was issued; a role change applies from the next refresh.
+type accessClaims struct {
+	ID          uuid.UUID          `json:"jti"`
+	SessionID   uuid.UUID          `json:"sid"`
+	AccountID   uuid.UUID          `json:"account_id"`
+	AccountType entity.AccountType `json:"account_type"`
+	Role        entity.Role        `json:"role"`
+	Scope       string             `json:"scope"`
+}
+
+// ExecuteLogin checks the password and, for accounts with two-factor authentication enabled, the
+// one-time password as a second step: without one the login fails with OTP_REQUIRED. Wrong
+// passwords and codes count against the email and the client address, which are throttled and
+// then locked out after repeated failures; a successful login clears the email count.
+func (u *authUseCase) ExecuteLogin(ctx context.Context, input LoginInput) (*SessionOutput, error) {
+	now := time.Now().UTC()
+	keys := loginKeys(input)
+	if err := checkThrottle(ctx, u.attempts, keys, now); err != nil {
+		u.auditLoginFailure(ctx, input, err)
+		return nil, err
+	}
+
+	account, err := u.authenticate(ctx, input)
+	if errors.Is(err, entity.ErrInvalidCredentials) || errors.Is(err, entity.ErrInvalidOTP) {
+		recordFailure(ctx, u.attempts, keys, now)
+		u.auditLoginFailure(ctx, input, err)
+		return nil, err
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.attempts.DeleteLoginAttempts(ctx, keys[0].key); err != nil {
+		return nil, err
+	}
+
+	tx, err := u.tokens.NewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// the account logging in is the actor of its own login
+	actor := GetActor(ctx)
+	actor.AccountID, actor.Role = account.ID, account.Role
+	txCtx := InjectActor(gateway.InjectTransaction(ctx, tx), actor)
+	session, err := u.issue(txCtx, account.ID, account.AccountType, account.Role, uuid.New())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := audit(txCtx, u.audit, entity.AuditLogin, account.ID, nil, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (u *authUseCase) authenticate(ctx context.Context, input LoginInput) (*entity.ResumeAccount, error) {
+	account, err := u.accounts.FindResumeAccount(ctx, input.Email)
+	if errors.Is(err, sql.ErrNoRows) {
+		decoyPassword.Compare(input.Password)
+		return nil, errLoginFailed()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := account.ValidatePassword(input.Password); err != nil {
+		return nil, errLoginFailed()
+	}
+
+	// a canceled account fails like a wrong password, so the response does not reveal it exists
+	if account.Status == entity.AccountStatusCanceled {
+		return nil, errLoginFailed()
+	}
+
+	totp, err := findEnabledTOTP(ctx, u.mfa, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if totp != nil {
+		if input.OTP == "" {
+			return nil, entity.ErrOTPRequired.New("two-factor authentication enabled, send a one-time password")
+		}
+
+		if err := consumeOTP(ctx, u.mfa, totp, input.OTP); err != nil {
+			return nil, err
+		}
+	}
+
+	return account, nil
+}
+
+// ExecuteRefresh exchanges a refresh token for a new access token and the next refresh token of
+// the same family. A token exchanged twice revokes the whole family, and a canceled account can no
+// longer refresh.
+func (u *authUseCase) ExecuteRefresh(ctx context.Context, refreshToken string) (*SessionOutput, error) {
+	current, err := u.tokens.FindRefreshToken(ctx, entity.HashToken(refreshToken))
+	if err != nil {
+		return nil, notFoundAs(entity.ErrInvalidRefreshToken, err)
+	}
+
+	now := time.Now().UTC()
+	if current.UsedAt.Valid && !current.RevokedAt.Valid {
+		return nil, u.revokeReusedFamily(ctx, current)
+	}
+
+	if !current.Active(now) {
+		return nil, entity.ErrInvalidRefreshToken.New("refresh token expired or revoked")
+	}
+
+	account, err := u.accounts.FindAccount(ctx, current.AccountID)
+	if err != nil {
+		return nil, notFoundAs(entity.ErrInvalidRefreshToken, err)
+	}
+
+	if account.Status == entity.AccountStatusCanceled {
+		return nil, entity.ErrInvalidRefreshToken.New("account canceled")
+	}
+
+	tx, err := u.tokens.NewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	txCtx := gateway.InjectTransaction(ctx, tx)
+	used, err := u.tokens.UseRefreshToken(txCtx, current.ID, now)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if !used {
+		// another request exchanged it first
+		tx.Rollback()
+		return nil, u.revokeReusedFamily(ctx, current)
+	}
+
+	output, err := u.issue(txCtx, account.ID, account.AccountType, account.Role, current.FamilyID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// ExecuteLogout revokes the access token and the session it belongs to, refresh tokens included.
+func (u *authUseCase) ExecuteLogout(ctx context.Context, input LogoutInput) error {
+	revoked := make([]entity.RevokedToken, 0, 2)
+	if input.TokenID != uuid.Nil {
+		revoked = append(revoked, entity.NewRevokedToken(input.TokenID, input.ExpiresAt))
+	}
+
+	if input.SessionID != uuid.Nil {
+		if err := u.tokens.RevokeTokenFamily(ctx, input.SessionID, time.Now().UTC()); err != nil {
+			return err
+		}
+
+		revoked = append(revoked, u.revokedSession(input.SessionID))
+	}
+
+	if len(revoked) == 0 {
+		return entity.ErrInvalidArgument.New("token has no id to revoke")
+	}
+
+	return u.tokens.RevokeTokens(ctx, revoked...)
+}
+
+func (u *authUseCase) issue(ctx context.Context, accountID uuid.UUID, accountType entity.AccountType, role entity.Role, familyID uuid.UUID) (*SessionOutput, error) {
+	if role == "" {
+		role = entity.DefaultRole(accountType)
+	}
+
+	refresh, value := entity.NewRefreshToken(accountID, familyID, properties.Props.JWT.RefreshExpire)
+	if err := u.tokens.SaveRefreshToken(ctx, refresh); err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(accessClaims{
+		ID:          uuid.New(),
+		SessionID:   familyID,
+		AccountID:   accountID,
+		AccountType: accountType,
+		Role:        role,
+		Scope:       role.Scope(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := u.signer.Generate(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SessionOutput{
+		AccessToken:           access,
+		RefreshToken:          value,
+		RefreshTokenExpiresAt: refresh.ExpiresAt,
+	}, nil
+}
+
+func (u *authUseCase) revokeReusedFamily(ctx context.Context, token *entity.RefreshToken) error {
+	logger.Logger.Warn("Refresh token reused, revoking session",
+		zap.String("account_id", token.AccountID.String()),
+		zap.String("session_id", token.FamilyID.String()))
+
+	if err := u.tokens.RevokeTokenFamily(ctx, token.FamilyID, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	if err := u.tokens.RevokeTokens(ctx, u.revokedSession(token.FamilyID)); err != nil {
+		return err
+	}
+
+	return entity.ErrRefreshTokenReused.New("refresh token already used, session revoked")
+}
+
+// revokedSession covers every access token of the session: none outlives the access token lifetime.
+func (u *authUseCase) revokedSession(sessionID uuid.UUID) entity.RevokedToken {
+	return entity.NewRevokedToken(sessionID, time.Now().Add(properties.Props.JWT.Expire))
+}
@@ -0,0 +1,197 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/guilhermealvess/guicpay/internal/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type claims struct {
+	TokenID   uuid.UUID   `json:"jti"`
+	SessionID uuid.UUID   `json:"sid"`
+	AccountID uuid.UUID   `json:"account_id"`
+	Role      entity.Role `json:"role"`
+	Scope     string      `json:"scope"`
+	ExpiresAt int64       `json:"exp"`
+}
+
+func TestAuthSession(t *testing.T) {
+	ctx := context.Background()
+	token.InitJWT("secret", time.Minute)
+
+	setup := func(t *testing.T) (*fixture, usecase.AuthUseCase, entity.Account) {
+		f := newFixture(t)
+		token.UseRevocationStore(f.store)
+		t.Cleanup(func() { token.UseRevocationStore(nil) })
+		return f, usecase.NewAuthUseCase(f.deps), f.account(t, entity.Personal, 0)
+	}
+
+	access := func(t *testing.T, session *usecase.SessionOutput) (claims, error) {
+		var c claims
+		err := token.Middle(ctx, "Bearer "+session.AccessToken, &c)
+		return c, err
+	}
+
+	t.Run("login", func(t *testing.T) {
+		_, auth, account := setup(t)
+
+		session, err := auth.ExecuteLogin(ctx, usecase.LoginInput{Email: account.Email, Password: "PASSWORD"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, session.RefreshToken)
+
+		c, err := access(t, session)
+		require.NoError(t, err)
+		assert.Equal(t, account.ID, c.AccountID)
+		assert.NotEqual(t, uuid.Nil, c.TokenID)
+		assert.NotEqual(t, uuid.Nil, c.SessionID)
+		assert.Equal(t, entity.RoleCustomer, c.Role)
+		assert.True(t, entity.PermissionTransfer.GrantedBy(c.Scope))
+		assert.False(t, entity.PermissionAccountsList.GrantedBy(c.Scope))
+
+		_, err = auth.ExecuteLogin(ctx, usecase.LoginInput{Email: account.Email, Password: "WRONG"})
+		assert.ErrorIs(t, err, entity.ErrInvalidCredentials)
+
+		_, err = auth.ExecuteLogin(ctx, usecase.LoginInput{Email: "nobody@example.com", Password: "PASSWORD"})
+		assert.ErrorIs(t, err, entity.ErrInvalidCredentials)
+	})
+
+	t.Run("refresh rotates the token within the session", func(t *testing.T) {
+		_, auth, account := setup(t)
+		login, err := auth.ExecuteLogin(ctx, usecase.LoginInput{Email: account.Email, Password: "PASSWORD"})
+		require.NoError(t, err)
+
+		refreshed, err := auth.ExecuteRefresh(ctx, login.RefreshToken)
+		require.NoError(t, err)
+		assert.NotEqual(t, login.RefreshToken, refreshed.RefreshToken)
+
+		before, err := access(t, login)
+		require.NoError(t, err)
+		after, err := access(t, refreshed)
+		require.NoError(t, err)
+		assert.Equal(t, before.SessionID, after.SessionID)
+		assert.NotEqual(t, before.TokenID, after.TokenID)
+
+		_, err = auth.ExecuteRefresh(ctx, "rt_unknown")
+		assert.ErrorIs(t, err, entity.ErrInvalidRefreshToken)
+	})
+
+	t.Run("role change applies on refresh", func(t *testing.T) {
+		f, auth, account := setup(t)
+		login, err := auth.ExecuteLogin(ctx, usecase.LoginInput{Email: account.Email, Password: "PASSWORD"})
+		require.NoError(t, err)
+
+		output, err := f.usecase.ExecuteChangeRole(ctx, account.ID, "admin", false)
+		require.NoError(t, err)
+		assert.Equal(t, "CUSTOMER", output.From)
+		assert.Equal(t, "ADMIN", output.To)
+
+		before, err := access(t, login)
+		require.NoError(t, err)
+		assert.Equal(t, entity.RoleCustomer, before.Role)
+
+		refreshed, err := auth.ExecuteRefresh(ctx, login.RefreshToken)
+		require.NoError(t, err)
+		after, err := access(t, refreshed)
+		require.NoError(t, err)
+		assert.Equal(t, entity.RoleAdmin, after.Role)
+		assert.True(t, entity.PermissionAccountsManage.GrantedBy(after.Scope))
+
+		_, err = f.usecase.ExecuteChangeRole(ctx, account.ID, "root", false)
+		assert.ErrorIs(t, err, entity.ErrInvalidArgument)
+	})
+
+	t.Run("reuse revokes the whole family", func(t *testing.T) {
+		_, auth, account := setup(t)
+		login, err := auth.ExecuteLogin(ctx, usecase.LoginInput{Email: account.Email, Password: "PASSWORD"})
+		require.NoError(t, err)
+		refreshed, err := auth.ExecuteRefresh(ctx, login.RefreshToken)
+		require.NoError(t, err)
+
+		_, err = auth.ExecuteRefresh(ctx, login.RefreshToken)
+		assert.ErrorIs(t, err, entity.ErrRefreshTokenReused)
+
+		_, err = auth.ExecuteRefresh(ctx, refreshed.RefreshToken)
+		assert.ErrorIs(t, err, entity.ErrInvalidRefreshToken)
+
+		_, err = access(t, refreshed)
+		assert.ErrorIs(t, err, token.ErrRevoked)
+
+		other, err := auth.ExecuteLogin(ctx, usecase.LoginInput{Email: account.Email, Password: "PASSWORD"})
+		require.NoError(t, err)
+		_, err = access(t, other)
+		assert.NoError(t, err)
+	})
+
+	t.Run("cancel ends the sessions of the account", func(t *testing.T) {
+		f, auth, account := setup(t)
+		login, err := auth.ExecuteLogin(ctx, usecase.LoginInput{Email: account.Email, Password: "PASSWORD"})
+		require.NoError(t, err)
+
+		_, err = f.usecase.ExecuteChangeStatus(ctx, account.ID, string(entity.AccountStatusCanceled), true)
+		require.NoError(t, err)
+		_, err = access(t, login)
+		require.NoError(t, err)
+
+		_, err = f.usecase.ExecuteChangeStatus(ctx, account.ID, string(entity.AccountStatusCanceled), false)
+		require.NoError(t, err)
+
+		_, err = access(t, login)
+		assert.ErrorIs(t, err, token.ErrRevoked)
+
+		_, err = auth.ExecuteRefresh(ctx, login.RefreshToken)
+		assert.ErrorIs(t, err, entity.ErrInvalidRefreshToken)
+	})
+
+	t.Run("a canceled account cannot refresh", func(t *testing.T) {
+		f, auth, account := setup(t)
+		login, err := auth.ExecuteLogin(ctx, usecase.LoginInput{Email: account.Email, Password: "PASSWORD"})
+		require.NoError(t, err)
+
+		// canceled without going through the use case, so the session was not revoked
+		canceled, err := f.store.FindAccount(ctx, account.ID)
+		require.NoError(t, err)
+		require.NoError(t, canceled.ChangeStatus(entity.AccountStatusCanceled))
+		require.NoError(t, f.store.UpdateAccount(ctx, *canceled))
+
+		_, err = auth.ExecuteRefresh(ctx, login.RefreshToken)
+		assert.ErrorIs(t, err, entity.ErrInvalidRefreshToken)
+	})
+
+	t.Run("a canceled account cannot log in", func(t *testing.T) {
+		f, auth, account := setup(t)
+		_, err := f.usecase.ExecuteChangeStatus(ctx, account.ID, string(entity.AccountStatusCanceled), false)
+		require.NoError(t, err)
+
+		_, err = auth.ExecuteLogin(ctx, usecase.LoginInput{Email: account.Email, Password: "PASSWORD"})
+		assert.ErrorIs(t, err, entity.ErrInvalidCredentials)
+	})
+
+	t.Run("logout", func(t *testing.T) {
+		_, auth, account := setup(t)
+		login, err := auth.ExecuteLogin(ctx, usecase.LoginInput{Email: account.Email, Password: "PASSWORD"})
+		require.NoError(t, err)
+		c, err := access(t, login)
+		require.NoError(t, err)
+
+		require.NoError(t, auth.ExecuteLogout(ctx, usecase.LogoutInput{
+			TokenID:   c.TokenID,
+			SessionID: c.SessionID,
+			ExpiresAt: time.Unix(c.ExpiresAt, 0),
+		}))
+
+		_, err = access(t, login)
+		assert.ErrorIs(t, err, token.ErrRevoked)
+
+		_, err = auth.ExecuteRefresh(ctx, login.RefreshToken)
+		assert.ErrorIs(t, err, entity.ErrInvalidRefreshToken)
+
+		assert.ErrorIs(t, auth.ExecuteLogout(ctx, usecase.LogoutInput{}), entity.ErrUnprocessableEntity)
+	})
+}
@@ -0,0 +1,17 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+)
+
+// notFoundAs reports a repository miss as the given catalogued error; other errors pass through.
+func notFoundAs(code *entity.ErrorCode, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return code.Wrap(err)
+	}
+
+	return err
+}
@@ -15,20 +15,15 @@ func (u *accountUseCase) ExecuteDeposit(ctx context.Context, accountID uuid.UUID
 
 	account, err := u.repository.FindAccount(ctx, accountID)
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, notFoundAs(entity.ErrAccountNotFound, err)
 	}
 
+	before := auditBalance{Balance: Money(account.Wallet.Balance())}
 	transaction, err := account.Deposit(entity.Money(value))
 	if err != nil {
 		return uuid.Nil, err
 	}
 
-	if len(account.Wallet) >= properties.Props.SnapshotWalletSize {
-		go func() {
-			u.queue <- account.ID
-		}()
-	}
-
 	tx, err := u.repository.NewTransaction(ctx)
 	if err != nil {
 		return uuid.Nil, err
@@ -36,10 +31,12 @@ func (u *accountUseCase) ExecuteDeposit(ctx context.Context, accountID uuid.UUID
 
 	ctx = gateway.InjectTransaction(ctx, tx)
 	if err := u.repository.SaveAtomicTransactions(ctx, *transaction); err != nil {
+		tx.Rollback()
 		return uuid.Nil, err
 	}
 
-	if err := u.notification.Notify(ctx, *account, *transaction); err != nil {
+	after := auditDeposit{TransactionID: transaction.ID, Amount: Money(transaction.Amount), Balance: Money(account.Wallet.Balance())}
+	if err := audit(ctx, u.audit, entity.AuditDeposit, account.ID, before, after); err != nil {
 		tx.Rollback()
 		return uuid.Nil, err
 	}
@@ -49,5 +46,6 @@ func (u *accountUseCase) ExecuteDeposit(ctx context.Context, accountID uuid.UUID
 		return uuid.Nil, err
 	}
 
+	u.publish(ctx, account.PullEvents()...)
 	return transaction.ID, nil
 }
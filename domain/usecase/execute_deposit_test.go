@@ -0,0 +1,60 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteDeposit(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("credits the account and notifies it", func(t *testing.T) {
+		f := newFixture(t)
+		account := f.account(t, entity.Personal, 0)
+
+		id, err := f.usecase.ExecuteDeposit(ctx, account.ID, 15055)
+		require.NoError(t, err)
+		assert.Equal(t, 150*entity.Real+55*entity.Cent, f.balance(t, account.ID))
+
+		calls := f.notifier.Calls()
+		require.Len(t, calls, 1)
+		assert.Equal(t, account.ID, calls[0].Account.ID)
+		assert.Equal(t, id, calls[0].Transaction.ID)
+	})
+
+	t.Run("canceled account", func(t *testing.T) {
+		f := newFixture(t)
+		account := f.account(t, entity.Personal, 0)
+		account.Status = entity.AccountStatusCanceled
+		require.NoError(t, f.store.UpdateAccount(ctx, account))
+
+		_, err := f.usecase.ExecuteDeposit(ctx, account.ID, 100)
+		assert.ErrorIs(t, err, entity.ErrUnprocessableEntity)
+		assert.Equal(t, entity.Money(0), f.balance(t, account.ID))
+		assert.Empty(t, f.notifier.Calls())
+	})
+
+	t.Run("notification failure keeps the deposit", func(t *testing.T) {
+		f := newFixture(t)
+		account := f.account(t, entity.Personal, 0)
+		f.notifier.Fail(errors.New("notification service down"))
+
+		_, err := f.usecase.ExecuteDeposit(ctx, account.ID, 100)
+		require.NoError(t, err)
+		assert.Equal(t, entity.Money(100), f.balance(t, account.ID))
+		assert.Len(t, f.notifier.Calls(), 1)
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		f := newFixture(t)
+		_, err := f.usecase.ExecuteDeposit(ctx, uuid.New(), 100)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
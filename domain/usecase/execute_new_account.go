@@ -6,6 +6,9 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/internal/logger"
+	"go.uber.org/zap"
 )
 
 func (u *accountUseCase) ExecuteNewAccount(ctx context.Context, input NewAccountInput) (uuid.UUID, error) {
@@ -18,9 +21,31 @@ func (u *accountUseCase) ExecuteNewAccount(ctx context.Context, input NewAccount
 		input.PhoneNumber,
 	)
 
-	if err := u.repository.CreateAccount(ctx, account); err != nil {
+	tx, err := u.repository.NewTransaction(ctx)
+	if err != nil {
 		return uuid.Nil, err
 	}
+	defer tx.Rollback()
+
+	txCtx := gateway.InjectTransaction(ctx, tx)
+	if err := u.repository.CreateAccount(txCtx, account); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := audit(txCtx, u.audit, entity.AuditAccountCreated, account.ID, nil, newAuditAccount(account)); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, err
+	}
+
+	u.publish(ctx, account.PullEvents()...)
+
+	// the account exists even when a code could not be sent; the holder can ask for it again
+	if err := u.sendVerifications(ctx, account, entity.VerificationChannels...); err != nil {
+		logger.Logger.Warn("Error in send verification codes", zap.String("account_id", account.ID.String()), zap.Error(err))
+	}
 
 	return account.ID, nil
 }
@@ -2,39 +2,76 @@ package usecase
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
 	"github.com/guilhermealvess/guicpay/domain/gateway"
 	"github.com/guilhermealvess/guicpay/internal/logger"
 	"github.com/guilhermealvess/guicpay/internal/properties"
 	"go.uber.org/zap"
 )
 
+var errSnapshotNotNeeded = errors.New("wallet below snapshot size")
+
 func (u *accountUseCase) ExecuteSnapshotTransaction(ctx context.Context, accountID uuid.UUID) {
+	snapshot, err := u.snapshot(ctx, accountID, false, false)
+	if errors.Is(err, errSnapshotNotNeeded) {
+		return
+	}
+
+	if err != nil {
+		logger.Logger.Error("Error in snapshot", zap.Error(err), zap.String("account_id", accountID.String()))
+		return
+	}
+
+	logger.Logger.Info("Done snapshot", zap.String("snapshotID", snapshot.ID.String()), zap.String("account_id", accountID.String()))
+}
+
+func (u *accountUseCase) ExecuteForceSnapshot(ctx context.Context, accountID uuid.UUID, dryRun bool) (*SnapshotOutput, error) {
+	snapshot, err := u.snapshot(ctx, accountID, true, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SnapshotOutput{
+		ID:        snapshot.ID,
+		AccountID: snapshot.AccountID,
+		Balance:   Money(snapshot.Amount),
+		Timestamp: snapshot.Timestamp,
+		DryRun:    dryRun,
+	}, nil
+}
+
+// snapshot consolidates the account wallet. Unless forced, wallets below the snapshot size are left
+// untouched; a dry run does everything but commit.
+func (u *accountUseCase) snapshot(ctx context.Context, accountID uuid.UUID, force, dryRun bool) (*entity.Transaction, error) {
 	ctx, cancel := context.WithTimeout(ctx, properties.Props.TransactionTimeout)
 	defer cancel()
 
 	tx, err := u.repository.NewTransaction(ctx)
 	if err != nil {
-		logger.Logger.Error("Error in new tx", zap.Error(err))
-		return
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	ctx = gateway.InjectTransaction(ctx, tx)
 	account, err := u.repository.FindAccount(ctx, accountID)
 	if err != nil {
-		logger.Logger.Error("Error in find account", zap.Error(err))
-		return
+		return nil, notFoundAs(entity.ErrAccountNotFound, err)
 	}
 
-	if len(account.Wallet) < properties.Props.SnapshotWalletSize {
-		return
+	if len(account.Wallet) == 0 {
+		return nil, entity.ErrNothingToSnapshot.New("wallet has no transactions to snapshot")
 	}
 
-	snapshot := account.Wallet.Snapshot(account.ID)
+	if !force && len(account.Wallet) < properties.Props.SnapshotWalletSize {
+		return nil, errSnapshotNotNeeded
+	}
+
+	snapshot := account.Snapshot()
 	if err := u.repository.SaveAtomicTransactions(ctx, *snapshot); err != nil {
-		logger.Logger.Error("Error in save snapshot", zap.Error(err))
-		return
+		return nil, err
 	}
 
 	transactionIDs := make(uuid.UUIDs, 0)
@@ -43,11 +80,25 @@ func (u *accountUseCase) ExecuteSnapshotTransaction(ctx context.Context, account
 	}
 
 	if err := u.repository.SetSnapshotTransactions(ctx, snapshot.ID, transactionIDs); err != nil {
-		logger.Logger.Error("Error in update snapshot", zap.Error(err))
-		tx.Rollback()
-		return
+		return nil, err
+	}
+
+	// automatic snapshots are bookkeeping; forcing one is an operator action
+	if force {
+		after := auditSnapshot{SnapshotID: snapshot.ID, Transactions: len(transactionIDs), Balance: Money(snapshot.Amount)}
+		if err := audit(ctx, u.audit, entity.AuditSnapshotForced, account.ID, nil, after); err != nil {
+			return nil, err
+		}
+	}
+
+	if dryRun {
+		return snapshot, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
 	}
 
-	tx.Commit()
-	logger.Logger.Info("Done snapshot", zap.String("snapshotID", snapshot.ID.String()), zap.String("account_id", account.ID.String()))
+	u.publish(ctx, account.PullEvents()...)
+	return snapshot, nil
 }
@@ -0,0 +1,68 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/internal/properties"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("small wallet is left untouched", func(t *testing.T) {
+		f := newFixture(t)
+		account := f.account(t, entity.Personal, 10*entity.Real)
+
+		f.usecase.ExecuteSnapshotTransaction(ctx, account.ID)
+		found, err := f.store.FindAccount(ctx, account.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Wallet, 1)
+		assert.Equal(t, entity.Deposit, found.Wallet[0].TransactionType)
+	})
+
+	t.Run("full wallet is consolidated", func(t *testing.T) {
+		f := newFixture(t)
+		account := f.account(t, entity.Personal, 0)
+		for range properties.Props.SnapshotWalletSize {
+			_, err := f.usecase.ExecuteDeposit(ctx, account.ID, uint64(entity.Real))
+			require.NoError(t, err)
+		}
+
+		select {
+		case id := <-f.snapshots:
+			assert.Equal(t, account.ID, id)
+		case <-time.After(time.Second):
+			t.Fatal("snapshot was not requested")
+		}
+
+		f.usecase.ExecuteSnapshotTransaction(ctx, account.ID)
+		found, err := f.store.FindAccount(ctx, account.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Wallet, 1)
+		assert.Equal(t, entity.Snapshot, found.Wallet[0].TransactionType)
+		assert.Equal(t, entity.Money(properties.Props.SnapshotWalletSize)*entity.Real, found.Wallet.Balance())
+
+		balance, err := f.usecase.FindBalanceAt(ctx, account.ID, time.Time{})
+		require.NoError(t, err)
+		assert.NotNil(t, balance.SnapshotID)
+	})
+
+	t.Run("forced dry run does not persist", func(t *testing.T) {
+		f := newFixture(t)
+		account := f.account(t, entity.Personal, 10*entity.Real)
+
+		output, err := f.usecase.ExecuteForceSnapshot(ctx, account.ID, true)
+		require.NoError(t, err)
+		assert.True(t, output.DryRun)
+
+		found, err := f.store.FindAccount(ctx, account.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Wallet, 1)
+		assert.Equal(t, entity.Deposit, found.Wallet[0].TransactionType)
+	})
+}
@@ -10,49 +10,74 @@ import (
 	"go.opentelemetry.io/otel"
 )
 
-func (u *accountUseCase) ExecuteTransfer(ctx context.Context, payer, payee uuid.UUID, value uint64) (uuid.UUID, error) {
+// ExecuteTransfer moves value from payer to payee. otp is the one-time password the step-up check
+// may ask for, empty when the client sent none.
+func (u *accountUseCase) ExecuteTransfer(ctx context.Context, payer, payee uuid.UUID, value uint64, otp string) (uuid.UUID, error) {
 	ctx, cancel := context.WithTimeout(ctx, properties.Props.TransactionTimeout)
 	defer cancel()
 
 	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "AccountUseCase.ExecuteTransfer")
 	defer span.End()
 
+	if err := u.stepUp(ctx, payer, payee, value, otp); err != nil {
+		return uuid.Nil, err
+	}
+
 	tx, err := u.repository.NewTransaction(ctx)
 	if err != nil {
 		return uuid.Nil, err
 	}
 
-	defer tx.Commit()
+	defer tx.Rollback()
 	ctx = gateway.InjectTransaction(ctx, tx)
 	accounts, err := u.repository.FindAccountByIDs(ctx, payer, payee)
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, notFoundAs(entity.ErrAccountNotFound, err)
 	}
 
 	payerAccount, payeeAccount := accounts[payer], accounts[payee]
+
 	if err := u.authorizer.Authorize(ctx, *payerAccount); err != nil {
 		return uuid.Nil, err
 	}
 
+	before := newAuditBalances(payerAccount, payeeAccount)
 	output, err := payerAccount.Transfer(payeeAccount, entity.Money(value))
 	if err != nil {
 		return uuid.Nil, err
 	}
 
-	if len(payerAccount.Wallet) >= properties.Props.SnapshotWalletSize {
-		go func() {
-			u.queue <- payerAccount.ID
-		}()
+	if err := u.repository.SaveAtomicTransactions(ctx, *output.Payer, *output.Payee); err != nil {
+		return uuid.Nil, err
 	}
 
-	if err := u.repository.SaveAtomicTransactions(ctx, *output.Payer, *output.Payee); err != nil {
+	if err := audit(ctx, u.audit, entity.AuditTransfer, payerAccount.ID, before, auditTransfer{
+		CorrelatedID:  output.CorrelatedID,
+		PayerID:       payerAccount.ID,
+		PayeeID:       payeeAccount.ID,
+		Amount:        Money(output.Payee.Amount),
+		auditBalances: newAuditBalances(payerAccount, payeeAccount),
+	}); err != nil {
+		return uuid.Nil, err
+	}
+
+	event := entity.NewWebhookEvent(entity.WebhookEventTransferReceived, transferReceivedData{
+		TransactionID: output.Payee.ID,
+		CorrelatedID:  output.CorrelatedID,
+		PayerID:       payerAccount.ID,
+		PayeeID:       payeeAccount.ID,
+		Amount:        int64(output.Payee.Amount),
+		Currency:      "BRL",
+		Timestamp:     output.Payee.Timestamp,
+	})
+	if err := enqueueWebhookEvent(ctx, u.webhooks, payeeAccount.ID, event); err != nil {
 		return uuid.Nil, err
 	}
 
-	if err := u.notification.Notify(ctx, *payeeAccount, *output.Payee); err != nil {
-		tx.Rollback()
+	if err := tx.Commit(); err != nil {
 		return uuid.Nil, err
 	}
 
-	return output.CorrelatedID, tx.Commit()
+	u.publish(ctx, payerAccount.PullEvents()...)
+	return output.CorrelatedID, nil
 }
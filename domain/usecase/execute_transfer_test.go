@@ -0,0 +1,78 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteTransfer(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("moves money and notifies the payee", func(t *testing.T) {
+		f := newFixture(t)
+		payer := f.account(t, entity.Personal, 100*entity.Real)
+		payee := f.account(t, entity.Seller, 0)
+
+		webhook, err := entity.NewWebhook(payee.ID, "https://example.com/hook", "", []entity.WebhookEventType{entity.WebhookEventTransferReceived}, false)
+		require.NoError(t, err)
+		require.NoError(t, f.store.CreateWebhook(ctx, webhook))
+
+		correlatedID, err := f.usecase.ExecuteTransfer(ctx, payer.ID, payee.ID, uint64(40*entity.Real), "")
+		require.NoError(t, err)
+		assert.Equal(t, 60*entity.Real, f.balance(t, payer.ID))
+		assert.Equal(t, 40*entity.Real, f.balance(t, payee.ID))
+
+		authorized := f.authorizer.Calls()
+		require.Len(t, authorized, 1)
+		assert.Equal(t, payer.ID, authorized[0].ID)
+
+		notified := f.notifier.Calls()
+		require.Len(t, notified, 1)
+		assert.Equal(t, payee.ID, notified[0].Account.ID)
+		assert.Equal(t, correlatedID, notified[0].Transaction.CorrelatedID.UUID)
+
+		deliveries, err := f.store.FindWebhookDeliveries(ctx, webhook.ID)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, entity.WebhookEventTransferReceived, deliveries[0].EventType)
+	})
+
+	t.Run("authorization denied rolls back", func(t *testing.T) {
+		f := newFixture(t)
+		payer := f.account(t, entity.Personal, 100*entity.Real)
+		payee := f.account(t, entity.Seller, 0)
+		f.authorizer.Fail(errors.New("denied"))
+
+		_, err := f.usecase.ExecuteTransfer(ctx, payer.ID, payee.ID, uint64(40*entity.Real), "")
+		assert.Error(t, err)
+		assert.Equal(t, 100*entity.Real, f.balance(t, payer.ID))
+		assert.Equal(t, entity.Money(0), f.balance(t, payee.ID))
+		assert.Empty(t, f.notifier.Calls())
+	})
+
+	t.Run("insufficient balance", func(t *testing.T) {
+		f := newFixture(t)
+		payer := f.account(t, entity.Personal, 10*entity.Real)
+		payee := f.account(t, entity.Personal, 0)
+
+		_, err := f.usecase.ExecuteTransfer(ctx, payer.ID, payee.ID, uint64(40*entity.Real), "")
+		assert.ErrorIs(t, err, entity.ErrUnprocessableEntity)
+		assert.Equal(t, 10*entity.Real, f.balance(t, payer.ID))
+		assert.Equal(t, entity.Money(0), f.balance(t, payee.ID))
+	})
+
+	t.Run("seller cannot pay", func(t *testing.T) {
+		f := newFixture(t)
+		payer := f.account(t, entity.Seller, 100*entity.Real)
+		payee := f.account(t, entity.Personal, 0)
+
+		_, err := f.usecase.ExecuteTransfer(ctx, payer.ID, payee.ID, uint64(entity.Real), "")
+		assert.ErrorIs(t, err, entity.ErrUnprocessableEntity)
+		assert.Equal(t, 100*entity.Real, f.balance(t, payer.ID))
+	})
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+)
+
+func (u *accountUseCase) ExportStatement(ctx context.Context, accountID uuid.UUID, input StatementExportInput, encoder gateway.StatementEncoder) error {
+	account, err := u.repository.FindAccount(ctx, accountID)
+	if err != nil {
+		return notFoundAs(entity.ErrAccountNotFound, err)
+	}
+
+	filter := entity.StatementFilter{From: input.From, To: input.To}
+	if filter.To.IsZero() {
+		filter.To = time.Now().UTC()
+	}
+
+	if filter.From.IsZero() {
+		filter.From = account.CreatedAt
+	}
+
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+
+	// the latest line up to the end of the range carries the ledger balance, even when
+	// the range itself has no transactions
+	last, err := u.repository.FindStatement(ctx, accountID, entity.StatementFilter{To: filter.To, Limit: 1})
+	if err != nil {
+		return err
+	}
+
+	var ledgerBalance entity.Money
+	if len(last) > 0 {
+		ledgerBalance = last[0].Balance
+	}
+
+	if err := encoder.Begin(*account, filter.From, filter.To); err != nil {
+		return err
+	}
+
+	if err := u.repository.StreamStatement(ctx, accountID, filter, encoder.Line); err != nil {
+		return err
+	}
+
+	return encoder.End(ledgerBalance, filter.To)
+}
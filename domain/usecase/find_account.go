@@ -4,42 +4,82 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
 )
 
 func (u *accountUseCase) FindByID(ctx context.Context, accountID uuid.UUID) (*AccountOutput, error) {
 	account, err := u.repository.FindAccount(ctx, accountID)
 	if err != nil {
-		return nil, err
+		return nil, notFoundAs(entity.ErrAccountNotFound, err)
 	}
 
-	return &AccountOutput{
-		ID:           account.ID,
-		AccountType:  string(account.AccountType),
-		CustomerName: account.CustomerName,
-		Email:        account.Email,
-		Status:       string(account.Status),
-		Balance:      account.Wallet.Balance().String(),
-	}, nil
+	return buildAccountOutput(account), nil
 }
 
-func (u *accountUseCase) FindAll(ctx context.Context) ([]*AccountOutput, error) {
-	accounts, err := u.repository.FindAll(ctx)
+func (u *accountUseCase) ListAccounts(ctx context.Context, input AccountListInput) (*AccountListOutput, error) {
+	filter := entity.AccountFilter{
+		NamePrefix:  input.Name,
+		EmailPrefix: input.Email,
+		Limit:       input.Limit,
+	}
+
+	for _, t := range input.Types {
+		filter.Types = append(filter.Types, entity.AccountType(t))
+	}
+
+	for _, s := range input.Statuses {
+		filter.Statuses = append(filter.Statuses, entity.AccountStatus(s))
+	}
+
+	if input.Cursor != "" {
+		cursor, err := entity.ParseAccountCursor(input.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.Cursor = cursor
+	}
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	total, err := u.repository.CountAccounts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// one extra account tells whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+	accounts, err := u.repository.ListAccounts(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]*AccountOutput, 0)
+	output := AccountListOutput{Accounts: make([]*AccountOutput, 0, limit), Total: total}
+	if len(accounts) > limit {
+		accounts = accounts[:limit]
+		last := accounts[limit-1]
+		output.NextCursor = entity.AccountCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
 	for _, account := range accounts {
-		data := AccountOutput{
-			ID:           account.ID,
-			AccountType:  string(account.AccountType),
-			CustomerName: account.CustomerName,
-			Email:        account.Email,
-			Status:       string(account.Status),
-			Balance:      account.Wallet.Balance().String(),
-		}
-		result = append(result, &data)
+		data := buildAccountOutput(&account.Account)
+		data.Balance = Money(account.Balance)
+		output.Accounts = append(output.Accounts, data)
 	}
 
-	return result, nil
+	return &output, nil
+}
+
+func buildAccountOutput(account *entity.Account) *AccountOutput {
+	return &AccountOutput{
+		ID:           account.ID,
+		AccountType:  string(account.AccountType),
+		Role:         string(account.Role),
+		CustomerName: account.CustomerName,
+		Email:        account.Email,
+		Status:       string(account.Status),
+		Balance:      Money(account.Wallet.Balance()),
+	}
 }
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+)
+
+// FindBalanceAt computes the balance as of the instant from the nearest preceding snapshot
+// plus every transaction up to the instant that it does not consolidate.
+func (u *accountUseCase) FindBalanceAt(ctx context.Context, accountID uuid.UUID, at time.Time) (*BalanceOutput, error) {
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+
+	if _, err := u.repository.FindAccount(ctx, accountID); err != nil {
+		return nil, notFoundAs(entity.ErrAccountNotFound, err)
+	}
+
+	snapshot, err := u.repository.FindSnapshotBefore(ctx, accountID, at)
+	if err != nil {
+		return nil, err
+	}
+
+	later, err := u.repository.FindTransactionsAfterSnapshot(ctx, accountID, snapshot, at)
+	if err != nil {
+		return nil, err
+	}
+
+	output := BalanceOutput{
+		AccountID: accountID,
+		Balance:   Money(entity.BalanceFromSnapshot(snapshot, later)),
+		At:        at,
+	}
+
+	if snapshot != nil {
+		output.SnapshotID = &snapshot.ID
+	}
+
+	return &output, nil
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+)
+
+func (u *accountUseCase) FindStatement(ctx context.Context, accountID uuid.UUID, input StatementInput) (*StatementOutput, error) {
+	filter := entity.StatementFilter{
+		From:      input.From,
+		To:        input.To,
+		MinAmount: entity.Money(input.MinAmount),
+		MaxAmount: entity.Money(input.MaxAmount),
+		Limit:     input.Limit,
+	}
+
+	for _, t := range input.Types {
+		filter.Types = append(filter.Types, entity.TransactionType(t))
+	}
+
+	if input.Cursor != "" {
+		cursor, err := entity.ParseStatementCursor(input.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.Cursor = cursor
+	}
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	// one extra line tells whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+	lines, err := u.repository.FindStatement(ctx, accountID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	output := StatementOutput{Transactions: make([]*StatementLineOutput, 0, limit)}
+	if len(lines) > limit {
+		lines = lines[:limit]
+		last := lines[limit-1]
+		output.NextCursor = entity.StatementCursor{Timestamp: last.Timestamp, ID: last.ID}.Encode()
+	}
+
+	for _, line := range lines {
+		output.Transactions = append(output.Transactions, buildStatementLineOutput(line))
+	}
+
+	return &output, nil
+}
+
+func buildStatementLineOutput(line *entity.StatementLine) *StatementLineOutput {
+	data := StatementLineOutput{
+		ID:              line.ID,
+		TransactionType: string(line.TransactionType),
+		Amount:          Money(line.Amount),
+		Balance:         Money(line.Balance),
+		Timestamp:       line.Timestamp,
+	}
+
+	if line.CorrelatedID.Valid {
+		data.CorrelatedID = &line.CorrelatedID.UUID
+	}
+
+	if line.Counterparty != nil {
+		data.Counterparty = &CounterpartyOutput{
+			ID:           line.Counterparty.AccountID,
+			AccountType:  string(line.Counterparty.AccountType),
+			CustomerName: line.Counterparty.CustomerName,
+		}
+	}
+
+	return &data
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/internal/logger"
+	"github.com/guilhermealvess/guicpay/internal/properties"
+	"go.uber.org/zap"
+)
+
+// decoyPassword is checked when no account has the email, so the miss costs the same hash as a
+// wrong password.
+var decoyPassword = entity.Password("SHA256:" + strings.Repeat("0", 64) + ":" + strings.Repeat("0", 64))
+
+// errLoginFailed is the one answer to a wrong email or password, so logins do not reveal which
+// emails have an account.
+func errLoginFailed() error {
+	return entity.ErrInvalidCredentials.New("email or password does not match")
+}
+
+// loginKey is a key failures of a login attempt are counted under, with the policy throttling it.
+type loginKey struct {
+	key    string
+	policy entity.LoginPolicy
+}
+
+func loginKeys(input LoginInput) []loginKey {
+	login := properties.Props.Login
+	keys := []loginKey{{key: entity.LoginEmailKey(input.Email), policy: accountLoginPolicy()}}
+
+	if input.ClientIP != "" {
+		// addresses are shared behind NATs and proxies, so they get a larger allowance
+		keys = append(keys, loginKey{
+			key: entity.LoginIPKey(input.ClientIP),
+			policy: entity.LoginPolicy{
+				FreeAttempts: login.IPFreeAttempts,
+				LockAfter:    login.IPLockAfter,
+				BaseDelay:    login.BaseDelay,
+				Lockout:      login.Lockout,
+			},
+		})
+	}
+
+	return keys
+}
+
+// accountLoginPolicy throttles the keys of a single account: its email, and the one-time passwords
+// sent once it is logged in.
+func accountLoginPolicy() entity.LoginPolicy {
+	login := properties.Props.Login
+	return entity.LoginPolicy{
+		FreeAttempts: login.FreeAttempts,
+		LockAfter:    login.LockAfter,
+		BaseDelay:    login.BaseDelay,
+		Lockout:      login.Lockout,
+	}
+}
+
+// checkThrottle fails while any key of the attempt waits for its delay or is locked; throttled
+// attempts are rejected before the password is checked and are not counted.
+func checkThrottle(ctx context.Context, repository gateway.LoginAttemptRepository, keys []loginKey, now time.Time) error {
+	ids := make([]string, 0, len(keys))
+	policies := make(map[string]entity.LoginPolicy, len(keys))
+	for _, k := range keys {
+		ids = append(ids, k.key)
+		policies[k.key] = k.policy
+	}
+
+	attempts, err := repository.FindLoginAttempts(ctx, ids...)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range attempts {
+		policy := policies[a.Key]
+		until := a.BlockedUntil(policy)
+		if !now.Before(until) {
+			continue
+		}
+
+		if a.Locked(policy) {
+			return entity.ErrLoginLocked.Errorf("too many failed attempts, login locked until %s", until.Format(time.RFC3339))
+		}
+
+		return entity.ErrLoginThrottled.Errorf("too many failed attempts, retry in %s", max(until.Sub(now).Round(time.Second), time.Second))
+	}
+
+	return nil
+}
+
+// recordFailure counts a failed attempt under each of its keys. Counting is best effort: a
+// failure to store it is logged and the login still fails with the original error.
+func recordFailure(ctx context.Context, repository gateway.LoginAttemptRepository, keys []loginKey, now time.Time) {
+	for _, k := range keys {
+		attempts, err := repository.RecordLoginFailure(ctx, k.key, now, k.policy.Lockout)
+		if err != nil {
+			logger.Logger.Error("Error in record login failure", zap.Error(err))
+			continue
+		}
+
+		if attempts.Failures == k.policy.LockAfter {
+			logger.Logger.Warn("Login locked after failed attempts",
+				zap.String("key", k.key),
+				zap.Int("failures", attempts.Failures))
+		}
+	}
+}
+
+// auditLoginFailure records a rejected login. The entry has no subject, as the email may belong to
+// no account; like counting failures, it is best effort and a failure to store it is only logged.
+func (u *authUseCase) auditLoginFailure(ctx context.Context, input LoginInput, err error) {
+	data := auditLoginAttempts{Email: input.Email, Error: entity.ErrorOf(err).Code}
+	if err := audit(ctx, u.audit, entity.AuditLoginFailed, uuid.Nil, nil, data); err != nil {
+		logger.Logger.Error("Error in audit login failure", zap.Error(err))
+	}
+}
+
+// ExecuteUnlockLogin clears the failed logins of the account email and its wrong one-time
+// passwords, lifting a lockout before it expires. Address counters are left alone: they may belong
+// to whoever locked the account.
+func (u *accountUseCase) ExecuteUnlockLogin(ctx context.Context, accountID uuid.UUID, dryRun bool) (*UnlockOutput, error) {
+	account, err := u.repository.FindAccount(ctx, accountID)
+	if err != nil {
+		return nil, notFoundAs(entity.ErrAccountNotFound, err)
+	}
+
+	keys := []string{entity.LoginEmailKey(account.Email), entity.LoginOTPKey(account.ID)}
+	attempts, err := u.attempts.FindLoginAttempts(ctx, keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	output := UnlockOutput{AccountID: account.ID, DryRun: dryRun}
+	for _, a := range attempts {
+		output.Failures += a.Failures
+	}
+
+	if dryRun {
+		return &output, nil
+	}
+
+	tx, err := u.repository.NewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	txCtx := gateway.InjectTransaction(ctx, tx)
+	if err := u.attempts.DeleteLoginAttempts(txCtx, keys...); err != nil {
+		return nil, err
+	}
+
+	before := auditLoginAttempts{Failures: output.Failures}
+	if err := audit(txCtx, u.audit, entity.AuditLoginUnlocked, account.ID, before, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &output, nil
+}
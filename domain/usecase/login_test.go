@@ -0,0 +1,117 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/guilhermealvess/guicpay/internal/properties"
+	"github.com/guilhermealvess/guicpay/internal/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginThrottling(t *testing.T) {
+	ctx := context.Background()
+	token.InitJWT("secret", time.Minute)
+
+	login := properties.Props.Login
+	properties.Props.Login.FreeAttempts = 2
+	properties.Props.Login.LockAfter = 4
+	properties.Props.Login.IPFreeAttempts = 3
+	properties.Props.Login.IPLockAfter = 10
+	properties.Props.Login.BaseDelay = time.Hour
+	properties.Props.Login.Lockout = 2 * time.Hour
+	t.Cleanup(func() { properties.Props.Login = login })
+
+	setup := func(t *testing.T) (*fixture, usecase.AuthUseCase, entity.Account) {
+		f := newFixture(t)
+		return f, usecase.NewAuthUseCase(f.deps), f.account(t, entity.Personal, 0)
+	}
+
+	attempt := func(auth usecase.AuthUseCase, email, password, ip string) error {
+		_, err := auth.ExecuteLogin(ctx, usecase.LoginInput{Email: email, Password: password, ClientIP: ip})
+		return err
+	}
+
+	t.Run("uniform errors", func(t *testing.T) {
+		_, auth, account := setup(t)
+
+		wrong := attempt(auth, account.Email, "WRONG", "")
+		missing := attempt(auth, "nobody@example.com", "WRONG", "")
+		assert.ErrorIs(t, wrong, entity.ErrInvalidCredentials)
+		assert.Equal(t, entity.ErrorOf(wrong), entity.ErrorOf(missing))
+	})
+
+	t.Run("delay then lockout", func(t *testing.T) {
+		f, auth, account := setup(t)
+
+		for range 3 {
+			assert.ErrorIs(t, attempt(auth, account.Email, "WRONG", "203.0.113.1"), entity.ErrInvalidCredentials)
+		}
+
+		// the right password waits for the delay too, from any address
+		assert.ErrorIs(t, attempt(auth, account.Email, "PASSWORD", "198.51.100.1"), entity.ErrLoginThrottled)
+
+		_, err := f.store.RecordLoginFailure(ctx, entity.LoginEmailKey(account.Email), time.Now(), time.Hour)
+		require.NoError(t, err)
+		err = attempt(auth, account.Email, "PASSWORD", "198.51.100.1")
+		assert.ErrorIs(t, err, entity.ErrLoginLocked)
+		assert.ErrorIs(t, err, entity.ErrTooManyRequests)
+
+		unlocked, err := f.usecase.ExecuteUnlockLogin(ctx, account.ID, false)
+		require.NoError(t, err)
+		assert.Equal(t, 4, unlocked.Failures)
+		assert.NoError(t, attempt(auth, account.Email, "PASSWORD", "198.51.100.1"))
+	})
+
+	t.Run("unknown emails are throttled alike", func(t *testing.T) {
+		_, auth, _ := setup(t)
+
+		for range 3 {
+			assert.ErrorIs(t, attempt(auth, "nobody@example.com", "WRONG", ""), entity.ErrInvalidCredentials)
+		}
+		assert.ErrorIs(t, attempt(auth, "nobody@example.com", "WRONG", ""), entity.ErrLoginThrottled)
+	})
+
+	t.Run("per address", func(t *testing.T) {
+		f, auth, account := setup(t)
+		other := f.account(t, entity.Personal, 0)
+
+		for _, email := range []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com"} {
+			assert.ErrorIs(t, attempt(auth, email, "WRONG", "203.0.113.2"), entity.ErrInvalidCredentials)
+		}
+
+		assert.ErrorIs(t, attempt(auth, account.Email, "PASSWORD", "203.0.113.2"), entity.ErrLoginThrottled)
+		assert.NoError(t, attempt(auth, other.Email, "PASSWORD", "203.0.113.3"))
+	})
+
+	t.Run("success clears the email count", func(t *testing.T) {
+		f, auth, account := setup(t)
+
+		for range 2 {
+			assert.ErrorIs(t, attempt(auth, account.Email, "WRONG", ""), entity.ErrInvalidCredentials)
+		}
+		require.NoError(t, attempt(auth, account.Email, "PASSWORD", ""))
+
+		found, err := f.store.FindLoginAttempts(ctx, entity.LoginEmailKey(account.Email))
+		require.NoError(t, err)
+		assert.Empty(t, found)
+	})
+
+	t.Run("password reset lifts the lockout", func(t *testing.T) {
+		f, auth, account := setup(t)
+
+		for range 4 {
+			_, err := f.store.RecordLoginFailure(ctx, entity.LoginEmailKey(account.Email), time.Now(), time.Hour)
+			require.NoError(t, err)
+		}
+		assert.ErrorIs(t, attempt(auth, account.Email, "PASSWORD", ""), entity.ErrLoginLocked)
+
+		_, err := f.usecase.ExecuteResetPassword(ctx, account.ID, "NEW-PASSWORD", false)
+		require.NoError(t, err)
+		assert.NoError(t, attempt(auth, account.Email, "NEW-PASSWORD", ""))
+	})
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/internal/properties"
+)
+
+// ExecuteEnrollTOTP starts two-factor enrollment. The credential stays pending, and logins keep
+// working without codes, until ExecuteConfirmTOTP; enrolling again replaces a pending credential.
+func (u *authUseCase) ExecuteEnrollTOTP(ctx context.Context, accountID uuid.UUID) (*TOTPEnrollmentOutput, error) {
+	account, err := u.accounts.FindAccount(ctx, accountID)
+	if err != nil {
+		return nil, notFoundAs(entity.ErrAccountNotFound, err)
+	}
+
+	current, err := findEnabledTOTP(ctx, u.mfa, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if current != nil {
+		return nil, entity.ErrTOTPAlreadyEnabled.New("disable two-factor authentication before enrolling again")
+	}
+
+	totp, codes := entity.NewTOTP(account.ID)
+	if err := u.mfa.SaveTOTP(ctx, totp); err != nil {
+		return nil, err
+	}
+
+	return &TOTPEnrollmentOutput{
+		Secret:        totp.Secret,
+		URI:           totp.URI(properties.Props.MFA.Issuer, account.Email),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+func (u *authUseCase) ExecuteConfirmTOTP(ctx context.Context, accountID uuid.UUID, code string) error {
+	totp, err := u.mfa.FindTOTP(ctx, accountID)
+	if err != nil {
+		return notFoundAs(entity.ErrTOTPNotEnrolled, err)
+	}
+
+	if err := checkOTP(ctx, u.attempts, accountID, func() error { return totp.Confirm(code, time.Now()) }); err != nil {
+		return err
+	}
+
+	return u.mfa.SaveTOTP(ctx, *totp)
+}
+
+// ExecuteDisableTOTP removes the credential; a code or a recovery code proves the account holder
+// still has the second factor.
+func (u *authUseCase) ExecuteDisableTOTP(ctx context.Context, accountID uuid.UUID, code string) error {
+	totp, err := findEnabledTOTP(ctx, u.mfa, accountID)
+	if err != nil {
+		return err
+	}
+
+	if totp == nil {
+		return entity.ErrTOTPNotEnrolled.New("two-factor authentication is not enabled")
+	}
+
+	if err := checkOTP(ctx, u.attempts, accountID, func() error { return totp.Verify(code, time.Now()) }); err != nil {
+		return err
+	}
+
+	return u.mfa.DeleteTOTP(ctx, accountID)
+}
+
+// stepUp asks accounts with two-factor authentication enabled for a code before transfers above
+// the step-up amount or to a payee they never paid before. It runs outside the transfer
+// transaction, so a wrong code stays counted when the transfer fails.
+func (u *accountUseCase) stepUp(ctx context.Context, payer, payee uuid.UUID, value uint64, code string) error {
+	totp, err := findEnabledTOTP(ctx, u.mfa, payer)
+	if err != nil || totp == nil {
+		return err
+	}
+
+	if value <= properties.Props.MFA.StepUpAmount {
+		known, err := u.repository.HasTransferredTo(ctx, payer, payee)
+		if err != nil || known {
+			return err
+		}
+	}
+
+	if code == "" {
+		return entity.ErrStepUpRequired.New("transfer requires a one-time password")
+	}
+
+	err = checkOTP(ctx, u.attempts, payer, func() error { return consumeOTP(ctx, u.mfa, totp, code) })
+	if errors.Is(err, entity.ErrInvalidOTP) {
+		return entity.ErrStepUpRequired.Wrap(err)
+	}
+
+	return err
+}
+
+// checkOTP runs verify, the check of a one-time password sent by a logged in account. Wrong codes
+// are counted against the account like wrong logins, so after repeated failures it is throttled
+// and then locked out with LOGIN_THROTTLED or LOGIN_LOCKED; a right code clears the count.
+func checkOTP(ctx context.Context, attempts gateway.LoginAttemptRepository, accountID uuid.UUID, verify func() error) error {
+	now := time.Now().UTC()
+	keys := []loginKey{{key: entity.LoginOTPKey(accountID), policy: accountLoginPolicy()}}
+	if err := checkThrottle(ctx, attempts, keys, now); err != nil {
+		return err
+	}
+
+	err := verify()
+	if errors.Is(err, entity.ErrInvalidOTP) {
+		recordFailure(ctx, attempts, keys, now)
+		return err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return attempts.DeleteLoginAttempts(ctx, keys[0].key)
+}
+
+// findEnabledTOTP returns the confirmed credential of the account, or nil when it has none.
+func findEnabledTOTP(ctx context.Context, mfa gateway.MFARepository, accountID uuid.UUID) (*entity.TOTP, error) {
+	totp, err := mfa.FindTOTP(ctx, accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !totp.Enabled() {
+		return nil, nil
+	}
+
+	return totp, nil
+}
+
+// consumeOTP verifies the code and saves the credential, so the same code or recovery code is not
+// accepted twice. The save only applies to the credential as it was read, so of two requests
+// racing with codes of the same credential only the first one passes.
+func consumeOTP(ctx context.Context, mfa gateway.MFARepository, totp *entity.TOTP, code string) error {
+	lastStep, updatedAt := totp.LastStep, totp.UpdatedAt
+	if err := totp.Verify(code, time.Now()); err != nil {
+		return err
+	}
+
+	used, err := mfa.UseTOTP(ctx, *totp, lastStep, updatedAt)
+	if err != nil {
+		return err
+	}
+
+	if !used {
+		return entity.ErrInvalidOTP.New("one-time password already used")
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/guilhermealvess/guicpay/internal/properties"
+	"github.com/guilhermealvess/guicpay/internal/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactorAuthentication(t *testing.T) {
+	ctx := context.Background()
+	token.InitJWT("secret", time.Minute)
+
+	stepUpAmount := properties.Props.MFA.StepUpAmount
+	properties.Props.MFA.StepUpAmount = uint64(500 * entity.Real)
+	t.Cleanup(func() { properties.Props.MFA.StepUpAmount = stepUpAmount })
+
+	// code returns the code of the time step offset from now; each step is accepted once
+	code := func(t *testing.T, secret string, offset int64) string {
+		raw, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+		require.NoError(t, err)
+		return entity.TOTPCode(raw, time.Now().Unix()/30+offset)
+	}
+
+	enroll := func(t *testing.T, f *fixture, auth usecase.AuthUseCase, accountID uuid.UUID) *usecase.TOTPEnrollmentOutput {
+		enrollment, err := auth.ExecuteEnrollTOTP(ctx, accountID)
+		require.NoError(t, err)
+		require.NoError(t, auth.ExecuteConfirmTOTP(ctx, accountID, code(t, enrollment.Secret, -1)))
+		return enrollment
+	}
+
+	setup := func(t *testing.T) (*fixture, usecase.AuthUseCase) {
+		f := newFixture(t)
+		return f, usecase.NewAuthUseCase(f.deps)
+	}
+
+	t.Run("enrollment", func(t *testing.T) {
+		f, auth := setup(t)
+		account := f.account(t, entity.Personal, 0)
+
+		pending, err := auth.ExecuteEnrollTOTP(ctx, account.ID)
+		require.NoError(t, err)
+		assert.Contains(t, pending.URI, "otpauth://totp/")
+		assert.Len(t, pending.RecoveryCodes, 10)
+
+		_, err = auth.ExecuteLogin(ctx, usecase.LoginInput{Email: account.Email, Password: "PASSWORD"})
+		assert.NoError(t, err, "a pending credential does not ask for codes")
+
+		assert.ErrorIs(t, auth.ExecuteConfirmTOTP(ctx, account.ID, "000000x"), entity.ErrInvalidOTP)
+		require.NoError(t, auth.ExecuteConfirmTOTP(ctx, account.ID, code(t, pending.Secret, 0)))
+
+		_, err = auth.ExecuteEnrollTOTP(ctx, account.ID)
+		assert.ErrorIs(t, err, entity.ErrTOTPAlreadyEnabled)
+	})
+
+	t.Run("login asks for a code", func(t *testing.T) {
+		f, auth := setup(t)
+		account := f.account(t, entity.Personal, 0)
+		enrollment := enroll(t, f, auth, account.ID)
+
+		_, err := auth.ExecuteLogin(ctx, usecase.LoginInput{Email: account.Email, Password: "PASSWORD"})
+		assert.ErrorIs(t, err, entity.ErrOTPRequired)
+
+		_, err = auth.ExecuteLogin(ctx, usecase.LoginInput{Email: account.Email, Password: "PASSWORD", OTP: "123"})
+		assert.ErrorIs(t, err, entity.ErrInvalidOTP)
+
+		valid := code(t, enrollment.Secret, 0)
+		_, err = auth.ExecuteLogin(ctx, usecase.LoginInput{Email: account.Email, Password: "PASSWORD", OTP: valid})
+		require.NoError(t, err)
+
+		_, err = auth.ExecuteLogin(ctx, usecase.LoginInput{Email: account.Email, Password: "PASSWORD", OTP: valid})
+		assert.ErrorIs(t, err, entity.ErrInvalidOTP, "codes cannot be replayed")
+
+		_, err = auth.ExecuteLogin(ctx, usecase.LoginInput{Email: account.Email, Password: "PASSWORD", OTP: enrollment.RecoveryCodes[0]})
+		assert.NoError(t, err)
+	})
+
+	t.Run("step-up for new payees and large transfers", func(t *testing.T) {
+		f, auth := setup(t)
+		payer := f.account(t, entity.Personal, 1000*entity.Real)
+		payee := f.account(t, entity.Personal, 0)
+
+		_, err := f.usecase.ExecuteTransfer(ctx, payer.ID, payee.ID, uint64(10*entity.Real), "")
+		require.NoError(t, err, "accounts without two-factor authentication are not asked")
+
+		enrollment := enroll(t, f, auth, payer.ID)
+		_, err = f.usecase.ExecuteTransfer(ctx, payer.ID, payee.ID, uint64(10*entity.Real), "")
+		assert.NoError(t, err, "known payee below the step-up amount")
+
+		_, err = f.usecase.ExecuteTransfer(ctx, payer.ID, payee.ID, uint64(600*entity.Real), "")
+		assert.ErrorIs(t, err, entity.ErrStepUpRequired)
+
+		other := f.account(t, entity.Personal, 0)
+		_, err = f.usecase.ExecuteTransfer(ctx, payer.ID, other.ID, uint64(10*entity.Real), "")
+		assert.ErrorIs(t, err, entity.ErrStepUpRequired)
+
+		_, err = f.usecase.ExecuteTransfer(ctx, payer.ID, other.ID, uint64(10*entity.Real), "999999x")
+		assert.ErrorIs(t, err, entity.ErrStepUpRequired)
+		assert.ErrorIs(t, err, entity.ErrInvalidOTP)
+
+		_, err = f.usecase.ExecuteTransfer(ctx, payer.ID, other.ID, uint64(10*entity.Real), code(t, enrollment.Secret, 0))
+		require.NoError(t, err)
+		assert.Equal(t, 970*entity.Real, f.balance(t, payer.ID))
+	})
+
+	t.Run("wrong codes are throttled per account", func(t *testing.T) {
+		login := properties.Props.Login
+		properties.Props.Login.FreeAttempts = 2
+		properties.Props.Login.LockAfter = 4
+		properties.Props.Login.BaseDelay = time.Hour
+		properties.Props.Login.Lockout = 2 * time.Hour
+		t.Cleanup(func() { properties.Props.Login = login })
+
+		f, auth := setup(t)
+		payer := f.account(t, entity.Personal, 1000*entity.Real)
+		payee := f.account(t, entity.Personal, 0)
+		enrollment := enroll(t, f, auth, payer.ID)
+
+		for range 2 {
+			_, err := f.usecase.ExecuteTransfer(ctx, payer.ID, payee.ID, uint64(10*entity.Real), "999999x")
+			assert.ErrorIs(t, err, entity.ErrInvalidOTP)
+		}
+		assert.ErrorIs(t, auth.ExecuteDisableTOTP(ctx, payer.ID, "999999x"), entity.ErrInvalidOTP)
+
+		// the right code waits for the delay too, on every check of the account
+		_, err := f.usecase.ExecuteTransfer(ctx, payer.ID, payee.ID, uint64(10*entity.Real), code(t, enrollment.Secret, 0))
+		assert.ErrorIs(t, err, entity.ErrLoginThrottled)
+		assert.ErrorIs(t, auth.ExecuteDisableTOTP(ctx, payer.ID, enrollment.RecoveryCodes[0]), entity.ErrLoginThrottled)
+
+		_, err = f.store.RecordLoginFailure(ctx, entity.LoginOTPKey(payer.ID), time.Now(), time.Hour)
+		require.NoError(t, err)
+		assert.ErrorIs(t, auth.ExecuteDisableTOTP(ctx, payer.ID, enrollment.RecoveryCodes[0]), entity.ErrLoginLocked)
+
+		unlocked, err := f.usecase.ExecuteUnlockLogin(ctx, payer.ID, false)
+		require.NoError(t, err)
+		assert.Equal(t, 4, unlocked.Failures)
+
+		_, err = f.usecase.ExecuteTransfer(ctx, payer.ID, payee.ID, uint64(10*entity.Real), code(t, enrollment.Secret, 0))
+		require.NoError(t, err)
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		f, auth := setup(t)
+		account := f.account(t, entity.Personal, 0)
+		enrollment := enroll(t, f, auth, account.ID)
+
+		assert.ErrorIs(t, auth.ExecuteDisableTOTP(ctx, account.ID, ""), entity.ErrInvalidOTP)
+		require.NoError(t, auth.ExecuteDisableTOTP(ctx, account.ID, enrollment.RecoveryCodes[1]))
+
+		_, err := auth.ExecuteLogin(ctx, usecase.LoginInput{Email: account.Email, Password: "PASSWORD"})
+		assert.NoError(t, err)
+		assert.ErrorIs(t, auth.ExecuteDisableTOTP(ctx, account.ID, "123456"), entity.ErrTOTPNotEnrolled)
+	})
+}
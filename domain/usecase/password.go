@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/internal/logger"
+	"github.com/guilhermealvess/guicpay/internal/properties"
+	"go.uber.org/zap"
+)
+
+// ExecuteRequestPasswordReset sends the account with the email a reset token, replacing any token
+// sent before. An email without an account is not an error, and neither is a failure to send the
+// token, so the endpoint does not reveal which emails have one.
+func (u *authUseCase) ExecuteRequestPasswordReset(ctx context.Context, email string) error {
+	resume, err := u.accounts.FindResumeAccount(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	account, err := u.accounts.FindAccount(ctx, resume.ID)
+	if err != nil {
+		return err
+	}
+
+	token, value := entity.NewPasswordResetToken(account.ID, properties.Props.Login.ResetExpire)
+	if err := u.tokens.SavePasswordResetToken(ctx, token); err != nil {
+		return err
+	}
+
+	if err := u.notifier.NotifyPasswordReset(ctx, *account, value, token.ExpiresAt); err != nil {
+		logger.Logger.Error("Error in notify password reset", zap.String("account_id", account.ID.String()), zap.Error(err))
+	}
+
+	return nil
+}
+
+// ExecutePasswordReset sets the password of the account the reset token was sent to. The token
+// works once, and the new password ends every session of the account.
+func (u *authUseCase) ExecutePasswordReset(ctx context.Context, input PasswordResetInput) error {
+	hash := entity.HashToken(input.Token)
+	token, err := u.tokens.FindPasswordResetToken(ctx, hash)
+	if err != nil {
+		return notFoundAs(entity.ErrInvalidResetToken, err)
+	}
+
+	now := time.Now().UTC()
+	if !token.Active(now) {
+		return entity.ErrInvalidResetToken.New("password reset token expired or already used")
+	}
+
+	account, err := u.accounts.FindAccount(ctx, token.AccountID)
+	if err != nil {
+		return notFoundAs(entity.ErrInvalidResetToken, err)
+	}
+
+	tx, err := u.tokens.NewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	txCtx := gateway.InjectTransaction(ctx, tx)
+	used, err := u.tokens.UsePasswordResetToken(txCtx, hash, now)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if !used {
+		// another request used it first
+		tx.Rollback()
+		return entity.ErrInvalidResetToken.New("password reset token expired or already used")
+	}
+
+	if err := u.changePassword(txCtx, account.ID, input.Password, now); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+// ExecuteChangePassword sets a new password once the current one is confirmed. Wrong current
+// passwords count against the email of the account like wrong logins, so they are throttled and
+// then locked out too. Every session of the account ends, the one making the change included.
+func (u *authUseCase) ExecuteChangePassword(ctx context.Context, input ChangePasswordInput) error {
+	account, err := u.accounts.FindAccount(ctx, input.AccountID)
+	if err != nil {
+		return notFoundAs(entity.ErrAccountNotFound, err)
+	}
+
+	now := time.Now().UTC()
+	keys := []loginKey{{key: entity.LoginEmailKey(account.Email), policy: accountLoginPolicy()}}
+	if err := checkThrottle(ctx, u.attempts, keys, now); err != nil {
+		return err
+	}
+
+	if err := account.PasswordEncoded.Compare(input.CurrentPassword); err != nil {
+		recordFailure(ctx, u.attempts, keys, now)
+		return entity.ErrInvalidCredentials.New("current password does not match")
+	}
+
+	tx, err := u.tokens.NewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	txCtx := gateway.InjectTransaction(ctx, tx)
+	if err := u.changePassword(txCtx, account.ID, input.NewPassword, now); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+func (u *authUseCase) changePassword(ctx context.Context, accountID uuid.UUID, password string, now time.Time) error {
+	if password == "" {
+		return entity.ErrInvalidArgument.New("new password is empty")
+	}
+
+	// the account is read again under its lock, so a status or role changed meanwhile is kept
+	if err := u.accounts.LockAccount(ctx, accountID); err != nil {
+		return notFoundAs(entity.ErrAccountNotFound, err)
+	}
+
+	account, err := u.accounts.FindAccount(ctx, accountID)
+	if err != nil {
+		return notFoundAs(entity.ErrAccountNotFound, err)
+	}
+
+	account.ChangePassword(password)
+	if err := u.accounts.UpdateAccount(ctx, *account); err != nil {
+		return err
+	}
+
+	if err := revokeAccountSessions(ctx, u.tokens, account.ID, now); err != nil {
+		return err
+	}
+
+	return u.attempts.DeleteLoginAttempts(ctx, entity.LoginEmailKey(account.Email))
+}
+
+// revokeAccountSessions ends every session of the account: its refresh tokens stop exchanging and
+// the access tokens already issued are revoked until they expire.
+func revokeAccountSessions(ctx context.Context, tokens gateway.TokenRepository, accountID uuid.UUID, now time.Time) error {
+	sessions, err := tokens.RevokeAccountSessions(ctx, accountID, now)
+	if err != nil || len(sessions) == 0 {
+		return err
+	}
+
+	revoked := make([]entity.RevokedToken, 0, len(sessions))
+	for _, id := range sessions {
+		revoked = append(revoked, entity.NewRevokedToken(id, now.Add(properties.Props.JWT.Expire)))
+	}
+
+	return tokens.RevokeTokens(ctx, revoked...)
+}
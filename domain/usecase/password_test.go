@@ -0,0 +1,143 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/guilhermealvess/guicpay/internal/properties"
+	"github.com/guilhermealvess/guicpay/internal/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordChange(t *testing.T) {
+	ctx := context.Background()
+	token.InitJWT("secret", time.Minute)
+
+	setup := func(t *testing.T) (*fixture, usecase.AuthUseCase, entity.Account) {
+		f := newFixture(t)
+		token.UseRevocationStore(f.store)
+		t.Cleanup(func() { token.UseRevocationStore(nil) })
+		return f, usecase.NewAuthUseCase(f.deps), f.account(t, entity.Personal, 0)
+	}
+
+	login := func(t *testing.T, auth usecase.AuthUseCase, email, password string) *usecase.SessionOutput {
+		t.Helper()
+		session, err := auth.ExecuteLogin(ctx, usecase.LoginInput{Email: email, Password: password})
+		require.NoError(t, err)
+		return session
+	}
+
+	// ended asserts the session can neither call the API nor refresh
+	ended := func(t *testing.T, auth usecase.AuthUseCase, session *usecase.SessionOutput) {
+		t.Helper()
+		var c claims
+		assert.ErrorIs(t, token.Middle(ctx, "Bearer "+session.AccessToken, &c), token.ErrRevoked)
+		_, err := auth.ExecuteRefresh(ctx, session.RefreshToken)
+		assert.ErrorIs(t, err, entity.ErrInvalidRefreshToken)
+	}
+
+	t.Run("forgot and reset", func(t *testing.T) {
+		f, auth, account := setup(t)
+		session := login(t, auth, account.Email, "PASSWORD")
+
+		require.NoError(t, auth.ExecuteRequestPasswordReset(ctx, account.Email))
+		resets := f.notifier.Resets()
+		require.Len(t, resets, 1)
+		assert.Equal(t, account.ID, resets[0].Account.ID)
+
+		reset := usecase.PasswordResetInput{Token: resets[0].Token, Password: "NEW-PASSWORD"}
+		require.NoError(t, auth.ExecutePasswordReset(ctx, reset))
+		ended(t, auth, session)
+
+		_, err := auth.ExecuteLogin(ctx, usecase.LoginInput{Email: account.Email, Password: "PASSWORD"})
+		assert.ErrorIs(t, err, entity.ErrInvalidCredentials)
+		login(t, auth, account.Email, "NEW-PASSWORD")
+
+		// the token works once
+		reset.Password = "OTHER-PASSWORD"
+		assert.ErrorIs(t, auth.ExecutePasswordReset(ctx, reset), entity.ErrInvalidResetToken)
+		assert.ErrorIs(t, auth.ExecutePasswordReset(ctx, usecase.PasswordResetInput{Token: "pr_unknown", Password: "X"}), entity.ErrInvalidResetToken)
+	})
+
+	t.Run("unknown email is not revealed", func(t *testing.T) {
+		f, auth, account := setup(t)
+
+		assert.NoError(t, auth.ExecuteRequestPasswordReset(ctx, "nobody@example.com"))
+		assert.Empty(t, f.notifier.Resets())
+
+		// nor is a known one by a failure to send the token
+		f.notifier.Fail(errors.New("smtp down"))
+		assert.NoError(t, auth.ExecuteRequestPasswordReset(ctx, account.Email))
+		assert.Len(t, f.notifier.Resets(), 1)
+	})
+
+	t.Run("a new request replaces the token", func(t *testing.T) {
+		f, auth, account := setup(t)
+
+		require.NoError(t, auth.ExecuteRequestPasswordReset(ctx, account.Email))
+		require.NoError(t, auth.ExecuteRequestPasswordReset(ctx, account.Email))
+		resets := f.notifier.Resets()
+		require.Len(t, resets, 2)
+
+		assert.ErrorIs(t, auth.ExecutePasswordReset(ctx, usecase.PasswordResetInput{Token: resets[0].Token, Password: "NEW-PASSWORD"}), entity.ErrInvalidResetToken)
+		assert.NoError(t, auth.ExecutePasswordReset(ctx, usecase.PasswordResetInput{Token: resets[1].Token, Password: "NEW-PASSWORD"}))
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		f, auth, account := setup(t)
+
+		expired, value := entity.NewPasswordResetToken(account.ID, -time.Minute)
+		require.NoError(t, f.store.SavePasswordResetToken(ctx, expired))
+		assert.ErrorIs(t, auth.ExecutePasswordReset(ctx, usecase.PasswordResetInput{Token: value, Password: "NEW-PASSWORD"}), entity.ErrInvalidResetToken)
+		login(t, auth, account.Email, "PASSWORD")
+	})
+
+	t.Run("change checks the current password", func(t *testing.T) {
+		_, auth, account := setup(t)
+		session := login(t, auth, account.Email, "PASSWORD")
+
+		err := auth.ExecuteChangePassword(ctx, usecase.ChangePasswordInput{AccountID: account.ID, CurrentPassword: "WRONG", NewPassword: "NEW-PASSWORD"})
+		assert.ErrorIs(t, err, entity.ErrInvalidCredentials)
+		_, err = auth.ExecuteRefresh(ctx, session.RefreshToken)
+		require.NoError(t, err)
+
+		other := login(t, auth, account.Email, "PASSWORD")
+		require.NoError(t, auth.ExecuteChangePassword(ctx, usecase.ChangePasswordInput{AccountID: account.ID, CurrentPassword: "PASSWORD", NewPassword: "NEW-PASSWORD"}))
+		ended(t, auth, other)
+		login(t, auth, account.Email, "NEW-PASSWORD")
+	})
+
+	t.Run("wrong current passwords are throttled", func(t *testing.T) {
+		login := properties.Props.Login
+		properties.Props.Login.FreeAttempts = 2
+		properties.Props.Login.LockAfter = 4
+		properties.Props.Login.BaseDelay = time.Hour
+		properties.Props.Login.Lockout = 2 * time.Hour
+		t.Cleanup(func() { properties.Props.Login = login })
+
+		_, auth, account := setup(t)
+		change := usecase.ChangePasswordInput{AccountID: account.ID, CurrentPassword: "WRONG", NewPassword: "NEW-PASSWORD"}
+		for range 3 {
+			assert.ErrorIs(t, auth.ExecuteChangePassword(ctx, change), entity.ErrInvalidCredentials)
+		}
+
+		change.CurrentPassword = "PASSWORD"
+		assert.ErrorIs(t, auth.ExecuteChangePassword(ctx, change), entity.ErrLoginThrottled)
+		_, err := auth.ExecuteLogin(ctx, usecase.LoginInput{Email: account.Email, Password: "PASSWORD"})
+		assert.ErrorIs(t, err, entity.ErrLoginThrottled, "the count is shared with the login")
+	})
+
+	t.Run("admin reset ends sessions", func(t *testing.T) {
+		f, auth, account := setup(t)
+		session := login(t, auth, account.Email, "PASSWORD")
+
+		_, err := f.usecase.ExecuteResetPassword(ctx, account.ID, "NEW-PASSWORD", false)
+		require.NoError(t, err)
+		ended(t, auth, session)
+	})
+}
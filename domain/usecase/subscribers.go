@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/internal/properties"
+)
+
+// NewNotificationSubscriber notifies the account credited by a deposit or a transfer.
+func NewNotificationSubscriber(n gateway.NotificationService) gateway.EventHandler {
+	return func(ctx context.Context, event entity.Event) error {
+		switch e := event.(type) {
+		case entity.DepositCompleted:
+			return n.Notify(ctx, e.Account, e.Transaction)
+
+		case entity.TransferCompleted:
+			return n.Notify(ctx, e.Payee, e.PayeeTransfer)
+
+		case entity.TransferReversed:
+			return n.Notify(ctx, e.Payee, e.PayeeReversal)
+		}
+
+		return nil
+	}
+}
+
+// NewSnapshotSubscriber enqueues accounts whose wallet reached the snapshot size.
+func NewSnapshotSubscriber(queue chan uuid.UUID) gateway.EventHandler {
+	enqueue := func(accountID uuid.UUID, walletSize int) {
+		if walletSize < properties.Props.SnapshotWalletSize {
+			return
+		}
+
+		go func() {
+			queue <- accountID
+		}()
+	}
+
+	return func(ctx context.Context, event entity.Event) error {
+		switch e := event.(type) {
+		case entity.DepositCompleted:
+			enqueue(e.Account.ID, e.WalletSize)
+
+		case entity.TransferCompleted:
+			enqueue(e.Payer.ID, e.PayerWalletSize)
+
+		case entity.TransferReversed:
+			enqueue(e.Payer.ID, e.PayerWalletSize)
+		}
+
+		return nil
+	}
+}
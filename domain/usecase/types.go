@@ -1,10 +1,61 @@
 package usecase
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
 )
 
+// Money is an amount in an output, rendered in JSON as {"amount": 1029, "currency": "BRL"} with
+// the amount in cents.
+type Money entity.Money
+
+func (m Money) String() string {
+	return entity.Money(m).String()
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   int64  `json:"amount"`
+		Currency string `json:"currency"`
+	}{Amount: int64(m), Currency: entity.Currency})
+}
+
+// AmountInput is an amount in a request: either a decimal string in reais such as "10.29" or an
+// integer number of cents such as 1029. Floating point numbers are rejected, as they cannot carry
+// cents exactly.
+type AmountInput entity.Money
+
+func (a *AmountInput) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		m, err := entity.ParseMoney(s)
+		if err != nil {
+			return err
+		}
+
+		*a = AmountInput(m)
+		return nil
+	}
+
+	var cents int64
+	if err := json.Unmarshal(data, &cents); err != nil {
+		return errors.Join(entity.ErrUnprocessableEntity, fmt.Errorf("invalid amount %s: use a decimal string or integer cents", data))
+	}
+
+	if cents < 0 {
+		return errors.Join(entity.ErrUnprocessableEntity, fmt.Errorf("amount %d must not be negative", cents))
+	}
+
+	*a = AmountInput(cents)
+	return nil
+}
+
 type NewAccountInput struct {
 	Name           string `json:"customer_name" validate:"required"`
 	Email          string `json:"email" validate:"required"`
@@ -17,12 +68,254 @@ type NewAccountInput struct {
 type AccountOutput struct {
 	ID           uuid.UUID `json:"account_id"`
 	AccountType  string    `json:"account_type"`
+	Role         string    `json:"role"`
 	CustomerName string    `json:"customer_name"`
 	Email        string    `json:"email"`
-	Balance      string    `json:"balance"`
+	Balance      Money     `json:"balance"`
 	Status       string    `json:"status"`
 }
 
+type AccountListInput struct {
+	Types    []string
+	Statuses []string
+	Name     string
+	Email    string
+	Cursor   string
+	Limit    int
+}
+
+type AccountListOutput struct {
+	Accounts   []*AccountOutput `json:"accounts"`
+	Total      int              `json:"total"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
 func ValidateDTO(v any) error {
 	return validator.New().Struct(v)
 }
+
+type NewWebhookInput struct {
+	URL    string   `json:"url" validate:"required,url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events" validate:"required,min=1"`
+}
+
+type WebhookOutput struct {
+	ID        uuid.UUID `json:"webhook_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type NewAPIKeyInput struct {
+	Name   string   `json:"name" validate:"required,max=100"`
+	Scopes []string `json:"scopes" validate:"required,min=1"`
+}
+
+type APIKeyOutput struct {
+	ID         uuid.UUID  `json:"api_key_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Key        string     `json:"key,omitempty"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+type WebhookDeliveryOutput struct {
+	ID             uuid.UUID       `json:"delivery_id"`
+	WebhookID      uuid.UUID       `json:"webhook_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	LastStatusCode int             `json:"last_status_code"`
+	LastError      string          `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type AuditListInput struct {
+	Actions   []string
+	ActorID   uuid.UUID
+	SubjectID uuid.UUID
+	RequestID string
+	From      time.Time
+	To        time.Time
+	Cursor    string
+	Limit     int
+}
+
+type AuditEntryOutput struct {
+	ID        uuid.UUID       `json:"entry_id"`
+	Action    string          `json:"action"`
+	ActorID   *uuid.UUID      `json:"actor_id,omitempty"`
+	ActorRole string          `json:"actor_role,omitempty"`
+	APIKeyID  *uuid.UUID      `json:"api_key_id,omitempty"`
+	SubjectID *uuid.UUID      `json:"subject_id,omitempty"`
+	IP        string          `json:"ip"`
+	UserAgent string          `json:"user_agent"`
+	RequestID string          `json:"request_id"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type AuditListOutput struct {
+	Entries    []*AuditEntryOutput `json:"entries"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+type StatementInput struct {
+	From      time.Time
+	To        time.Time
+	Types     []string
+	MinAmount uint64
+	MaxAmount uint64
+	Cursor    string
+	Limit     int
+}
+
+type CounterpartyOutput struct {
+	ID           uuid.UUID `json:"account_id"`
+	AccountType  string    `json:"account_type"`
+	CustomerName string    `json:"customer_name"`
+}
+
+type StatementLineOutput struct {
+	ID              uuid.UUID           `json:"transaction_id"`
+	TransactionType string              `json:"transaction_type"`
+	Amount          Money               `json:"amount"`
+	Balance         Money               `json:"balance"`
+	Timestamp       time.Time           `json:"timestamp"`
+	CorrelatedID    *uuid.UUID          `json:"correlated_id,omitempty"`
+	Counterparty    *CounterpartyOutput `json:"counterparty,omitempty"`
+}
+
+type StatementOutput struct {
+	Transactions []*StatementLineOutput `json:"transactions"`
+	NextCursor   string                 `json:"next_cursor,omitempty"`
+}
+
+type WatchInput struct {
+	Cursor string
+	Replay int
+}
+
+type TransactionOutput struct {
+	ID              uuid.UUID  `json:"transaction_id"`
+	TransactionType string     `json:"transaction_type"`
+	Amount          Money      `json:"amount"`
+	Timestamp       time.Time  `json:"timestamp"`
+	CorrelatedID    *uuid.UUID `json:"correlated_id,omitempty"`
+	Cursor          string     `json:"cursor"`
+}
+
+type StatementExportInput struct {
+	From time.Time
+	To   time.Time
+}
+
+type BalanceOutput struct {
+	AccountID  uuid.UUID  `json:"account_id"`
+	Balance    Money      `json:"balance"`
+	At         time.Time  `json:"at"`
+	SnapshotID *uuid.UUID `json:"snapshot_id,omitempty"`
+}
+
+type SnapshotOutput struct {
+	ID        uuid.UUID `json:"snapshot_id"`
+	AccountID uuid.UUID `json:"account_id"`
+	Balance   Money     `json:"balance"`
+	Timestamp time.Time `json:"timestamp"`
+	DryRun    bool      `json:"dry_run"`
+}
+
+type StatusOutput struct {
+	AccountID uuid.UUID `json:"account_id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	DryRun    bool      `json:"dry_run"`
+}
+
+type RoleOutput struct {
+	AccountID uuid.UUID `json:"account_id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	DryRun    bool      `json:"dry_run"`
+}
+
+type ReversalOutput struct {
+	CorrelatedID         uuid.UUID `json:"correlated_id"`
+	TransferCorrelatedID uuid.UUID `json:"transfer_correlated_id"`
+	PayerID              uuid.UUID `json:"payer_id"`
+	PayeeID              uuid.UUID `json:"payee_id"`
+	Amount               Money     `json:"amount"`
+	DryRun               bool      `json:"dry_run"`
+}
+
+type PasswordResetOutput struct {
+	AccountID uuid.UUID `json:"account_id"`
+	Password  string    `json:"password,omitempty"`
+	DryRun    bool      `json:"dry_run"`
+}
+
+type UnlockOutput struct {
+	AccountID uuid.UUID `json:"account_id"`
+	Failures  int       `json:"failures"`
+	DryRun    bool      `json:"dry_run"`
+}
+
+type LoginInput struct {
+	Email    string
+	Password string
+	// OTP is a code of the authenticator app or a recovery code, required once TOTP is enabled.
+	OTP string
+	// ClientIP is the address the attempt came from; failures are also counted per address.
+	ClientIP string
+}
+
+type VerifyInput struct {
+	Channel string `json:"channel" validate:"required"`
+	Code    string `json:"code" validate:"required"`
+}
+
+type VerificationOutput struct {
+	AccountID     uuid.UUID `json:"account_id"`
+	Status        string    `json:"status"`
+	EmailVerified bool      `json:"email_verified"`
+	PhoneVerified bool      `json:"phone_verified"`
+}
+
+type PasswordResetInput struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type ChangePasswordInput struct {
+	AccountID       uuid.UUID `json:"-"`
+	CurrentPassword string    `json:"current_password" validate:"required"`
+	NewPassword     string    `json:"new_password" validate:"required"`
+}
+
+type TOTPEnrollmentOutput struct {
+	Secret        string   `json:"secret"`
+	URI           string   `json:"otpauth_uri"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type SessionOutput struct {
+	AccessToken           string    `json:"token"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+// LogoutInput holds the claims of the access token ending the session.
+type LogoutInput struct {
+	TokenID   uuid.UUID
+	SessionID uuid.UUID
+	ExpiresAt time.Time
+}
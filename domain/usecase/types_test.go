@@ -0,0 +1,32 @@
+package usecase_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoneyJSON(t *testing.T) {
+	t.Run("output", func(t *testing.T) {
+		raw, err := json.Marshal(usecase.AccountOutput{Balance: usecase.Money(1029)})
+		require.NoError(t, err)
+		assert.Contains(t, string(raw), `"balance":{"amount":1029,"currency":"BRL"}`)
+	})
+
+	t.Run("input", func(t *testing.T) {
+		for raw, expected := range map[string]entity.Money{`"10.29"`: 1029, `"0.29"`: 29, `1029`: 1029} {
+			var amount usecase.AmountInput
+			require.NoError(t, json.Unmarshal([]byte(raw), &amount), raw)
+			assert.Equal(t, expected, entity.Money(amount), raw)
+		}
+
+		for _, raw := range []string{`10.29`, `-5`, `"-5"`, `"1.001"`, `true`} {
+			var amount usecase.AmountInput
+			assert.Error(t, json.Unmarshal([]byte(raw), &amount), raw)
+		}
+	})
+}
@@ -2,34 +2,96 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/guilhermealvess/guicpay/domain/entity"
 	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/internal/logger"
+	"go.uber.org/zap"
 )
 
 type AccountUseCase interface {
 	ExecuteNewAccount(ctx context.Context, input NewAccountInput) (uuid.UUID, error)
 	ExecuteDeposit(ctx context.Context, accountID uuid.UUID, value uint64) (uuid.UUID, error)
-	ExecuteTransfer(ctx context.Context, payer, payee uuid.UUID, value uint64) (uuid.UUID, error)
+	ExecuteTransfer(ctx context.Context, payer, payee uuid.UUID, value uint64, otp string) (uuid.UUID, error)
 	FindByID(ctx context.Context, accountID uuid.UUID) (*AccountOutput, error)
-	FindAll(ctx context.Context) ([]*AccountOutput, error)
+	ListAccounts(ctx context.Context, input AccountListInput) (*AccountListOutput, error)
+	FindStatement(ctx context.Context, accountID uuid.UUID, input StatementInput) (*StatementOutput, error)
+	FindBalanceAt(ctx context.Context, accountID uuid.UUID, at time.Time) (*BalanceOutput, error)
+	ExportStatement(ctx context.Context, accountID uuid.UUID, input StatementExportInput, encoder gateway.StatementEncoder) error
+	WatchTransactions(ctx context.Context, accountID uuid.UUID, input WatchInput, fn func(*TransactionOutput) error) error
 	ExecuteSnapshotTransaction(ctx context.Context, accountID uuid.UUID)
-	ExecuteLogin(ctx context.Context, email, password string) (*entity.ResumeAccount, error)
+
+	FindByEmail(ctx context.Context, email string) (*AccountOutput, error)
+	FindByDocument(ctx context.Context, document string) (*AccountOutput, error)
+	ExecuteChangeStatus(ctx context.Context, accountID uuid.UUID, status string, dryRun bool) (*StatusOutput, error)
+	ExecuteChangeRole(ctx context.Context, accountID uuid.UUID, role string, dryRun bool) (*RoleOutput, error)
+	ExecuteForceSnapshot(ctx context.Context, accountID uuid.UUID, dryRun bool) (*SnapshotOutput, error)
+	ExecuteReverseTransfer(ctx context.Context, correlatedID uuid.UUID, dryRun bool) (*ReversalOutput, error)
+	ExecuteResetPassword(ctx context.Context, accountID uuid.UUID, password string, dryRun bool) (*PasswordResetOutput, error)
+	ExecuteUnlockLogin(ctx context.Context, accountID uuid.UUID, dryRun bool) (*UnlockOutput, error)
+	ListAuditEntries(ctx context.Context, input AuditListInput) (*AuditListOutput, error)
+
+	ExecuteSendVerification(ctx context.Context, accountID uuid.UUID, channel string) error
+	ExecuteVerify(ctx context.Context, accountID uuid.UUID, input VerifyInput) (*VerificationOutput, error)
 }
 
 type accountUseCase struct {
-	repository   gateway.AccountRepository
-	authorizer   gateway.AuthorizationService
-	notification gateway.NotificationService
-	queue        chan uuid.UUID
+	repository    gateway.AccountRepository
+	authorizer    gateway.AuthorizationService
+	webhooks      gateway.WebhookRepository
+	mfa           gateway.MFARepository
+	attempts      gateway.LoginAttemptRepository
+	tokens        gateway.TokenRepository
+	verifications gateway.VerificationRepository
+	audit         gateway.AuditRepository
+	notifier      gateway.NotificationService
+	bus           gateway.EventBus
 }
 
-func NewAccountUseCase(r gateway.AccountRepository, n gateway.NotificationService, a gateway.AuthorizationService, ch chan uuid.UUID) AccountUseCase {
+// Dependencies are the gateways the use cases run on; each use case keeps the ones it needs. Naming
+// every field keeps repositories of the same store from being passed in the wrong place.
+type Dependencies struct {
+	Accounts      gateway.AccountRepository
+	Webhooks      gateway.WebhookRepository
+	MFA           gateway.MFARepository
+	LoginAttempts gateway.LoginAttemptRepository
+	Tokens        gateway.TokenRepository
+	Verifications gateway.VerificationRepository
+	APIKeys       gateway.APIKeyRepository
+	Audit         gateway.AuditRepository
+	Authorizer    gateway.AuthorizationService
+	Notifier      gateway.NotificationService
+	WebhookSender gateway.WebhookService
+	Signer        gateway.TokenSigner
+	Bus           gateway.EventBus
+}
+
+func NewAccountUseCase(d Dependencies) AccountUseCase {
 	return &accountUseCase{
-		repository:   r,
-		authorizer:   a,
-		notification: n,
-		queue:        ch,
+		repository:    d.Accounts,
+		authorizer:    d.Authorizer,
+		webhooks:      d.Webhooks,
+		mfa:           d.MFA,
+		attempts:      d.LoginAttempts,
+		tokens:        d.Tokens,
+		verifications: d.Verifications,
+		audit:         d.Audit,
+		notifier:      d.Notifier,
+		bus:           d.Bus,
+	}
+}
+
+// publish dispatches events after the transaction that produced them is committed;
+// subscriber failures are logged and do not undo the operation.
+func (u *accountUseCase) publish(ctx context.Context, events ...entity.Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	ctx = gateway.DetachTransaction(ctx)
+	if err := u.bus.Publish(ctx, events...); err != nil {
+		logger.Logger.Warn("Error in publish events", zap.Error(err))
 	}
 }
@@ -0,0 +1,76 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/guilhermealvess/guicpay/infra/eventbus"
+	"github.com/guilhermealvess/guicpay/internal/testkit"
+	"github.com/guilhermealvess/guicpay/internal/token"
+	"github.com/stretchr/testify/require"
+)
+
+type fixture struct {
+	store      *testkit.Store
+	authorizer *testkit.Authorizer
+	notifier   *testkit.Notifier
+	snapshots  chan uuid.UUID
+	deps       usecase.Dependencies
+	usecase    usecase.AccountUseCase
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	f := &fixture{
+		store:      testkit.NewStore(),
+		authorizer: testkit.NewAuthorizer(),
+		notifier:   testkit.NewNotifier(),
+		snapshots:  make(chan uuid.UUID, 10),
+	}
+
+	bus := eventbus.NewInMemoryEventBus()
+	bus.Subscribe(usecase.NewNotificationSubscriber(f.notifier), entity.EventDepositCompleted, entity.EventTransferCompleted, entity.EventTransferReversed)
+	bus.Subscribe(usecase.NewSnapshotSubscriber(f.snapshots), entity.EventDepositCompleted, entity.EventTransferCompleted, entity.EventTransferReversed)
+	f.deps = usecase.Dependencies{
+		Accounts:      f.store,
+		Webhooks:      f.store,
+		MFA:           f.store,
+		LoginAttempts: f.store,
+		Tokens:        f.store,
+		Verifications: f.store,
+		APIKeys:       f.store,
+		Audit:         f.store,
+		Authorizer:    f.authorizer,
+		Notifier:      f.notifier,
+		Signer:        token.JWT,
+		Bus:           bus,
+	}
+	f.usecase = usecase.NewAccountUseCase(f.deps)
+	return f
+}
+
+func (f *fixture) account(t *testing.T, accountType entity.AccountType, balance entity.Money) entity.Account {
+	t.Helper()
+	id := uuid.NewString()
+	account := entity.NewAccount(accountType, "Fulano De Tal", id, id+"@example.com", "PASSWORD", "+5511999999999")
+	account.Status = entity.AccountStatusActive
+	require.NoError(t, f.store.CreateAccount(context.Background(), account))
+
+	if balance > 0 {
+		deposit, err := account.Deposit(balance)
+		require.NoError(t, err)
+		require.NoError(t, f.store.SaveAtomicTransactions(context.Background(), *deposit))
+	}
+
+	return account
+}
+
+func (f *fixture) balance(t *testing.T, accountID uuid.UUID) entity.Money {
+	t.Helper()
+	account, err := f.store.FindAccount(context.Background(), accountID)
+	require.NoError(t, err)
+	return account.Wallet.Balance()
+}
@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/internal/properties"
+)
+
+// ExecuteSendVerification sends a new code to the channel, or to every channel still unverified
+// when none is given, replacing the codes sent before. A channel gets a new code only once the
+// resend cooldown passed since the last one.
+func (u *accountUseCase) ExecuteSendVerification(ctx context.Context, accountID uuid.UUID, channel string) error {
+	account, err := u.repository.FindAccount(ctx, accountID)
+	if err != nil {
+		return notFoundAs(entity.ErrAccountNotFound, err)
+	}
+
+	if account.Status != entity.AccountStatusPending {
+		return entity.ErrAlreadyVerified.New("account already verified")
+	}
+
+	verifications, err := u.verifications.FindVerifications(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	pending := unverifiedChannels(verifications)
+	if channel != "" {
+		c, err := entity.ParseVerificationChannel(channel)
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(pending, c) {
+			return entity.ErrAlreadyVerified.Errorf("%s already verified", channel)
+		}
+		pending = []entity.VerificationChannel{c}
+	}
+
+	now := time.Now()
+	cooldown := properties.Props.Verification.ResendCooldown
+	for _, v := range verifications {
+		if !slices.Contains(pending, v.Channel) {
+			continue
+		}
+
+		if wait := v.SentAt.Add(cooldown).Sub(now); wait > 0 {
+			return entity.ErrVerificationCooldown.Errorf("%s code sent recently, retry in %s",
+				strings.ToLower(string(v.Channel)), max(wait.Round(time.Second), time.Second))
+		}
+	}
+
+	return u.sendVerifications(ctx, *account, pending...)
+}
+
+// ExecuteVerify checks the code sent to the channel. Once email and phone are both verified the
+// account leaves AccountStatusPending and can send money.
+func (u *accountUseCase) ExecuteVerify(ctx context.Context, accountID uuid.UUID, input VerifyInput) (*VerificationOutput, error) {
+	channel, err := entity.ParseVerificationChannel(input.Channel)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := u.repository.NewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// the account stays locked until the end, so of two codes of different channels confirmed at
+	// once the second reads the first verified and activates the account
+	txCtx := gateway.InjectTransaction(ctx, tx)
+	account, err := u.lockAccount(txCtx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.Status != entity.AccountStatusPending {
+		return nil, entity.ErrAlreadyVerified.New("account already verified")
+	}
+
+	verifications, err := u.verifications.FindVerifications(txCtx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(verifications, func(v *entity.Verification) bool { return v.Channel == channel })
+	if i < 0 {
+		return nil, entity.ErrInvalidVerification.New("no verification code sent, request one")
+	}
+
+	v, now, maxAttempts := verifications[i], time.Now(), properties.Props.Verification.MaxAttempts
+	if err := v.Checkable(now, maxAttempts); err != nil {
+		return nil, err
+	}
+
+	// the attempt is counted by a conditional update, so concurrent requests cannot check more
+	// codes than the limit allows
+	counted, err := u.verifications.CountVerificationAttempt(txCtx, accountID, channel, maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+
+	if !counted {
+		return nil, entity.ErrVerificationLocked.New("too many wrong codes, request a new one")
+	}
+	v.Attempts++
+
+	if checkErr := v.Match(input.Code, now); checkErr != nil {
+		// wrong codes are committed too, so they count against the attempt limit
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return nil, checkErr
+	}
+
+	if err := u.verifications.SaveVerification(txCtx, *v); err != nil {
+		return nil, err
+	}
+
+	if len(unverifiedChannels(verifications)) == 0 {
+		before := auditStatus{Status: account.Status}
+		if err := account.ChangeStatus(entity.AccountStatusActive); err != nil {
+			return nil, err
+		}
+
+		if err := u.repository.UpdateAccount(txCtx, *account); err != nil {
+			return nil, err
+		}
+
+		after := auditStatus{Status: account.Status}
+		if err := audit(txCtx, u.audit, entity.AuditAccountStatusChanged, account.ID, before, after); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	u.publish(ctx, account.PullEvents()...)
+	return buildVerificationOutput(account, verifications), nil
+}
+
+func (u *accountUseCase) sendVerifications(ctx context.Context, account entity.Account, channels ...entity.VerificationChannel) error {
+	for _, channel := range channels {
+		v, code := entity.NewVerification(account.ID, channel, properties.Props.Verification.CodeExpire)
+		if err := u.verifications.SaveVerification(ctx, v); err != nil {
+			return err
+		}
+
+		if err := u.notifier.NotifyVerificationCode(ctx, account, channel, code, v.ExpiresAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func unverifiedChannels(verifications []*entity.Verification) []entity.VerificationChannel {
+	pending := slices.Clone(entity.VerificationChannels)
+	for _, v := range verifications {
+		if v.Verified() {
+			pending = slices.DeleteFunc(pending, func(c entity.VerificationChannel) bool { return c == v.Channel })
+		}
+	}
+
+	return pending
+}
+
+func buildVerificationOutput(account *entity.Account, verifications []*entity.Verification) *VerificationOutput {
+	output := VerificationOutput{AccountID: account.ID, Status: string(account.Status)}
+	for _, v := range verifications {
+		switch v.Channel {
+		case entity.VerificationEmail:
+			output.EmailVerified = v.Verified()
+		case entity.VerificationPhone:
+			output.PhoneVerified = v.Verified()
+		}
+	}
+
+	return &output
+}
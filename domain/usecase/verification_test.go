@@ -0,0 +1,146 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/guilhermealvess/guicpay/internal/properties"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountVerification(t *testing.T) {
+	ctx := context.Background()
+
+	verification := properties.Props.Verification
+	properties.Props.Verification.MaxAttempts = 3
+	properties.Props.Verification.ResendCooldown = 0
+	t.Cleanup(func() { properties.Props.Verification = verification })
+
+	onboard := func(t *testing.T, f *fixture) (uuid.UUID, map[entity.VerificationChannel]string) {
+		t.Helper()
+		id := uuid.NewString()
+		accountID, err := f.usecase.ExecuteNewAccount(ctx, usecase.NewAccountInput{
+			Name:           "Fulano De Tal",
+			Email:          id + "@example.com",
+			Password:       "PASSWORD",
+			Type:           "personal",
+			DocumentNumber: id,
+			PhoneNumber:    "+5511999999999",
+		})
+		require.NoError(t, err)
+
+		codes := make(map[entity.VerificationChannel]string)
+		for _, n := range f.notifier.Codes() {
+			if n.Account.ID == accountID {
+				codes[n.Channel] = n.Code
+			}
+		}
+		return accountID, codes
+	}
+
+	verify := func(accountID uuid.UUID, f *fixture, channel, code string) (*usecase.VerificationOutput, error) {
+		return f.usecase.ExecuteVerify(ctx, accountID, usecase.VerifyInput{Channel: channel, Code: code})
+	}
+
+	t.Run("unverified accounts receive but do not send", func(t *testing.T) {
+		f := newFixture(t)
+		accountID, codes := onboard(t, f)
+		require.Len(t, codes, 2)
+
+		account, err := f.usecase.FindByID(ctx, accountID)
+		require.NoError(t, err)
+		assert.Equal(t, string(entity.AccountStatusPending), account.Status)
+
+		_, err = f.usecase.ExecuteDeposit(ctx, accountID, uint64(50*entity.Real))
+		require.NoError(t, err)
+		sender := f.account(t, entity.Personal, 10*entity.Real)
+		_, err = f.usecase.ExecuteTransfer(ctx, sender.ID, accountID, uint64(10*entity.Real), "")
+		require.NoError(t, err)
+
+		payee := f.account(t, entity.Seller, 0)
+		_, err = f.usecase.ExecuteTransfer(ctx, accountID, payee.ID, uint64(10*entity.Real), "")
+		assert.ErrorIs(t, err, entity.ErrAccountUnverified)
+		assert.Equal(t, 60*entity.Real, f.balance(t, accountID))
+
+		output, err := verify(accountID, f, "email", codes[entity.VerificationEmail])
+		require.NoError(t, err)
+		assert.True(t, output.EmailVerified)
+		assert.Equal(t, string(entity.AccountStatusPending), output.Status)
+
+		output, err = verify(accountID, f, "phone", codes[entity.VerificationPhone])
+		require.NoError(t, err)
+		assert.True(t, output.PhoneVerified)
+		assert.Equal(t, string(entity.AccountStatusActive), output.Status)
+
+		_, err = f.usecase.ExecuteTransfer(ctx, accountID, payee.ID, uint64(10*entity.Real), "")
+		assert.NoError(t, err)
+
+		_, err = verify(accountID, f, "phone", codes[entity.VerificationPhone])
+		assert.ErrorIs(t, err, entity.ErrAlreadyVerified)
+	})
+
+	t.Run("attempt limit and resend", func(t *testing.T) {
+		f := newFixture(t)
+		accountID, codes := onboard(t, f)
+
+		for range 3 {
+			_, err := verify(accountID, f, "email", "000000x")
+			assert.ErrorIs(t, err, entity.ErrInvalidVerification)
+		}
+
+		_, err := verify(accountID, f, "email", codes[entity.VerificationEmail])
+		assert.ErrorIs(t, err, entity.ErrVerificationLocked)
+
+		require.NoError(t, f.usecase.ExecuteSendVerification(ctx, accountID, "email"))
+		sent := f.notifier.Codes()
+		last := sent[len(sent)-1]
+		assert.Equal(t, entity.VerificationEmail, last.Channel)
+
+		output, err := verify(accountID, f, "email", last.Code)
+		require.NoError(t, err)
+		assert.True(t, output.EmailVerified)
+
+		assert.ErrorIs(t, f.usecase.ExecuteSendVerification(ctx, accountID, "email"), entity.ErrAlreadyVerified)
+		assert.ErrorIs(t, f.usecase.ExecuteSendVerification(ctx, accountID, "fax"), entity.ErrInvalidArgument)
+
+		// without a channel only the unverified ones get a new code
+		before := len(f.notifier.Codes())
+		require.NoError(t, f.usecase.ExecuteSendVerification(ctx, accountID, ""))
+		sent = f.notifier.Codes()[before:]
+		require.Len(t, sent, 1)
+		assert.Equal(t, entity.VerificationPhone, sent[0].Channel)
+	})
+
+	t.Run("resend cooldown", func(t *testing.T) {
+		properties.Props.Verification.ResendCooldown = time.Hour
+		t.Cleanup(func() { properties.Props.Verification.ResendCooldown = 0 })
+
+		f := newFixture(t)
+		accountID, codes := onboard(t, f)
+
+		err := f.usecase.ExecuteSendVerification(ctx, accountID, "email")
+		assert.ErrorIs(t, err, entity.ErrVerificationCooldown)
+		assert.ErrorIs(t, err, entity.ErrTooManyRequests)
+		assert.ErrorIs(t, f.usecase.ExecuteSendVerification(ctx, accountID, ""), entity.ErrVerificationCooldown)
+
+		// the code sent before the cooldown still verifies
+		output, err := verify(accountID, f, "email", codes[entity.VerificationEmail])
+		require.NoError(t, err)
+		assert.True(t, output.EmailVerified)
+	})
+
+	t.Run("notification failure keeps the account", func(t *testing.T) {
+		f := newFixture(t)
+		f.notifier.Fail(assert.AnError)
+		accountID, _ := onboard(t, f)
+
+		_, err := f.usecase.FindByID(ctx, accountID)
+		require.NoError(t, err)
+		assert.ErrorIs(t, f.usecase.ExecuteSendVerification(ctx, accountID, ""), assert.AnError)
+	})
+}
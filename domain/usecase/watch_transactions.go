@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/internal/properties"
+)
+
+const (
+	WatchDefaultReplay = 20
+	WatchMaxReplay     = 200
+	watchBatchSize     = 100
+)
+
+// WatchTransactions hands fn the transactions of the account as they are committed, until ctx is
+// done or fn fails. Without a cursor the latest transactions are replayed first; with one, every
+// transaction after it is, so a client resuming from the last cursor it saw misses nothing. The
+// cursor is the position in the account feed, numbered in commit order, so a transaction that
+// commits after a later-timestamped one still comes after the cursor.
+//
+// Committed events only wake the watcher up: what is sent is always read from the repository
+// after the cursor, and a periodic poll covers transactions committed by other instances.
+func (u *accountUseCase) WatchTransactions(ctx context.Context, accountID uuid.UUID, input WatchInput, fn func(*TransactionOutput) error) error {
+	cursor := &entity.FeedCursor{}
+	if input.Cursor != "" {
+		var err error
+		if cursor, err = entity.ParseFeedCursor(input.Cursor); err != nil {
+			return err
+		}
+	}
+
+	replay := input.Replay
+	if replay <= 0 {
+		replay = WatchDefaultReplay
+	}
+	replay = min(replay, WatchMaxReplay)
+
+	// subscribe before the first read, so nothing committed in between is left waiting for the poll
+	wake := make(chan struct{}, 1)
+	unsubscribe := u.bus.Subscribe(func(ctx context.Context, event entity.Event) error {
+		if eventConcerns(event, accountID) {
+			select {
+			case wake <- struct{}{}:
+			default:
+			}
+		}
+		return nil
+	}, entity.EventDepositCompleted, entity.EventTransferCompleted, entity.EventTransferReversed, entity.EventSnapshotTaken)
+	defer unsubscribe()
+
+	send := func(transactions []*entity.Transaction) error {
+		for _, t := range transactions {
+			cursor = &entity.FeedCursor{Sequence: t.Sequence}
+			if err := fn(buildTransactionOutput(t, cursor.Encode())); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if input.Cursor == "" {
+		transactions, err := u.repository.FindTransactionFeed(ctx, accountID, nil, replay)
+		if err != nil {
+			return err
+		}
+
+		if err := send(transactions); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(properties.Props.WatchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			transactions, err := u.repository.FindTransactionFeed(ctx, accountID, cursor, watchBatchSize)
+			if err != nil {
+				return err
+			}
+
+			if err := send(transactions); err != nil {
+				return err
+			}
+
+			if len(transactions) < watchBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func eventConcerns(event entity.Event, accountID uuid.UUID) bool {
+	switch e := event.(type) {
+	case entity.DepositCompleted:
+		return e.Account.ID == accountID
+
+	case entity.TransferCompleted:
+		return e.Payer.ID == accountID || e.Payee.ID == accountID
+
+	case entity.TransferReversed:
+		return e.Payer.ID == accountID || e.Payee.ID == accountID
+
+	case entity.SnapshotTaken:
+		return e.AccountID == accountID
+	}
+
+	return false
+}
+
+func buildTransactionOutput(t *entity.Transaction, cursor string) *TransactionOutput {
+	data := TransactionOutput{
+		ID:              t.ID,
+		TransactionType: string(t.TransactionType),
+		Amount:          Money(t.Amount),
+		Timestamp:       t.Timestamp,
+		Cursor:          cursor,
+	}
+
+	if t.CorrelatedID.Valid {
+		data.CorrelatedID = &t.CorrelatedID.UUID
+	}
+
+	return &data
+}
@@ -0,0 +1,85 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchTransactions(t *testing.T) {
+	watch := func(t *testing.T, f *fixture, account entity.Account, input usecase.WatchInput) (<-chan *usecase.TransactionOutput, <-chan error, context.CancelFunc) {
+		t.Helper()
+		ctx, cancel := context.WithCancel(context.Background())
+		events := make(chan *usecase.TransactionOutput, 10)
+		done := make(chan error, 1)
+		go func() {
+			done <- f.usecase.WatchTransactions(ctx, account.ID, input, func(t *usecase.TransactionOutput) error {
+				events <- t
+				return nil
+			})
+		}()
+		t.Cleanup(cancel)
+		return events, done, cancel
+	}
+
+	next := func(t *testing.T, events <-chan *usecase.TransactionOutput) *usecase.TransactionOutput {
+		t.Helper()
+		select {
+		case e := <-events:
+			return e
+		case <-time.After(time.Second):
+			t.Fatal("no transaction received")
+			return nil
+		}
+	}
+
+	t.Run("replays then pushes committed transactions", func(t *testing.T) {
+		f := newFixture(t)
+		account := f.account(t, entity.Personal, 10*entity.Real)
+		payee := f.account(t, entity.Seller, 0)
+
+		events, done, cancel := watch(t, f, account, usecase.WatchInput{})
+		replayed := next(t, events)
+		assert.Equal(t, string(entity.Deposit), replayed.TransactionType)
+
+		_, err := f.usecase.ExecuteTransfer(context.Background(), account.ID, payee.ID, 300, "")
+		require.NoError(t, err)
+		pushed := next(t, events)
+		assert.Equal(t, string(entity.TransferPayer), pushed.TransactionType)
+		assert.Equal(t, "-3.00 BRL", pushed.Amount.String())
+		assert.NotNil(t, pushed.CorrelatedID)
+
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+	})
+
+	t.Run("resumes from a cursor", func(t *testing.T) {
+		f := newFixture(t)
+		account := f.account(t, entity.Personal, 10*entity.Real)
+
+		events, _, cancel := watch(t, f, account, usecase.WatchInput{})
+		first := next(t, events)
+		cancel()
+
+		_, err := f.usecase.ExecuteDeposit(context.Background(), account.ID, 500)
+		require.NoError(t, err)
+
+		events, _, _ = watch(t, f, account, usecase.WatchInput{Cursor: first.Cursor})
+		resumed := next(t, events)
+		assert.Equal(t, "5.00 BRL", resumed.Amount.String())
+		assert.NotEqual(t, first.ID, resumed.ID)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		f := newFixture(t)
+		account := f.account(t, entity.Personal, 0)
+
+		err := f.usecase.WatchTransactions(context.Background(), account.ID, usecase.WatchInput{Cursor: "invalid"}, nil)
+		assert.ErrorIs(t, err, entity.ErrUnprocessableEntity)
+	})
+}
@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/internal/logger"
+	"github.com/guilhermealvess/guicpay/internal/properties"
+	"go.uber.org/zap"
+)
+
+type transferReceivedData struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	CorrelatedID  uuid.UUID `json:"correlated_id"`
+	PayerID       uuid.UUID `json:"payer_id"`
+	PayeeID       uuid.UUID `json:"payee_id"`
+	Amount        int64     `json:"amount"`
+	Currency      string    `json:"currency"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+type WebhookUseCase interface {
+	ExecuteNewWebhook(ctx context.Context, accountID uuid.UUID, input NewWebhookInput) (*WebhookOutput, error)
+	FindWebhooks(ctx context.Context, accountID uuid.UUID) ([]*WebhookOutput, error)
+	ExecuteDeleteWebhook(ctx context.Context, accountID, webhookID uuid.UUID) error
+	FindWebhookDeliveries(ctx context.Context, accountID, webhookID uuid.UUID) ([]*WebhookDeliveryOutput, error)
+	ExecuteReplayDelivery(ctx context.Context, accountID, deliveryID uuid.UUID) (*WebhookDeliveryOutput, error)
+	ExecutePendingDeliveries(ctx context.Context)
+}
+
+type webhookUseCase struct {
+	repository gateway.WebhookRepository
+	sender     gateway.WebhookService
+}
+
+func NewWebhookUseCase(d Dependencies) WebhookUseCase {
+	return &webhookUseCase{
+		repository: d.Webhooks,
+		sender:     d.WebhookSender,
+	}
+}
+
+func (u *webhookUseCase) ExecuteNewWebhook(ctx context.Context, accountID uuid.UUID, input NewWebhookInput) (*WebhookOutput, error) {
+	events := make([]entity.WebhookEventType, 0, len(input.Events))
+	for _, e := range input.Events {
+		events = append(events, entity.WebhookEventType(e))
+	}
+
+	webhook, err := entity.NewWebhook(accountID, input.URL, input.Secret, events, properties.Props.Webhook.InsecureTargets)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.repository.CreateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	output := buildWebhookOutput(webhook)
+	output.Secret = webhook.Secret
+	return output, nil
+}
+
+func (u *webhookUseCase) FindWebhooks(ctx context.Context, accountID uuid.UUID) ([]*WebhookOutput, error) {
+	webhooks, err := u.repository.FindWebhooksByAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*WebhookOutput, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		result = append(result, buildWebhookOutput(*webhook))
+	}
+
+	return result, nil
+}
+
+func (u *webhookUseCase) ExecuteDeleteWebhook(ctx context.Context, accountID, webhookID uuid.UUID) error {
+	if _, err := u.findOwnedWebhook(ctx, accountID, webhookID); err != nil {
+		return err
+	}
+
+	return u.repository.DeleteWebhook(ctx, webhookID)
+}
+
+func (u *webhookUseCase) FindWebhookDeliveries(ctx context.Context, accountID, webhookID uuid.UUID) ([]*WebhookDeliveryOutput, error) {
+	if _, err := u.findOwnedWebhook(ctx, accountID, webhookID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := u.repository.FindWebhookDeliveries(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*WebhookDeliveryOutput, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		result = append(result, buildWebhookDeliveryOutput(*delivery))
+	}
+
+	return result, nil
+}
+
+func (u *webhookUseCase) ExecuteReplayDelivery(ctx context.Context, accountID, deliveryID uuid.UUID) (*WebhookDeliveryOutput, error) {
+	original, err := u.repository.FindWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := u.findOwnedWebhook(ctx, accountID, original.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := original.Replay()
+	if err := u.repository.SaveWebhookDeliveries(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	u.send(ctx, *webhook, &delivery)
+	return buildWebhookDeliveryOutput(delivery), nil
+}
+
+func (u *webhookUseCase) ExecutePendingDeliveries(ctx context.Context) {
+	lease := 2 * properties.Props.Webhook.Timeout
+	deliveries, err := u.repository.ClaimWebhookDeliveries(ctx, lease, properties.Props.Webhook.BatchSize)
+	if err != nil {
+		logger.Logger.Error("Error in claim webhook deliveries", zap.Error(err))
+		return
+	}
+
+	for _, delivery := range deliveries {
+		webhook, err := u.repository.FindWebhook(ctx, delivery.WebhookID)
+		if err != nil {
+			logger.Logger.Error("Error in find webhook", zap.Error(err), zap.String("delivery_id", delivery.ID.String()))
+			continue
+		}
+
+		u.send(ctx, *webhook, delivery)
+	}
+}
+
+func (u *webhookUseCase) send(ctx context.Context, webhook entity.Webhook, delivery *entity.WebhookDelivery) {
+	statusCode, err := u.sender.Send(ctx, webhook, *delivery)
+	if err != nil {
+		delivery.Failed(statusCode, err, properties.Props.Webhook.MaxAttempts, properties.Props.Webhook.Backoff)
+	} else {
+		delivery.Succeeded(statusCode)
+	}
+
+	if err := u.repository.UpdateWebhookDelivery(ctx, *delivery); err != nil {
+		logger.Logger.Error("Error in update webhook delivery", zap.Error(err), zap.String("delivery_id", delivery.ID.String()))
+		return
+	}
+
+	logger.Logger.Info("Webhook delivery attempt",
+		zap.String("delivery_id", delivery.ID.String()),
+		zap.String("status", string(delivery.Status)),
+		zap.Int("status_code", statusCode),
+		zap.Int("attempts", delivery.Attempts),
+	)
+}
+
+func (u *webhookUseCase) findOwnedWebhook(ctx context.Context, accountID, webhookID uuid.UUID) (*entity.Webhook, error) {
+	webhook, err := u.repository.FindWebhook(ctx, webhookID)
+	if err != nil {
+		return nil, notFoundAs(entity.ErrWebhookNotFound, err)
+	}
+
+	if webhook.AccountID != accountID {
+		return nil, entity.ErrWebhookNotFound.Errorf("webhook %s not found", webhookID)
+	}
+
+	return webhook, nil
+}
+
+// enqueueWebhookEvent records one pending delivery per webhook of the account subscribed to the event.
+// It is meant to run inside the same database transaction as the operation that produced the event.
+func enqueueWebhookEvent(ctx context.Context, repository gateway.WebhookRepository, accountID uuid.UUID, event entity.WebhookEvent) error {
+	webhooks, err := repository.FindWebhooksByAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	deliveries := make([]entity.WebhookDelivery, 0)
+	for _, webhook := range webhooks {
+		if !webhook.Subscribed(event.Type) {
+			continue
+		}
+
+		delivery, err := entity.NewWebhookDelivery(*webhook, event)
+		if err != nil {
+			return err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return repository.SaveWebhookDeliveries(ctx, deliveries...)
+}
+
+func buildWebhookOutput(webhook entity.Webhook) *WebhookOutput {
+	events := make([]string, 0, len(webhook.Events))
+	for _, e := range webhook.Events {
+		events = append(events, string(e))
+	}
+
+	return &WebhookOutput{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    events,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+func buildWebhookDeliveryOutput(delivery entity.WebhookDelivery) *WebhookDeliveryOutput {
+	return &WebhookDeliveryOutput{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		EventID:        delivery.EventID,
+		EventType:      string(delivery.EventType),
+		Payload:        delivery.Payload,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
@@ -0,0 +1,114 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/guilhermealvess/guicpay/internal/properties"
+	"github.com/guilhermealvess/guicpay/internal/testkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookUseCase(t *testing.T) {
+	ctx := context.Background()
+	events := []string{string(entity.WebhookEventTransferReceived)}
+
+	setup := func(t *testing.T) (*fixture, *testkit.WebhookSender, usecase.WebhookUseCase) {
+		f := newFixture(t)
+		sender := testkit.NewWebhookSender()
+		deps := f.deps
+		deps.WebhookSender = sender
+		return f, sender, usecase.NewWebhookUseCase(deps)
+	}
+
+	// transfer registers a webhook for the payee and makes a transfer to it, leaving one pending
+	// delivery.
+	transfer := func(t *testing.T, f *fixture, u usecase.WebhookUseCase) (entity.Account, *usecase.WebhookOutput) {
+		payer := f.account(t, entity.Personal, 100*entity.Real)
+		payee := f.account(t, entity.Seller, 0)
+		webhook, err := u.ExecuteNewWebhook(ctx, payee.ID, usecase.NewWebhookInput{URL: "https://seller.example.com/hooks", Events: events})
+		require.NoError(t, err)
+
+		_, err = f.usecase.ExecuteTransfer(ctx, payer.ID, payee.ID, uint64(10*entity.Real), "")
+		require.NoError(t, err)
+		return payee, webhook
+	}
+
+	t.Run("register", func(t *testing.T) {
+		f, _, u := setup(t)
+		account := f.account(t, entity.Seller, 0)
+
+		created, err := u.ExecuteNewWebhook(ctx, account.ID, usecase.NewWebhookInput{URL: "https://seller.example.com/hooks", Events: events})
+		require.NoError(t, err)
+		assert.NotEmpty(t, created.Secret)
+
+		listed, err := u.FindWebhooks(ctx, account.ID)
+		require.NoError(t, err)
+		require.Len(t, listed, 1)
+		assert.Equal(t, created.ID, listed[0].ID)
+		assert.Empty(t, listed[0].Secret)
+
+		for _, input := range []usecase.NewWebhookInput{
+			{URL: "http://seller.example.com/hooks", Events: events},
+			{URL: "https://127.0.0.1/hooks", Events: events},
+			{URL: "https://10.1.2.3/hooks", Events: events},
+			{URL: "https://seller.example.com/hooks", Secret: "short", Events: events},
+		} {
+			_, err := u.ExecuteNewWebhook(ctx, account.ID, input)
+			assert.ErrorIs(t, err, entity.ErrUnprocessableEntity, input.URL)
+		}
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		f, sender, u := setup(t)
+		payee, webhook := transfer(t, f, u)
+		other := f.account(t, entity.Seller, 0)
+
+		deliveries, err := u.FindWebhookDeliveries(ctx, payee.ID, webhook.ID)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		original := deliveries[0]
+
+		_, err = u.ExecuteReplayDelivery(ctx, other.ID, original.ID)
+		assert.ErrorIs(t, err, entity.ErrWebhookNotFound)
+		assert.Empty(t, sender.Sends())
+
+		replayed, err := u.ExecuteReplayDelivery(ctx, payee.ID, original.ID)
+		require.NoError(t, err)
+		assert.NotEqual(t, original.ID, replayed.ID)
+		assert.Equal(t, original.EventID, replayed.EventID)
+		assert.Equal(t, string(entity.WebhookDeliverySucceeded), replayed.Status)
+
+		sends := sender.Sends()
+		require.Len(t, sends, 1)
+		assert.Equal(t, replayed.ID, sends[0].Delivery.ID)
+		assert.JSONEq(t, string(original.Payload), string(sends[0].Delivery.Payload))
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		f, sender, u := setup(t)
+		payee, webhook := transfer(t, f, u)
+
+		sender.Fail(500, errors.New("500 ServerError"))
+		u.ExecutePendingDeliveries(ctx)
+		require.Len(t, sender.Sends(), 1)
+
+		deliveries, err := u.FindWebhookDeliveries(ctx, payee.ID, webhook.ID)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, string(entity.WebhookDeliveryPending), deliveries[0].Status)
+		assert.Equal(t, 1, deliveries[0].Attempts)
+		assert.Equal(t, 500, deliveries[0].LastStatusCode)
+		assert.WithinDuration(t, time.Now().Add(properties.Props.Webhook.Backoff), deliveries[0].NextAttemptAt, time.Second)
+
+		// the failed delivery waits for its backoff before it is claimed again
+		sender.Fail(0, nil)
+		u.ExecutePendingDeliveries(ctx)
+		assert.Len(t, sender.Sends(), 1)
+	})
+}
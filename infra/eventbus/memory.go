@@ -0,0 +1,91 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/internal/logger"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+)
+
+type subscription struct {
+	id      uint64
+	handler gateway.EventHandler
+	names   map[string]bool
+}
+
+func (s subscription) accepts(name string) bool {
+	return len(s.names) == 0 || s.names[name]
+}
+
+type inMemoryEventBus struct {
+	mu            sync.RWMutex
+	nextID        uint64
+	subscriptions []subscription
+}
+
+func NewInMemoryEventBus() gateway.EventBus {
+	return &inMemoryEventBus{}
+}
+
+// Subscribe registers a handler for the given event names, or for every event when none is given.
+func (b *inMemoryEventBus) Subscribe(handler gateway.EventHandler, eventNames ...string) func() {
+	names := make(map[string]bool)
+	for _, name := range eventNames {
+		names[name] = true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	id := b.nextID
+	b.subscriptions = append(b.subscriptions, subscription{id: id, handler: handler, names: names})
+
+	return func() { b.unsubscribe(id) }
+}
+
+// unsubscribe builds a new slice, so a Publish already iterating over the old one is not affected.
+func (b *inMemoryEventBus) unsubscribe(id uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subscriptions := make([]subscription, 0, len(b.subscriptions))
+	for _, s := range b.subscriptions {
+		if s.id != id {
+			subscriptions = append(subscriptions, s)
+		}
+	}
+	b.subscriptions = subscriptions
+}
+
+// Publish delivers the events in order to every matching handler. A failing handler does not
+// stop the others; all errors are returned joined.
+func (b *inMemoryEventBus) Publish(ctx context.Context, events ...entity.Event) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "EventBus.Publish")
+	defer span.End()
+
+	b.mu.RLock()
+	subscriptions := b.subscriptions
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, event := range events {
+		for _, s := range subscriptions {
+			if !s.accepts(event.EventName()) {
+				continue
+			}
+
+			if err := s.handler(ctx, event); err != nil {
+				span.RecordError(err)
+				logger.Logger.Error("Error in event handler", zap.String("event", event.EventName()), zap.Error(err))
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,78 @@
+package eventbus_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/infra/eventbus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryEventBus(t *testing.T) {
+	created := entity.AccountCreated{Timestamp: time.Now()}
+	snapshot := entity.SnapshotTaken{AccountID: uuid.New(), Timestamp: time.Now()}
+
+	t.Run("handlers receive the events they subscribed to", func(t *testing.T) {
+		bus := eventbus.NewInMemoryEventBus()
+
+		var all, snapshots []string
+		bus.Subscribe(func(_ context.Context, e entity.Event) error {
+			all = append(all, e.EventName())
+			return nil
+		})
+		bus.Subscribe(func(_ context.Context, e entity.Event) error {
+			snapshots = append(snapshots, e.EventName())
+			return nil
+		}, entity.EventSnapshotTaken)
+
+		require.NoError(t, bus.Publish(context.Background(), created, snapshot))
+		assert.Equal(t, []string{entity.EventAccountCreated, entity.EventSnapshotTaken}, all)
+		assert.Equal(t, []string{entity.EventSnapshotTaken}, snapshots)
+	})
+
+	t.Run("a failing handler does not stop the others", func(t *testing.T) {
+		bus := eventbus.NewInMemoryEventBus()
+		errHandler := errors.New("handler failed")
+
+		var delivered int
+		bus.Subscribe(func(context.Context, entity.Event) error { return errHandler })
+		bus.Subscribe(func(context.Context, entity.Event) error {
+			delivered++
+			return nil
+		})
+
+		err := bus.Publish(context.Background(), created, snapshot)
+		assert.ErrorIs(t, err, errHandler)
+		assert.Equal(t, 2, delivered)
+	})
+
+	t.Run("a handler can unsubscribe while an event is published", func(t *testing.T) {
+		bus := eventbus.NewInMemoryEventBus()
+
+		var once, always int
+		var unsubscribe func()
+		unsubscribe = bus.Subscribe(func(context.Context, entity.Event) error {
+			once++
+			unsubscribe()
+			return nil
+		})
+		bus.Subscribe(func(context.Context, entity.Event) error {
+			always++
+			return nil
+		})
+
+		// the Publish already running keeps delivering to the handler that left
+		require.NoError(t, bus.Publish(context.Background(), created, snapshot))
+		assert.Equal(t, 2, once)
+		assert.Equal(t, 2, always)
+
+		require.NoError(t, bus.Publish(context.Background(), created))
+		assert.Equal(t, 2, once)
+		assert.Equal(t, 3, always)
+	})
+}
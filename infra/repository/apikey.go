@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/infra/repository/sql/queries"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+)
+
+type apiKeyRepository struct {
+	repositoryBase
+	queries *queries.Queries
+}
+
+// NewAPIKeyRepository stores API keys for both dialects; scopes are kept space separated, as in a
+// scope claim.
+func NewAPIKeyRepository(db *sqlx.DB) gateway.APIKeyRepository {
+	return &apiKeyRepository{
+		repositoryBase: repositoryBase{
+			db: db,
+		},
+		queries: queries.New(db),
+	}
+}
+
+func (r *apiKeyRepository) CreateAPIKey(ctx context.Context, key entity.APIKey) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "CreateAPIKey")
+	defer span.End()
+
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, p := range key.Scopes {
+		scopes = append(scopes, string(p))
+	}
+
+	err := r.query(ctx).InsertAPIKey(ctx, queries.APIKey{
+		ID:         key.ID,
+		AccountID:  key.AccountID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		SecretHash: key.SecretHash,
+		Scopes:     strings.Join(scopes, " "),
+		CreatedAt:  key.CreatedAt.UTC(),
+		LastUsedAt: utcNullTime(key.LastUsedAt),
+		RevokedAt:  utcNullTime(key.RevokedAt),
+	})
+
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+func (r *apiKeyRepository) FindAPIKey(ctx context.Context, keyID uuid.UUID) (*entity.APIKey, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindAPIKey")
+	defer span.End()
+
+	row, err := r.query(ctx).FindAPIKey(ctx, keyID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return buildAPIKey(row), nil
+}
+
+func (r *apiKeyRepository) FindAPIKeyByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindAPIKeyByPrefix")
+	defer span.End()
+
+	row, err := r.query(ctx).FindAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return buildAPIKey(row), nil
+}
+
+func (r *apiKeyRepository) FindAPIKeysByAccount(ctx context.Context, accountID uuid.UUID) ([]*entity.APIKey, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindAPIKeysByAccount")
+	defer span.End()
+
+	rows, err := r.query(ctx).FindAPIKeysByAccount(ctx, accountID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	keys := make([]*entity.APIKey, 0, len(rows))
+	for i := range rows {
+		keys = append(keys, buildAPIKey(&rows[i]))
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeyRepository) RevokeAPIKey(ctx context.Context, keyID uuid.UUID, at time.Time) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "RevokeAPIKey")
+	defer span.End()
+
+	err := r.query(ctx).RevokeAPIKey(ctx, keyID, at.UTC())
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+func (r *apiKeyRepository) TouchAPIKey(ctx context.Context, keyID uuid.UUID, at time.Time) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "TouchAPIKey")
+	defer span.End()
+
+	err := r.query(ctx).TouchAPIKey(ctx, keyID, at.UTC())
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+func (r *apiKeyRepository) query(ctx context.Context) *queries.Queries {
+	tx, ok := gateway.GetTransactionContext(ctx)
+	if !ok {
+		return r.queries
+	}
+	txSQL := tx.(*sqlx.Tx)
+	return r.queries.WithTx(txSQL)
+}
+
+func buildAPIKey(row *queries.APIKey) *entity.APIKey {
+	key := entity.APIKey{
+		ID:         row.ID,
+		AccountID:  row.AccountID,
+		Name:       row.Name,
+		Prefix:     row.Prefix,
+		SecretHash: row.SecretHash,
+		CreatedAt:  row.CreatedAt,
+		LastUsedAt: row.LastUsedAt,
+		RevokedAt:  row.RevokedAt,
+	}
+
+	for _, s := range strings.Fields(row.Scopes) {
+		key.Scopes = append(key.Scopes, entity.Permission(s))
+	}
+
+	return &key
+}
@@ -0,0 +1,85 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAPIKeyRepositoryContract is the behaviour every gateway.APIKeyRepository must share.
+func testAPIKeyRepositoryContract(t *testing.T, accounts gateway.AccountRepository, repo gateway.APIKeyRepository) {
+	ctx := context.Background()
+	suffix := uuid.NewString()[:8]
+	account := entity.NewAccount(entity.Seller, "apikey", "apikey-"+suffix, "apikey-"+suffix+"@example.com", "PASSWORD", "+5511999999999")
+	require.NoError(t, accounts.CreateAccount(ctx, account))
+
+	scopes := []entity.Permission{entity.PermissionAccountRead, entity.PermissionTransfer}
+	key, value := entity.NewAPIKey(account.ID, "backend", scopes)
+	require.NoError(t, repo.CreateAPIKey(ctx, key))
+
+	t.Run("find by prefix", func(t *testing.T) {
+		prefix, secret, ok := entity.SplitAPIKey(value)
+		require.True(t, ok)
+
+		found, err := repo.FindAPIKeyByPrefix(ctx, prefix)
+		require.NoError(t, err)
+		assert.Equal(t, key.ID, found.ID)
+		assert.Equal(t, account.ID, found.AccountID)
+		assert.Equal(t, "backend", found.Name)
+		assert.Equal(t, scopes, found.Scopes)
+		assert.True(t, found.Matches(secret))
+		assert.True(t, found.Active())
+		assert.False(t, found.LastUsedAt.Valid)
+
+		_, err = repo.FindAPIKeyByPrefix(ctx, entity.APIKeyPrefix+"unknown")
+		assert.True(t, errors.Is(err, sql.ErrNoRows))
+	})
+
+	t.Run("prefix is unique", func(t *testing.T) {
+		duplicate, _ := entity.NewAPIKey(account.ID, "duplicate", scopes)
+		duplicate.Prefix = key.Prefix
+		assert.Error(t, repo.CreateAPIKey(ctx, duplicate))
+	})
+
+	t.Run("touch and revoke", func(t *testing.T) {
+		used := time.Now().Add(-time.Hour)
+		require.NoError(t, repo.TouchAPIKey(ctx, key.ID, used))
+		require.NoError(t, repo.RevokeAPIKey(ctx, key.ID, time.Now()))
+
+		found, err := repo.FindAPIKey(ctx, key.ID)
+		require.NoError(t, err)
+		assert.False(t, found.Active())
+		require.True(t, found.LastUsedAt.Valid)
+		assert.WithinDuration(t, used, found.LastUsedAt.Time, time.Second)
+
+		// revoking again keeps the first revocation
+		require.NoError(t, repo.RevokeAPIKey(ctx, key.ID, time.Now().Add(time.Hour)))
+		again, err := repo.FindAPIKey(ctx, key.ID)
+		require.NoError(t, err)
+		assert.WithinDuration(t, found.RevokedAt.Time, again.RevokedAt.Time, time.Second)
+	})
+
+	t.Run("list per account", func(t *testing.T) {
+		other, _ := entity.NewAPIKey(account.ID, "reports", []entity.Permission{entity.PermissionAccountRead})
+		other.CreatedAt = key.CreatedAt.Add(time.Minute)
+		require.NoError(t, repo.CreateAPIKey(ctx, other))
+
+		keys, err := repo.FindAPIKeysByAccount(ctx, account.ID)
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		assert.Equal(t, other.ID, keys[0].ID)
+		assert.Equal(t, key.ID, keys[1].ID)
+
+		none, err := repo.FindAPIKeysByAccount(ctx, uuid.New())
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/infra/repository/sql/queries"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+)
+
+type auditRepository struct {
+	repositoryBase
+	queries *queries.Queries
+}
+
+// NewAuditRepository writes the audit log; within a transaction in the context, entries commit or
+// roll back with the action they record.
+func NewAuditRepository(db *sqlx.DB) gateway.AuditRepository {
+	return &auditRepository{
+		repositoryBase: repositoryBase{
+			db: db,
+		},
+		queries: queries.New(db),
+	}
+}
+
+func (r *auditRepository) SaveAuditEntry(ctx context.Context, entry entity.AuditEntry) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "SaveAuditEntry")
+	defer span.End()
+
+	err := r.query(ctx).InsertAuditEntry(ctx, queries.AuditEntry{
+		ID:        entry.ID,
+		Action:    string(entry.Action),
+		ActorID:   nullUUID(entry.Actor.AccountID),
+		ActorRole: string(entry.Actor.Role),
+		APIKeyID:  nullUUID(entry.Actor.APIKeyID),
+		SubjectID: nullUUID(entry.SubjectID),
+		IP:        entry.Actor.IP,
+		UserAgent: entry.Actor.UserAgent,
+		RequestID: entry.Actor.RequestID,
+		Before:    entry.Before,
+		After:     entry.After,
+		CreatedAt: entry.CreatedAt.UTC(),
+	})
+
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+func (r *auditRepository) FindAuditEntries(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindAuditEntries")
+	defer span.End()
+
+	params := queries.ListAuditEntriesParams{
+		ActorID:   filter.ActorID,
+		SubjectID: filter.SubjectID,
+		RequestID: filter.RequestID,
+		Limit:     filter.Limit,
+	}
+
+	for _, a := range filter.Actions {
+		params.Actions = append(params.Actions, string(a))
+	}
+
+	if !filter.From.IsZero() {
+		params.From = filter.From.UTC()
+	}
+
+	if !filter.To.IsZero() {
+		params.To = filter.To.UTC()
+	}
+
+	if filter.Cursor != nil {
+		params.CursorCreatedAt = filter.Cursor.CreatedAt.UTC()
+		params.CursorID = filter.Cursor.ID
+	}
+
+	rows, err := r.query(ctx).ListAuditEntries(ctx, params)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	entries := make([]*entity.AuditEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, &entity.AuditEntry{
+			ID:     row.ID,
+			Action: entity.AuditAction(row.Action),
+			Actor: entity.AuditActor{
+				AccountID: row.ActorID.UUID,
+				Role:      entity.Role(row.ActorRole),
+				APIKeyID:  row.APIKeyID.UUID,
+				IP:        row.IP,
+				UserAgent: row.UserAgent,
+				RequestID: row.RequestID,
+			},
+			SubjectID: row.SubjectID.UUID,
+			Before:    row.Before,
+			After:     row.After,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+
+	return entries, nil
+}
+
+func (r *auditRepository) query(ctx context.Context) *queries.Queries {
+	tx, ok := gateway.GetTransactionContext(ctx)
+	if !ok {
+		return r.queries
+	}
+	txSQL := tx.(*sqlx.Tx)
+	return r.queries.WithTx(txSQL)
+}
+
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
@@ -0,0 +1,102 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAuditRepositoryContract is the behaviour every gateway.AuditRepository must share.
+func testAuditRepositoryContract(t *testing.T, repo gateway.AuditRepository) {
+	ctx := context.Background()
+	actor := entity.AuditActor{AccountID: uuid.New(), Role: entity.RoleAdmin, IP: "10.0.0.1", UserAgent: "curl/8.0", RequestID: uuid.NewString()}
+	subject := uuid.New()
+
+	save := func(t *testing.T, action entity.AuditAction, actor entity.AuditActor, createdAt time.Time) entity.AuditEntry {
+		t.Helper()
+		entry, err := entity.NewAuditEntry(action, actor, subject, map[string]string{"status": "ACTIVE"}, map[string]string{"status": "CANCELED"})
+		require.NoError(t, err)
+		entry.CreatedAt = createdAt
+		require.NoError(t, repo.SaveAuditEntry(ctx, entry))
+		return entry
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	first := save(t, entity.AuditAccountStatusChanged, actor, now.Add(-2*time.Hour))
+	second := save(t, entity.AuditTransferReversed, actor, now.Add(-time.Hour))
+	anonymous := save(t, entity.AuditLoginFailed, entity.AuditActor{IP: "10.0.0.2"}, now)
+
+	t.Run("reads entries back", func(t *testing.T) {
+		entries, err := repo.FindAuditEntries(ctx, entity.AuditFilter{SubjectID: subject})
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		assert.Equal(t, []uuid.UUID{anonymous.ID, second.ID, first.ID}, []uuid.UUID{entries[0].ID, entries[1].ID, entries[2].ID})
+
+		got := entries[2]
+		assert.Equal(t, entity.AuditAccountStatusChanged, got.Action)
+		assert.Equal(t, actor, got.Actor)
+		assert.Equal(t, subject, got.SubjectID)
+		assert.JSONEq(t, `{"status":"ACTIVE"}`, string(got.Before))
+		assert.JSONEq(t, `{"status":"CANCELED"}`, string(got.After))
+		assert.WithinDuration(t, first.CreatedAt, got.CreatedAt, time.Millisecond)
+
+		assert.Equal(t, uuid.Nil, entries[0].Actor.AccountID)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		entries, err := repo.FindAuditEntries(ctx, entity.AuditFilter{ActorID: actor.AccountID, Actions: []entity.AuditAction{entity.AuditTransferReversed}})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, second.ID, entries[0].ID)
+
+		entries, err = repo.FindAuditEntries(ctx, entity.AuditFilter{RequestID: actor.RequestID, From: now.Add(-90 * time.Minute), To: now})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, second.ID, entries[0].ID)
+	})
+
+	t.Run("pages newest first", func(t *testing.T) {
+		page, err := repo.FindAuditEntries(ctx, entity.AuditFilter{SubjectID: subject, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+
+		last := page[1]
+		rest, err := repo.FindAuditEntries(ctx, entity.AuditFilter{SubjectID: subject, Cursor: &entity.AuditCursor{CreatedAt: last.CreatedAt, ID: last.ID}})
+		require.NoError(t, err)
+		require.Len(t, rest, 1)
+		assert.Equal(t, first.ID, rest[0].ID)
+	})
+
+	t.Run("rollback discards entries", func(t *testing.T) {
+		tx, err := repo.NewTransaction(ctx)
+		require.NoError(t, err)
+
+		other := uuid.New()
+		entry, err := entity.NewAuditEntry(entity.AuditDeposit, actor, other, nil, nil)
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveAuditEntry(gateway.InjectTransaction(ctx, tx), entry))
+		require.NoError(t, tx.Rollback())
+
+		entries, err := repo.FindAuditEntries(ctx, entity.AuditFilter{SubjectID: other})
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+// testAuditLogAppendOnly checks the schema itself refuses to change the audit trail.
+func testAuditLogAppendOnly(t *testing.T, db *sqlx.DB) {
+	t.Run("audit log is append-only", func(t *testing.T) {
+		_, err := db.Exec(`UPDATE audit_log SET ip = 'forged'`)
+		assert.Error(t, err)
+
+		_, err = db.Exec(`DELETE FROM audit_log`)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+const pqUniqueViolation = pq.ErrorCode("23505")
+
+// uniqueViolation reports unique constraint failures as conflict errors of the catalog, naming the
+// column when the constraint tells which one; any other error is returned untouched.
+func uniqueViolation(err error) error {
+	var constraint string
+
+	var pqErr *pq.Error
+	var sqliteErr sqlite3.Error
+	switch {
+	case errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation:
+		constraint = pqErr.Constraint
+
+	case errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique:
+		// sqlite only reports the columns: "UNIQUE constraint failed: accounts.email"
+		constraint = sqliteErr.Error()
+
+	default:
+		return err
+	}
+
+	switch {
+	case strings.Contains(constraint, "email"):
+		return entity.ErrDuplicateEmail.Wrap(err)
+
+	case strings.Contains(constraint, "document_number"):
+		return entity.ErrDuplicateDocument.Wrap(err)
+
+	case strings.Contains(constraint, "parent_id"):
+		return entity.ErrConcurrentUpdate.Wrap(err)
+
+	default:
+		return entity.ErrDuplicateResource.Wrap(err)
+	}
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/infra/repository/sql/queries"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+)
+
+type loginAttemptRepository struct {
+	repositoryBase
+	queries *queries.Queries
+}
+
+// NewLoginAttemptRepository counts failed logins for both dialects.
+func NewLoginAttemptRepository(db *sqlx.DB) gateway.LoginAttemptRepository {
+	return &loginAttemptRepository{
+		repositoryBase: repositoryBase{
+			db: db,
+		},
+		queries: queries.New(db),
+	}
+}
+
+// RecordLoginFailure also prunes the keys whose failures are all older than the window.
+func (r *loginAttemptRepository) RecordLoginFailure(ctx context.Context, key string, at time.Time, window time.Duration) (*entity.LoginAttempts, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "RecordLoginFailure")
+	defer span.End()
+
+	since := at.Add(-window).UTC()
+	row, err := r.query(ctx).RecordLoginFailure(ctx, key, at.UTC(), since)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if err := r.query(ctx).DeleteStaleLoginAttempts(ctx, since); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return buildLoginAttempts(row), nil
+}
+
+func (r *loginAttemptRepository) FindLoginAttempts(ctx context.Context, keys ...string) ([]*entity.LoginAttempts, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindLoginAttempts")
+	defer span.End()
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.query(ctx).FindLoginAttempts(ctx, keys)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	attempts := make([]*entity.LoginAttempts, 0, len(rows))
+	for _, row := range rows {
+		attempts = append(attempts, buildLoginAttempts(row))
+	}
+
+	return attempts, nil
+}
+
+func (r *loginAttemptRepository) DeleteLoginAttempts(ctx context.Context, keys ...string) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "DeleteLoginAttempts")
+	defer span.End()
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	err := r.query(ctx).DeleteLoginAttempts(ctx, keys)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+func (r *loginAttemptRepository) query(ctx context.Context) *queries.Queries {
+	tx, ok := gateway.GetTransactionContext(ctx)
+	if !ok {
+		return r.queries
+	}
+	txSQL := tx.(*sqlx.Tx)
+	return r.queries.WithTx(txSQL)
+}
+
+func buildLoginAttempts(row *queries.LoginAttempt) *entity.LoginAttempts {
+	return &entity.LoginAttempts{
+		Key:           row.AttemptKey,
+		Failures:      row.Failures,
+		LastFailureAt: row.LastFailureAt,
+	}
+}
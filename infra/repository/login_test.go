@@ -0,0 +1,59 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testLoginAttemptRepositoryContract is the behaviour every gateway.LoginAttemptRepository must share.
+func testLoginAttemptRepositoryContract(t *testing.T, repo gateway.LoginAttemptRepository) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	email := entity.LoginEmailKey("login-" + uuid.NewString()[:8] + "@example.com")
+	ip := entity.LoginIPKey("203.0.113." + uuid.NewString()[:3])
+
+	t.Run("counts failures per key", func(t *testing.T) {
+		for i := 1; i <= 3; i++ {
+			attempts, err := repo.RecordLoginFailure(ctx, email, now.Add(time.Duration(i)*time.Second), time.Hour)
+			require.NoError(t, err)
+			assert.Equal(t, i, attempts.Failures)
+		}
+
+		_, err := repo.RecordLoginFailure(ctx, ip, now, time.Hour)
+		require.NoError(t, err)
+
+		found, err := repo.FindLoginAttempts(ctx, email, ip, entity.LoginIPKey("198.51.100.1"))
+		require.NoError(t, err)
+		require.Len(t, found, 2)
+		for _, a := range found {
+			switch a.Key {
+			case email:
+				assert.Equal(t, 3, a.Failures)
+				assert.True(t, now.Add(3*time.Second).Equal(a.LastFailureAt))
+			case ip:
+				assert.Equal(t, 1, a.Failures)
+			}
+		}
+	})
+
+	t.Run("starts over after the window", func(t *testing.T) {
+		attempts, err := repo.RecordLoginFailure(ctx, email, now.Add(2*time.Hour), time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, 1, attempts.Failures)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, repo.DeleteLoginAttempts(ctx, email, ip))
+
+		found, err := repo.FindLoginAttempts(ctx, email, ip)
+		require.NoError(t, err)
+		assert.Empty(t, found)
+	})
+}
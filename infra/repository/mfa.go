@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/infra/repository/sql/queries"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+)
+
+type mfaRepository struct {
+	repositoryBase
+	queries *queries.Queries
+}
+
+// NewMFARepository stores TOTP credentials for both dialects; recovery code hashes are kept as a
+// JSON array.
+func NewMFARepository(db *sqlx.DB) gateway.MFARepository {
+	return &mfaRepository{
+		repositoryBase: repositoryBase{
+			db: db,
+		},
+		queries: queries.New(db),
+	}
+}
+
+func (r *mfaRepository) SaveTOTP(ctx context.Context, totp entity.TOTP) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "SaveTOTP")
+	defer span.End()
+
+	codes, err := json.Marshal(totp.RecoveryCodes)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err = r.query(ctx).UpsertTOTPCredential(ctx, queries.TOTPCredential{
+		AccountID:     totp.AccountID,
+		Secret:        totp.Secret,
+		RecoveryCodes: string(codes),
+		LastStep:      totp.LastStep,
+		ConfirmedAt:   utcNullTime(totp.ConfirmedAt),
+		CreatedAt:     totp.CreatedAt.UTC(),
+		UpdatedAt:     totp.UpdatedAt.UTC(),
+	})
+
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+func (r *mfaRepository) UseTOTP(ctx context.Context, totp entity.TOTP, lastStep int64, updatedAt time.Time) (bool, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "UseTOTP")
+	defer span.End()
+
+	codes, err := json.Marshal(totp.RecoveryCodes)
+	if err != nil {
+		span.RecordError(err)
+		return false, err
+	}
+
+	used, err := r.query(ctx).UseTOTPCredential(ctx, queries.UseTOTPCredentialParams{
+		AccountID:         totp.AccountID,
+		RecoveryCodes:     string(codes),
+		LastStep:          totp.LastStep,
+		UpdatedAt:         totp.UpdatedAt.UTC(),
+		PreviousLastStep:  lastStep,
+		PreviousUpdatedAt: updatedAt.UTC(),
+	})
+
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return used, err
+}
+
+func (r *mfaRepository) FindTOTP(ctx context.Context, accountID uuid.UUID) (*entity.TOTP, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindTOTP")
+	defer span.End()
+
+	row, err := r.query(ctx).FindTOTPCredential(ctx, accountID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	totp := entity.TOTP{
+		AccountID:   row.AccountID,
+		Secret:      row.Secret,
+		LastStep:    row.LastStep,
+		ConfirmedAt: row.ConfirmedAt,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+
+	if err := json.Unmarshal([]byte(row.RecoveryCodes), &totp.RecoveryCodes); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &totp, nil
+}
+
+func (r *mfaRepository) DeleteTOTP(ctx context.Context, accountID uuid.UUID) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "DeleteTOTP")
+	defer span.End()
+
+	err := r.query(ctx).DeleteTOTPCredential(ctx, accountID)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+func (r *mfaRepository) query(ctx context.Context) *queries.Queries {
+	tx, ok := gateway.GetTransactionContext(ctx)
+	if !ok {
+		return r.queries
+	}
+	txSQL := tx.(*sqlx.Tx)
+	return r.queries.WithTx(txSQL)
+}
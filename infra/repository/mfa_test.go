@@ -0,0 +1,77 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testMFARepositoryContract is the behaviour every gateway.MFARepository must share.
+func testMFARepositoryContract(t *testing.T, accounts gateway.AccountRepository, repo gateway.MFARepository) {
+	ctx := context.Background()
+	suffix := uuid.NewString()[:8]
+	account := entity.NewAccount(entity.Personal, "mfa", "mfa-"+suffix, "mfa-"+suffix+"@example.com", "PASSWORD", "+5511999999999")
+	require.NoError(t, accounts.CreateAccount(ctx, account))
+
+	t.Run("totp lifecycle", func(t *testing.T) {
+		_, err := repo.FindTOTP(ctx, account.ID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		totp, _ := entity.NewTOTP(account.ID)
+		require.NoError(t, repo.SaveTOTP(ctx, totp))
+
+		found, err := repo.FindTOTP(ctx, account.ID)
+		require.NoError(t, err)
+		assert.Equal(t, totp.Secret, found.Secret)
+		assert.Equal(t, totp.RecoveryCodes, found.RecoveryCodes)
+		assert.False(t, found.Enabled())
+
+		found.LastStep = 42
+		found.RecoveryCodes = found.RecoveryCodes[1:]
+		found.ConfirmedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		require.NoError(t, repo.SaveTOTP(ctx, *found))
+
+		updated, err := repo.FindTOTP(ctx, account.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), updated.LastStep)
+		assert.Len(t, updated.RecoveryCodes, len(totp.RecoveryCodes)-1)
+		assert.True(t, updated.Enabled())
+
+		require.NoError(t, repo.DeleteTOTP(ctx, account.ID))
+		_, err = repo.FindTOTP(ctx, account.ID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("a code is used once", func(t *testing.T) {
+		totp, _ := entity.NewTOTP(account.ID)
+		totp.ConfirmedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		require.NoError(t, repo.SaveTOTP(ctx, totp))
+
+		first, err := repo.FindTOTP(ctx, account.ID)
+		require.NoError(t, err)
+		second := *first
+		lastStep, updatedAt := first.LastStep, first.UpdatedAt
+
+		first.LastStep, first.UpdatedAt = 7, time.Now()
+		used, err := repo.UseTOTP(ctx, *first, lastStep, updatedAt)
+		require.NoError(t, err)
+		assert.True(t, used)
+
+		second.LastStep, second.UpdatedAt = 8, time.Now()
+		used, err = repo.UseTOTP(ctx, second, lastStep, updatedAt)
+		require.NoError(t, err)
+		assert.False(t, used, "the credential changed since it was read")
+
+		found, err := repo.FindTOTP(ctx, account.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), found.LastStep)
+		require.NoError(t, repo.DeleteTOTP(ctx, account.ID))
+	})
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/guilhermealvess/guicpay/infra/repository/sql/queries"
+	"github.com/guilhermealvess/guicpay/internal/ratelimit"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+)
+
+// rateLimitSweepInterval is how often the store deletes buckets that have refilled.
+const rateLimitSweepInterval = time.Minute
+
+type rateLimitStore struct {
+	queries *queries.Queries
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewRateLimitStore keeps token buckets in the database, so every replica draws from the same
+// buckets.
+func NewRateLimitStore(db *sqlx.DB) ratelimit.Store {
+	return &rateLimitStore{queries: queries.New(db)}
+}
+
+func (s *rateLimitStore) Take(ctx context.Context, key string, limit ratelimit.Limit, now time.Time) (ratelimit.Result, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "TakeRateLimitToken")
+	defer span.End()
+
+	row, err := s.queries.TakeRateLimitToken(ctx, key, float64(limit.Burst), epochSeconds(now), limit.Rate())
+	if err != nil {
+		span.RecordError(err)
+		return ratelimit.Result{}, err
+	}
+
+	if s.sweepDue(now) {
+		if err := s.queries.DeleteFullRateLimitBuckets(ctx, epochSeconds(now)); err != nil {
+			span.RecordError(err)
+		}
+	}
+
+	return ratelimit.ResultOf(row.Allowed, row.Tokens, limit), nil
+}
+
+func (s *rateLimitStore) sweepDue(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) < rateLimitSweepInterval {
+		return false
+	}
+
+	s.lastSweep = now
+	return true
+}
+
+func epochSeconds(t time.Time) float64 {
+	return float64(t.UnixMicro()) / 1e6
+}
@@ -0,0 +1,71 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRateLimitStoreContract is the behaviour every ratelimit.Store must share.
+func testRateLimitStoreContract(t *testing.T, store ratelimit.Store) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	limit := ratelimit.Limit{Burst: 3, Period: 3 * time.Second}
+
+	t.Run("token bucket", func(t *testing.T) {
+		key := "test:" + uuid.NewString()
+		for range 3 {
+			result, err := store.Take(ctx, key, limit, now)
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+		}
+
+		result, err := store.Take(ctx, key, limit, now.Add(500*time.Millisecond))
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+		result, err = store.Take(ctx, key, limit, now.Add(time.Second))
+		require.NoError(t, err)
+		assert.True(t, result.Allowed, "one request refilled after a second")
+
+		result, err = store.Take(ctx, key, limit, now.Add(time.Second))
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+	})
+
+	t.Run("refills up to the burst", func(t *testing.T) {
+		key := "test:" + uuid.NewString()
+		_, err := store.Take(ctx, key, limit, now)
+		require.NoError(t, err)
+
+		later := now.Add(time.Hour)
+		for range 3 {
+			result, err := store.Take(ctx, key, limit, later)
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+		}
+
+		result, err := store.Take(ctx, key, limit, later)
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+	})
+
+	t.Run("keys are independent", func(t *testing.T) {
+		one := ratelimit.Limit{Burst: 1, Period: time.Minute}
+		a, b := "test:"+uuid.NewString(), "test:"+uuid.NewString()
+
+		result, err := store.Take(ctx, a, one, now)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+
+		result, err = store.Take(ctx, b, one, now)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	})
+}
@@ -1,14 +1,20 @@
 package repository
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/guilhermealvess/guicpay/domain/entity"
 	"github.com/guilhermealvess/guicpay/domain/gateway"
 	"github.com/guilhermealvess/guicpay/infra/repository/sql/queries"
+	"github.com/guilhermealvess/guicpay/internal/database"
 	"github.com/jmoiron/sqlx"
 	"go.opentelemetry.io/otel"
 )
@@ -26,7 +32,16 @@ type accountRepository struct {
 	queries *queries.Queries
 }
 
+// NewAccountRepository returns the repository matching the driver the database was opened with.
 func NewAccountRepository(db *sqlx.DB) gateway.AccountRepository {
+	if db.DriverName() == database.DriverSQLite {
+		return newSQLiteAccountRepository(db)
+	}
+
+	return newAccountRepository(db)
+}
+
+func newAccountRepository(db *sqlx.DB) *accountRepository {
 	return &accountRepository{
 		repositoryBase: repositoryBase{
 			db: db,
@@ -38,7 +53,7 @@ func NewAccountRepository(db *sqlx.DB) gateway.AccountRepository {
 func (r *accountRepository) CreateAccount(ctx context.Context, account entity.Account) error {
 	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "CreateAccount")
 	defer span.End()
-	err := r.queries.SaveAccount(ctx, queries.SaveAccountParams{
+	err := r.query(ctx).SaveAccount(ctx, queries.SaveAccountParams{
 		ID:              account.ID,
 		CustomerName:    account.CustomerName,
 		DocumentNumber:  account.DocumentNumber,
@@ -46,6 +61,7 @@ func (r *accountRepository) CreateAccount(ctx context.Context, account entity.Ac
 		PasswordEncoded: string(account.PasswordEncoded),
 		Status:          string(account.Status),
 		AccountType:     string(account.AccountType),
+		Role:            string(account.Role),
 		PhoneNumber:     account.PhoneNumber,
 		CreatedAt:       account.CreatedAt,
 		UpdatedAt:       account.UpdatedAt,
@@ -55,14 +71,14 @@ func (r *accountRepository) CreateAccount(ctx context.Context, account entity.Ac
 		span.RecordError(err)
 	}
 
-	return err
+	return uniqueViolation(err)
 }
 
 func (r *accountRepository) FindAccount(ctx context.Context, accountID uuid.UUID) (*entity.Account, error) {
 	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindAccount")
 	defer span.End()
 
-	row, err := r.queries.FindAccountByID(ctx, accountID)
+	row, err := r.query(ctx).FindAccountByID(ctx, accountID)
 	if err != nil {
 		span.RecordError(err)
 		return nil, fmt.Errorf("database: %w", err)
@@ -71,6 +87,7 @@ func (r *accountRepository) FindAccount(ctx context.Context, accountID uuid.UUID
 	account := entity.Account{
 		ID:              row.Account.ID,
 		AccountType:     entity.AccountType(row.Account.AccountType),
+		Role:            entity.Role(row.Account.Role),
 		CustomerName:    row.Account.CustomerName,
 		DocumentNumber:  row.Account.DocumentNumber,
 		Email:           row.Account.Email,
@@ -81,7 +98,7 @@ func (r *accountRepository) FindAccount(ctx context.Context, accountID uuid.UUID
 		UpdatedAt:       row.Account.UpdatedAt,
 	}
 
-	if err := json.Unmarshal(row.Transactions, &account.Wallet); err != nil {
+	if account.Wallet, err = parseWallet(row.Transactions); err != nil {
 		span.RecordError(err)
 		return nil, fmt.Errorf("database: %w", err)
 	}
@@ -93,6 +110,22 @@ func (r *accountRepository) FindAccountByIDs(ctx context.Context, ids ...uuid.UU
 	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindAccountByIDs")
 	defer span.End()
 
+	result := make(map[uuid.UUID]*entity.Account)
+	if _, ok := gateway.GetTransactionContext(ctx); ok {
+		// a transaction runs on a single connection, which cannot read concurrently
+		for _, id := range ids {
+			account, err := r.FindAccount(ctx, id)
+			if err != nil {
+				span.RecordError(err)
+				return nil, err
+			}
+
+			result[account.ID] = account
+		}
+
+		return result, nil
+	}
+
 	chError := make(chan error)
 	chAccount := make(chan *entity.Account)
 
@@ -104,7 +137,6 @@ func (r *accountRepository) FindAccountByIDs(ctx context.Context, ids ...uuid.UU
 		}(id)
 	}
 
-	result := make(map[uuid.UUID]*entity.Account)
 	for range ids {
 		if err := <-chError; err != nil {
 			span.RecordError(err)
@@ -121,9 +153,16 @@ func (r *accountRepository) FindAccountByIDs(ctx context.Context, ids ...uuid.UU
 func (r *accountRepository) SaveAtomicTransactions(ctx context.Context, transactions ...entity.Transaction) error {
 	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "SaveAtomicTransactions")
 	defer span.End()
+
+	sequences, err := feedSequences(ctx, r.query(ctx), transactions)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
 	ch := make(chan error)
-	for _, t := range transactions {
-		go func(transaction entity.Transaction) {
+	for i, t := range transactions {
+		go func(transaction entity.Transaction, seq int64) {
 			ch <- r.query(ctx).SaveTransaction(ctx, queries.SaveTransactionParams{
 				ID:              transaction.ID,
 				CorrelatedID:    transaction.CorrelatedID,
@@ -132,54 +171,97 @@ func (r *accountRepository) SaveAtomicTransactions(ctx context.Context, transact
 				Timestamp:       transaction.Timestamp,
 				Amount:          int64(transaction.Amount),
 				ParentID:        transaction.ParentID,
+				Sequence:        seq,
 			})
-		}(t)
+		}(t, sequences[i])
 	}
 
 	for range transactions {
 		if err := <-ch; err != nil {
 			span.RecordError(err)
-			return fmt.Errorf("database: %w", err)
+			return fmt.Errorf("database: %w", uniqueViolation(err))
 		}
 	}
 
 	return nil
 }
 
-func (r *accountRepository) FindAll(ctx context.Context) ([]*entity.Account, error) {
-	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindAll")
+// feedSequences numbers the transactions in the feeds of their accounts. Reserving the numbers
+// locks each account row until the transaction commits, so the numbers of an account follow commit
+// order and a reader resuming after one cannot miss a transaction that commits later. Accounts are
+// locked in id order, so concurrent transfers between the same two accounts do not deadlock.
+func feedSequences(ctx context.Context, q *queries.Queries, transactions []entity.Transaction) ([]int64, error) {
+	counts := make(map[uuid.UUID]int)
+	accountIDs := make([]uuid.UUID, 0)
+	for _, t := range transactions {
+		if counts[t.AccountID] == 0 {
+			accountIDs = append(accountIDs, t.AccountID)
+		}
+		counts[t.AccountID]++
+	}
+	slices.SortFunc(accountIDs, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+
+	next := make(map[uuid.UUID]int64, len(accountIDs))
+	for _, id := range accountIDs {
+		last, err := q.NextFeedSequence(ctx, id, counts[id])
+		if err != nil {
+			return nil, err
+		}
+		next[id] = last - int64(counts[id]) + 1
+	}
+
+	sequences := make([]int64, len(transactions))
+	for i, t := range transactions {
+		sequences[i] = next[t.AccountID]
+		next[t.AccountID]++
+	}
+
+	return sequences, nil
+}
+
+func (r *accountRepository) ListAccounts(ctx context.Context, filter entity.AccountFilter) ([]*entity.AccountSummary, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "ListAccounts")
 	defer span.End()
 
-	rows, err := r.queries.FindAll(ctx)
+	rows, err := r.query(ctx).ListAccounts(ctx, buildAccountListParams(filter))
 	if err != nil {
 		span.RecordError(err)
 		return nil, err
 	}
 
-	accounts := make([]*entity.Account, 0)
+	accounts := make([]*entity.AccountSummary, 0, len(rows))
 	for _, row := range rows {
-		account := entity.Account{
-			ID:              row.Account.ID,
-			AccountType:     entity.AccountType(row.Account.AccountType),
-			CustomerName:    row.Account.CustomerName,
-			DocumentNumber:  row.Account.DocumentNumber,
-			Email:           row.Account.Email,
-			PasswordEncoded: entity.Password(row.Account.PasswordEncoded),
-			PhoneNumber:     row.Account.PhoneNumber,
-			Status:          entity.AccountStatus(row.Account.Status),
-			CreatedAt:       row.Account.CreatedAt,
-			UpdatedAt:       row.Account.UpdatedAt,
-		}
+		accounts = append(accounts, &entity.AccountSummary{
+			Account: entity.Account{
+				ID:              row.ID,
+				AccountType:     entity.AccountType(row.AccountType),
+				Role:            entity.Role(row.Role),
+				CustomerName:    row.CustomerName,
+				DocumentNumber:  row.DocumentNumber,
+				Email:           row.Email,
+				PasswordEncoded: entity.Password(row.PasswordEncoded),
+				PhoneNumber:     row.PhoneNumber,
+				Status:          entity.AccountStatus(row.Status),
+				CreatedAt:       row.CreatedAt,
+				UpdatedAt:       row.UpdatedAt,
+			},
+			Balance: entity.Money(row.Balance),
+		})
+	}
 
-		if err := json.Unmarshal(row.Transactions, &account.Wallet); err != nil {
-			span.RecordError(err)
-			return nil, fmt.Errorf("database: %w", err)
-		}
+	return accounts, nil
+}
+
+func (r *accountRepository) CountAccounts(ctx context.Context, filter entity.AccountFilter) (int, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "CountAccounts")
+	defer span.End()
 
-		accounts = append(accounts, &account)
+	total, err := r.query(ctx).CountAccounts(ctx, buildAccountListParams(filter))
+	if err != nil {
+		span.RecordError(err)
 	}
 
-	return accounts, nil
+	return total, err
 }
 
 func (r *accountRepository) SetSnapshotTransactions(ctx context.Context, snapshotID uuid.UUID, transactionIDs uuid.UUIDs) error {
@@ -190,7 +272,39 @@ func (r *accountRepository) FindAccountByEmail(ctx context.Context, email string
 	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindAccountByEmail")
 	defer span.End()
 
-	row, err := r.queries.FindAccountByEmail(ctx, email)
+	row, err := r.query(ctx).FindAccountByEmail(ctx, email)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	account := entity.Account{
+		ID:              row.Account.ID,
+		AccountType:     entity.AccountType(row.Account.AccountType),
+		Role:            entity.Role(row.Account.Role),
+		CustomerName:    row.Account.CustomerName,
+		DocumentNumber:  row.Account.DocumentNumber,
+		Email:           row.Account.Email,
+		PasswordEncoded: entity.Password(row.Account.PasswordEncoded),
+		PhoneNumber:     row.Account.PhoneNumber,
+		Status:          entity.AccountStatus(row.Account.Status),
+		CreatedAt:       row.Account.CreatedAt,
+		UpdatedAt:       row.Account.UpdatedAt,
+	}
+
+	if account.Wallet, err = parseWallet(row.Transactions); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return &account, nil
+}
+
+func (r *accountRepository) FindAccountByDocument(ctx context.Context, document string) (*entity.Account, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindAccountByDocument")
+	defer span.End()
+
+	row, err := r.query(ctx).FindAccountByDocument(ctx, document)
 	if err != nil {
 		span.RecordError(err)
 		return nil, err
@@ -199,6 +313,7 @@ func (r *accountRepository) FindAccountByEmail(ctx context.Context, email string
 	account := entity.Account{
 		ID:              row.Account.ID,
 		AccountType:     entity.AccountType(row.Account.AccountType),
+		Role:            entity.Role(row.Account.Role),
 		CustomerName:    row.Account.CustomerName,
 		DocumentNumber:  row.Account.DocumentNumber,
 		Email:           row.Account.Email,
@@ -209,7 +324,7 @@ func (r *accountRepository) FindAccountByEmail(ctx context.Context, email string
 		UpdatedAt:       row.Account.UpdatedAt,
 	}
 
-	if err := json.Unmarshal(row.Transactions, &account.Wallet); err != nil {
+	if account.Wallet, err = parseWallet(row.Transactions); err != nil {
 		span.RecordError(err)
 		return nil, fmt.Errorf("database: %w", err)
 	}
@@ -217,11 +332,82 @@ func (r *accountRepository) FindAccountByEmail(ctx context.Context, email string
 	return &account, nil
 }
 
+func (r *accountRepository) UpdateAccount(ctx context.Context, account entity.Account) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "UpdateAccount")
+	defer span.End()
+
+	err := r.query(ctx).UpdateAccount(ctx, queries.UpdateAccountParams{
+		ID:              account.ID,
+		PasswordEncoded: string(account.PasswordEncoded),
+		Status:          string(account.Status),
+		Role:            string(account.Role),
+		UpdatedAt:       account.UpdatedAt,
+	})
+
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+func (r *accountRepository) LockAccount(ctx context.Context, accountID uuid.UUID) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "LockAccount")
+	defer span.End()
+
+	err := r.query(ctx).LockAccount(ctx, accountID)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+func (r *accountRepository) FindTransactionsByCorrelatedID(ctx context.Context, correlatedID uuid.UUID) ([]*entity.Transaction, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindTransactionsByCorrelatedID")
+	defer span.End()
+
+	rows, err := r.query(ctx).FindTransactionsByCorrelatedID(ctx, correlatedID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	transactions := make([]*entity.Transaction, 0, len(rows))
+	for _, row := range rows {
+		transactions = append(transactions, parseTransaction(row))
+	}
+
+	return transactions, nil
+}
+
 func (r *accountRepository) FindResumeAccount(ctx context.Context, email string) (*entity.ResumeAccount, error) {
 	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindResumeAccount")
 	defer span.End()
 
-	row, err := r.queries.FindResumeAccount(ctx, email)
+	row, err := r.query(ctx).FindResumeAccount(ctx, email)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	account := entity.ResumeAccount{
+		ID:              row.ID,
+		AccountType:     entity.AccountType(row.AccountType),
+		Role:            entity.Role(row.Role),
+		Email:           row.Email,
+		Status:          entity.AccountStatus(row.Status),
+		PasswordEncoded: entity.Password(row.Password),
+	}
+
+	return &account, nil
+}
+
+func (r *accountRepository) FindResumeAccountByID(ctx context.Context, accountID uuid.UUID) (*entity.ResumeAccount, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindResumeAccountByID")
+	defer span.End()
+
+	row, err := r.query(ctx).FindResumeAccountByID(ctx, accountID)
 	if err != nil {
 		span.RecordError(err)
 		return nil, err
@@ -230,6 +416,7 @@ func (r *accountRepository) FindResumeAccount(ctx context.Context, email string)
 	account := entity.ResumeAccount{
 		ID:              row.ID,
 		AccountType:     entity.AccountType(row.AccountType),
+		Role:            entity.Role(row.Role),
 		Email:           row.Email,
 		Status:          entity.AccountStatus(row.Status),
 		PasswordEncoded: entity.Password(row.Password),
@@ -238,6 +425,228 @@ func (r *accountRepository) FindResumeAccount(ctx context.Context, email string)
 	return &account, nil
 }
 
+func (r *accountRepository) FindStatement(ctx context.Context, accountID uuid.UUID, filter entity.StatementFilter) ([]*entity.StatementLine, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindStatement")
+	defer span.End()
+
+	rows, err := r.query(ctx).FindStatement(ctx, buildStatementParams(accountID, filter))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	lines := make([]*entity.StatementLine, 0, len(rows))
+	for _, row := range rows {
+		lines = append(lines, parseStatementLine(row))
+	}
+
+	return lines, nil
+}
+
+func (r *accountRepository) StreamStatement(ctx context.Context, accountID uuid.UUID, filter entity.StatementFilter, fn func(*entity.StatementLine) error) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "StreamStatement")
+	defer span.End()
+
+	params := buildStatementParams(accountID, filter)
+	params.Ascending = true
+	params.Limit = 0
+
+	err := r.query(ctx).StreamStatement(ctx, params, func(row *queries.StatementLine) error {
+		return fn(parseStatementLine(row))
+	})
+
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+// FindSnapshotBefore returns the latest snapshot taken up to the instant, or nil when there is none.
+func (r *accountRepository) FindSnapshotBefore(ctx context.Context, accountID uuid.UUID, at time.Time) (*entity.Transaction, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindSnapshotBefore")
+	defer span.End()
+
+	row, err := r.query(ctx).FindSnapshotBefore(ctx, accountID, at.UTC())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return parseTransaction(row), nil
+}
+
+func (r *accountRepository) FindTransactionsAfterSnapshot(ctx context.Context, accountID uuid.UUID, snapshot *entity.Transaction, at time.Time) (entity.Wallet, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindTransactionsAfterSnapshot")
+	defer span.End()
+
+	var snapshotAt time.Time
+	if snapshot != nil {
+		snapshotAt = snapshot.Timestamp.UTC()
+	}
+
+	rows, err := r.query(ctx).FindTransactionsAfterSnapshot(ctx, accountID, snapshotAt, at.UTC())
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	wallet := make(entity.Wallet, 0, len(rows))
+	for _, row := range rows {
+		wallet = append(wallet, parseTransaction(row))
+	}
+
+	return wallet, nil
+}
+
+// FindTransactionFeed returns up to limit transactions after the cursor, oldest first, or the
+// latest limit transactions when there is no cursor, still oldest first.
+func (r *accountRepository) FindTransactionFeed(ctx context.Context, accountID uuid.UUID, cursor *entity.FeedCursor, limit int) ([]*entity.Transaction, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindTransactionFeed")
+	defer span.End()
+
+	var rows []*queries.Transaction
+	var err error
+	if cursor != nil {
+		rows, err = r.query(ctx).FindTransactionsAfter(ctx, accountID, cursor.Sequence, limit)
+	} else {
+		rows, err = r.query(ctx).FindLatestTransactions(ctx, accountID, limit)
+		slices.Reverse(rows)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	transactions := make([]*entity.Transaction, 0, len(rows))
+	for _, row := range rows {
+		transactions = append(transactions, parseTransaction(row))
+	}
+
+	return transactions, nil
+}
+
+func (r *accountRepository) HasTransferredTo(ctx context.Context, payer, payee uuid.UUID) (bool, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "HasTransferredTo")
+	defer span.End()
+
+	exists, err := r.query(ctx).HasTransferredTo(ctx, payer, payee)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return exists, err
+}
+
+// parseWallet reads the transactions aggregated by json_agg, whose keys follow the column names.
+func parseWallet(raw json.RawMessage) (entity.Wallet, error) {
+	var rows []*queries.Transaction
+	if err := json.Unmarshal(raw, &rows); err != nil {
+		return nil, err
+	}
+
+	if rows == nil {
+		return nil, nil
+	}
+
+	wallet := make(entity.Wallet, 0, len(rows))
+	for _, row := range rows {
+		wallet = append(wallet, parseTransaction(row))
+	}
+
+	return wallet, nil
+}
+
+func parseTransaction(row *queries.Transaction) *entity.Transaction {
+	return &entity.Transaction{
+		ID:              row.ID,
+		CorrelatedID:    row.CorrelatedID,
+		AccountID:       row.AccountID,
+		TransactionType: entity.TransactionType(row.TransactionType),
+		Timestamp:       row.Timestamp,
+		Amount:          entity.Money(row.Amount),
+		SnapshotID:      row.SnapshotID,
+		ParentID:        row.ParentID,
+		Sequence:        row.Sequence,
+	}
+}
+
+func buildAccountListParams(filter entity.AccountFilter) queries.ListAccountsParams {
+	params := queries.ListAccountsParams{
+		NamePrefix:  filter.NamePrefix,
+		EmailPrefix: filter.EmailPrefix,
+		Limit:       filter.Limit,
+	}
+
+	for _, t := range filter.Types {
+		params.Types = append(params.Types, string(t))
+	}
+
+	for _, s := range filter.Statuses {
+		params.Statuses = append(params.Statuses, string(s))
+	}
+
+	if filter.Cursor != nil {
+		params.CursorCreatedAt = filter.Cursor.CreatedAt.UTC()
+		params.CursorID = filter.Cursor.ID
+	}
+
+	return params
+}
+
+func buildStatementParams(accountID uuid.UUID, filter entity.StatementFilter) queries.FindStatementParams {
+	params := queries.FindStatementParams{
+		AccountID: accountID,
+		From:      filter.From.UTC(),
+		To:        filter.To.UTC(),
+		MinAmount: int64(filter.MinAmount),
+		MaxAmount: int64(filter.MaxAmount),
+		Limit:     filter.Limit,
+	}
+
+	for _, t := range filter.Types {
+		params.Types = append(params.Types, string(t))
+	}
+
+	if filter.Cursor != nil {
+		params.CursorTimestamp = filter.Cursor.Timestamp.UTC()
+		params.CursorID = filter.Cursor.ID
+	}
+
+	return params
+}
+
+func parseStatementLine(row *queries.StatementLine) *entity.StatementLine {
+	line := entity.StatementLine{
+		Transaction: entity.Transaction{
+			ID:              row.ID,
+			CorrelatedID:    row.CorrelatedID,
+			AccountID:       row.AccountID,
+			TransactionType: entity.TransactionType(row.TransactionType),
+			Timestamp:       row.Timestamp,
+			Amount:          entity.Money(row.Amount),
+			SnapshotID:      row.SnapshotID,
+			ParentID:        row.ParentID,
+		},
+		Balance: entity.Money(row.Balance),
+	}
+
+	if row.CounterpartyID.Valid {
+		line.Counterparty = &entity.Counterparty{
+			AccountID:    row.CounterpartyID.UUID,
+			AccountType:  entity.AccountType(row.CounterpartyType.String),
+			CustomerName: row.CounterpartyName.String,
+		}
+	}
+
+	return &line
+}
+
 func (r *accountRepository) query(ctx context.Context) *queries.Queries {
 	tx, ok := gateway.GetTransactionContext(ctx)
 	if !ok {
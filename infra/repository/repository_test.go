@@ -0,0 +1,541 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/guilhermealvess/guicpay/infra/repository"
+	"github.com/guilhermealvess/guicpay/infra/repository/sql/migrations"
+	"github.com/guilhermealvess/guicpay/internal/database"
+	"github.com/guilhermealvess/guicpay/internal/ratelimit"
+	"github.com/guilhermealvess/guicpay/internal/testkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteAccountRepository(t *testing.T) {
+	db, err := database.Open(database.DriverSQLite, "file::memory:?_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := migrations.NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	testAccountRepositoryContract(t, repository.NewAccountRepository(db))
+	testNewAccountRollback(t, repository.NewAccountRepository(db), repository.NewAuditRepository(db))
+	testTokenRepositoryContract(t, repository.NewAccountRepository(db), repository.NewTokenRepository(db))
+	testMFARepositoryContract(t, repository.NewAccountRepository(db), repository.NewMFARepository(db))
+	testVerificationRepositoryContract(t, repository.NewAccountRepository(db), repository.NewVerificationRepository(db))
+	testAPIKeyRepositoryContract(t, repository.NewAccountRepository(db), repository.NewAPIKeyRepository(db))
+	testAuditRepositoryContract(t, repository.NewAuditRepository(db))
+	testAuditLogAppendOnly(t, db)
+	testLoginAttemptRepositoryContract(t, repository.NewLoginAttemptRepository(db))
+	testRateLimitStoreContract(t, repository.NewRateLimitStore(db))
+}
+
+func TestMemoryAccountRepository(t *testing.T) {
+	store := testkit.NewStore()
+	testAccountRepositoryContract(t, store)
+	testNewAccountRollback(t, store, store)
+	testTokenRepositoryContract(t, store, store)
+	testMFARepositoryContract(t, store, store)
+	testVerificationRepositoryContract(t, store, store)
+	testAPIKeyRepositoryContract(t, store, store)
+	testAuditRepositoryContract(t, store)
+	testLoginAttemptRepositoryContract(t, store)
+	testRateLimitStoreContract(t, ratelimit.NewMemoryStore())
+
+	t.Run("locking the same account conflicts", func(t *testing.T) {
+		ctx := context.Background()
+		account := entity.NewAccount(entity.Personal, "locked", "locked", "locked@example.com", "PASSWORD", "+5511999999999")
+		require.NoError(t, store.CreateAccount(ctx, account))
+
+		first, err := store.NewTransaction(ctx)
+		require.NoError(t, err)
+		second, err := store.NewTransaction(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, store.LockAccount(gateway.InjectTransaction(ctx, first), account.ID))
+		require.NoError(t, store.LockAccount(gateway.InjectTransaction(ctx, second), account.ID))
+		require.NoError(t, first.Commit())
+		assert.ErrorIs(t, second.Commit(), testkit.ErrConflict)
+	})
+}
+
+// TestPostgresAccountRepository runs against the database in TEST_DATABASE_URL, when given.
+func TestPostgresAccountRepository(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := database.Open(database.DriverPostgres, url)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := migrations.NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	testAccountRepositoryContract(t, repository.NewAccountRepository(db))
+	testAccountLock(t, repository.NewAccountRepository(db))
+	testNewAccountRollback(t, repository.NewAccountRepository(db), repository.NewAuditRepository(db))
+	testTokenRepositoryContract(t, repository.NewAccountRepository(db), repository.NewTokenRepository(db))
+	testMFARepositoryContract(t, repository.NewAccountRepository(db), repository.NewMFARepository(db))
+	testVerificationRepositoryContract(t, repository.NewAccountRepository(db), repository.NewVerificationRepository(db))
+	testAPIKeyRepositoryContract(t, repository.NewAccountRepository(db), repository.NewAPIKeyRepository(db))
+	testAuditRepositoryContract(t, repository.NewAuditRepository(db))
+	testAuditLogAppendOnly(t, db)
+	testLoginAttemptRepositoryContract(t, repository.NewLoginAttemptRepository(db))
+	testRateLimitStoreContract(t, repository.NewRateLimitStore(db))
+}
+
+// testAccountLock changes the status and the role of an account in two concurrent transactions,
+// each reading the account after locking it: the second waits for the first and keeps its change.
+// SQLite is left out, its single connection never runs two transactions at once.
+func testAccountLock(t *testing.T, repo gateway.AccountRepository) {
+	ctx := context.Background()
+	id := uuid.NewString()
+	account := entity.NewAccount(entity.Personal, "locked", id, id+"@example.com", "PASSWORD", "+5511999999999")
+	account.Status = entity.AccountStatusActive
+	require.NoError(t, repo.CreateAccount(ctx, account))
+
+	update := func(change func(*entity.Account) error) error {
+		tx, err := repo.NewTransaction(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		txCtx := gateway.InjectTransaction(ctx, tx)
+		if err := repo.LockAccount(txCtx, account.ID); err != nil {
+			return err
+		}
+
+		found, err := repo.FindAccount(txCtx, account.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := change(found); err != nil {
+			return err
+		}
+
+		if err := repo.UpdateAccount(txCtx, *found); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	}
+
+	t.Run("concurrent updates keep both changes", func(t *testing.T) {
+		tx, err := repo.NewTransaction(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		txCtx := gateway.InjectTransaction(ctx, tx)
+		require.NoError(t, repo.LockAccount(txCtx, account.ID))
+
+		done := make(chan error, 1)
+		go func() {
+			done <- update(func(a *entity.Account) error { return a.ChangeRole(entity.RoleSupport) })
+		}()
+
+		found, err := repo.FindAccount(txCtx, account.ID)
+		require.NoError(t, err)
+		require.NoError(t, found.ChangeStatus(entity.AccountStatusCanceled))
+		require.NoError(t, repo.UpdateAccount(txCtx, *found))
+		require.NoError(t, tx.Commit())
+		require.NoError(t, <-done)
+
+		found, err = repo.FindAccount(ctx, account.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entity.AccountStatusCanceled, found.Status)
+		assert.Equal(t, entity.RoleSupport, found.Role)
+	})
+}
+
+// failingAudit saves each entry and then fails, so the action recording it rolls back after all
+// of its writes.
+type failingAudit struct {
+	gateway.AuditRepository
+	saved []entity.AuditEntry
+}
+
+func (a *failingAudit) SaveAuditEntry(ctx context.Context, entry entity.AuditEntry) error {
+	if err := a.AuditRepository.SaveAuditEntry(ctx, entry); err != nil {
+		return err
+	}
+
+	a.saved = append(a.saved, entry)
+	return errors.New("audit failed")
+}
+
+// testNewAccountRollback opens an account whose audit fails: the account and its audit entry are
+// written in the transaction of the use case, so neither is left behind.
+func testNewAccountRollback(t *testing.T, accounts gateway.AccountRepository, audit gateway.AuditRepository) {
+	t.Run("rolled back account leaves no rows", func(t *testing.T) {
+		ctx := context.Background()
+		failing := &failingAudit{AuditRepository: audit}
+		accountUseCase := usecase.NewAccountUseCase(usecase.Dependencies{Accounts: accounts, Audit: failing})
+
+		id := uuid.NewString()
+		email := "rollback-" + id + "@example.com"
+		_, err := accountUseCase.ExecuteNewAccount(ctx, usecase.NewAccountInput{
+			Type:           string(entity.Personal),
+			Name:           "Rollback",
+			DocumentNumber: id,
+			Email:          email,
+			Password:       "PASSWORD",
+			PhoneNumber:    "+5511999999999",
+		})
+		require.Error(t, err)
+		require.Len(t, failing.saved, 1)
+
+		_, err = accounts.FindAccountByEmail(ctx, email)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		entries, err := audit.FindAuditEntries(ctx, entity.AuditFilter{SubjectID: failing.saved[0].SubjectID})
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+// testAccountRepositoryContract is the behaviour every gateway.AccountRepository must share.
+func testAccountRepositoryContract(t *testing.T, repo gateway.AccountRepository) {
+	ctx := context.Background()
+	suffix := uuid.NewString()[:8]
+	newAccount := func(t *testing.T, accountType entity.AccountType, name string) entity.Account {
+		t.Helper()
+		account := entity.NewAccount(accountType, name, fmt.Sprintf("%s-%s", name, suffix), fmt.Sprintf("%s-%s@example.com", name, suffix), "PASSWORD", "+5511999999999")
+		account.Status = entity.AccountStatusActive
+		require.NoError(t, repo.CreateAccount(ctx, account))
+		return account
+	}
+
+	save := func(t *testing.T, transactions ...entity.Transaction) {
+		t.Helper()
+		tx, err := repo.NewTransaction(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		require.NoError(t, repo.SaveAtomicTransactions(gateway.InjectTransaction(ctx, tx), transactions...))
+		require.NoError(t, tx.Commit())
+	}
+
+	personal := newAccount(t, entity.Personal, "personal")
+	seller := newAccount(t, entity.Seller, "seller")
+
+	t.Run("find account", func(t *testing.T) {
+		byID, err := repo.FindAccount(ctx, personal.ID)
+		require.NoError(t, err)
+		assert.Equal(t, personal.Email, byID.Email)
+		assert.Equal(t, personal.AccountType, byID.AccountType)
+		assert.Equal(t, entity.RoleCustomer, byID.Role)
+		assert.Equal(t, personal.PasswordEncoded, byID.PasswordEncoded)
+		assert.Empty(t, byID.Wallet)
+
+		byEmail, err := repo.FindAccountByEmail(ctx, personal.Email)
+		require.NoError(t, err)
+		assert.Equal(t, personal.ID, byEmail.ID)
+
+		byDocument, err := repo.FindAccountByDocument(ctx, seller.DocumentNumber)
+		require.NoError(t, err)
+		assert.Equal(t, seller.ID, byDocument.ID)
+
+		resume, err := repo.FindResumeAccount(ctx, seller.Email)
+		require.NoError(t, err)
+		assert.Equal(t, seller.ID, resume.ID)
+		assert.Equal(t, entity.Seller, resume.AccountType)
+		assert.Equal(t, entity.RoleSeller, resume.Role)
+
+		resume, err = repo.FindResumeAccountByID(ctx, personal.ID)
+		require.NoError(t, err)
+		assert.Equal(t, personal.Email, resume.Email)
+		assert.Equal(t, entity.AccountStatusActive, resume.Status)
+		_, err = repo.FindResumeAccountByID(ctx, uuid.New())
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		accounts, err := repo.FindAccountByIDs(ctx, personal.ID, seller.ID)
+		require.NoError(t, err)
+		assert.Len(t, accounts, 2)
+
+		_, err = repo.FindAccount(ctx, uuid.New())
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("unique constraints", func(t *testing.T) {
+		duplicated := entity.NewAccount(entity.Personal, "other", "other-"+suffix, personal.Email, "PASSWORD", "+5511999999999")
+		err := repo.CreateAccount(ctx, duplicated)
+		assert.ErrorIs(t, err, entity.ErrDuplicateEmail)
+		assert.ErrorIs(t, err, entity.ErrConflict)
+
+		duplicated = entity.NewAccount(entity.Personal, "other", personal.DocumentNumber, "other-"+suffix+"@example.com", "PASSWORD", "+5511999999999")
+		assert.ErrorIs(t, repo.CreateAccount(ctx, duplicated), entity.ErrDuplicateDocument)
+	})
+
+	t.Run("rollback discards transactions", func(t *testing.T) {
+		account := personal
+		deposit, err := account.Deposit(10 * entity.Real)
+		require.NoError(t, err)
+
+		tx, err := repo.NewTransaction(ctx)
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveAtomicTransactions(gateway.InjectTransaction(ctx, tx), *deposit))
+		require.NoError(t, tx.Rollback())
+
+		found, err := repo.FindAccount(ctx, personal.ID)
+		require.NoError(t, err)
+		assert.Empty(t, found.Wallet)
+	})
+
+	var transfer *entity.TransferOutput
+	t.Run("deposit and transfer", func(t *testing.T) {
+		payer, err := repo.FindAccount(ctx, personal.ID)
+		require.NoError(t, err)
+		deposit, err := payer.Deposit(100 * entity.Real)
+		require.NoError(t, err)
+		save(t, *deposit)
+
+		payer, err = repo.FindAccount(ctx, personal.ID)
+		require.NoError(t, err)
+		require.Len(t, payer.Wallet, 1)
+		assert.Equal(t, deposit.ID, payer.Wallet[0].ID)
+		assert.Equal(t, entity.Deposit, payer.Wallet[0].TransactionType)
+		assert.Equal(t, personal.ID, payer.Wallet[0].AccountID)
+		assert.WithinDuration(t, deposit.Timestamp, payer.Wallet[0].Timestamp, time.Millisecond)
+
+		payee, err := repo.FindAccount(ctx, seller.ID)
+		require.NoError(t, err)
+		transfer, err = payer.Transfer(payee, 30*entity.Real)
+		require.NoError(t, err)
+		save(t, *transfer.Payer, *transfer.Payee)
+
+		payer, err = repo.FindAccount(ctx, personal.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 70*entity.Real, payer.Wallet.Balance())
+
+		transactions, err := repo.FindTransactionsByCorrelatedID(ctx, transfer.CorrelatedID)
+		require.NoError(t, err)
+		assert.Len(t, transactions, 2)
+
+		sent, err := repo.HasTransferredTo(ctx, personal.ID, seller.ID)
+		require.NoError(t, err)
+		assert.True(t, sent)
+
+		sent, err = repo.HasTransferredTo(ctx, seller.ID, personal.ID)
+		require.NoError(t, err)
+		assert.False(t, sent)
+	})
+
+	t.Run("parent is unique per account", func(t *testing.T) {
+		payer, err := repo.FindAccount(ctx, personal.ID)
+		require.NoError(t, err)
+		payee, err := repo.FindAccount(ctx, seller.ID)
+		require.NoError(t, err)
+
+		first, err := payer.Transfer(payee, entity.Real)
+		require.NoError(t, err)
+		payer.Wallet = payer.Wallet[:len(payer.Wallet)-1]
+		second, err := payer.Transfer(payee, entity.Real)
+		require.NoError(t, err)
+		assert.Equal(t, first.Payer.ParentID, second.Payer.ParentID)
+
+		save(t, *first.Payer, *first.Payee)
+		tx, err := repo.NewTransaction(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+		assert.Error(t, repo.SaveAtomicTransactions(gateway.InjectTransaction(ctx, tx), *second.Payer, *second.Payee))
+	})
+
+	t.Run("statement", func(t *testing.T) {
+		lines, err := repo.FindStatement(ctx, personal.ID, entity.StatementFilter{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, lines, 3)
+		assert.Equal(t, 69*entity.Real, lines[0].Balance)
+		assert.Equal(t, 100*entity.Real, lines[2].Balance)
+		require.NotNil(t, lines[1].Counterparty)
+		assert.Equal(t, seller.ID, lines[1].Counterparty.AccountID)
+
+		page, err := repo.FindStatement(ctx, personal.ID, entity.StatementFilter{
+			Limit:  10,
+			Cursor: &entity.StatementCursor{Timestamp: lines[0].Timestamp, ID: lines[0].ID},
+		})
+		require.NoError(t, err)
+		assert.Len(t, page, 2)
+		assert.Equal(t, lines[1].ID, page[0].ID)
+
+		streamed := 0
+		err = repo.StreamStatement(ctx, personal.ID, entity.StatementFilter{Types: []entity.TransactionType{entity.Deposit}}, func(line *entity.StatementLine) error {
+			streamed++
+			assert.Equal(t, entity.Deposit, line.TransactionType)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, streamed)
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		tx, err := repo.NewTransaction(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+		txCtx := gateway.InjectTransaction(ctx, tx)
+
+		account, err := repo.FindAccount(txCtx, personal.ID)
+		require.NoError(t, err)
+		snapshot := account.Snapshot()
+		require.NoError(t, repo.SaveAtomicTransactions(txCtx, *snapshot))
+
+		ids := make(uuid.UUIDs, 0)
+		for _, t := range account.Wallet {
+			ids = append(ids, t.ID)
+		}
+		require.NoError(t, repo.SetSnapshotTransactions(txCtx, snapshot.ID, ids))
+		require.NoError(t, tx.Commit())
+
+		account, err = repo.FindAccount(ctx, personal.ID)
+		require.NoError(t, err)
+		require.Len(t, account.Wallet, 1)
+		assert.Equal(t, entity.Snapshot, account.Wallet[0].TransactionType)
+		assert.Equal(t, 69*entity.Real, account.Wallet.Balance())
+
+		found, err := repo.FindSnapshotBefore(ctx, personal.ID, time.Now())
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, snapshot.ID, found.ID)
+
+		later, err := repo.FindTransactionsAfterSnapshot(ctx, personal.ID, found, time.Now())
+		require.NoError(t, err)
+		assert.Empty(t, later)
+
+		before, err := repo.FindSnapshotBefore(ctx, personal.ID, snapshot.Timestamp.Add(-time.Second))
+		require.NoError(t, err)
+		assert.Nil(t, before)
+
+		assert.Error(t, repo.SetSnapshotTransactions(ctx, snapshot.ID, uuid.UUIDs{uuid.New()}))
+	})
+
+	t.Run("transaction feed", func(t *testing.T) {
+		all, err := repo.FindTransactionFeed(ctx, personal.ID, nil, 10)
+		require.NoError(t, err)
+		require.Len(t, all, 4)
+		assert.Equal(t, entity.Deposit, all[0].TransactionType)
+		assert.Equal(t, entity.Snapshot, all[3].TransactionType)
+
+		latest, err := repo.FindTransactionFeed(ctx, personal.ID, nil, 2)
+		require.NoError(t, err)
+		require.Len(t, latest, 2)
+		assert.Equal(t, all[2].ID, latest[0].ID)
+		assert.Equal(t, all[3].ID, latest[1].ID)
+
+		after, err := repo.FindTransactionFeed(ctx, personal.ID, &entity.FeedCursor{Sequence: all[0].Sequence}, 2)
+		require.NoError(t, err)
+		require.Len(t, after, 2)
+		assert.Equal(t, all[1].ID, after[0].ID)
+		assert.Equal(t, all[2].ID, after[1].ID)
+
+		// a transaction stamped before the last one read, but committed after it, still comes after
+		// the cursor
+		received, err := repo.FindTransactionFeed(ctx, seller.ID, nil, 10)
+		require.NoError(t, err)
+		require.NotEmpty(t, received)
+		last := received[len(received)-1]
+
+		account, err := repo.FindAccount(ctx, seller.ID)
+		require.NoError(t, err)
+		late, err := account.Deposit(entity.Real)
+		require.NoError(t, err)
+		late.Timestamp = received[0].Timestamp.Add(-time.Hour)
+		require.NoError(t, repo.SaveAtomicTransactions(ctx, *late))
+
+		resumed, err := repo.FindTransactionFeed(ctx, seller.ID, &entity.FeedCursor{Sequence: last.Sequence}, 10)
+		require.NoError(t, err)
+		require.Len(t, resumed, 1)
+		assert.Equal(t, late.ID, resumed[0].ID)
+		assert.Greater(t, resumed[0].Sequence, last.Sequence)
+	})
+
+	t.Run("update account", func(t *testing.T) {
+		account, err := repo.FindAccount(ctx, seller.ID)
+		require.NoError(t, err)
+		require.NoError(t, account.ChangeStatus(entity.AccountStatusCanceled))
+		require.NoError(t, account.ChangeRole(entity.RoleSupport))
+		require.NoError(t, repo.UpdateAccount(ctx, *account))
+
+		account, err = repo.FindAccount(ctx, seller.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entity.AccountStatusCanceled, account.Status)
+		assert.Equal(t, entity.RoleSupport, account.Role)
+
+		missing := entity.NewAccount(entity.Personal, "missing", "missing", "missing@example.com", "PASSWORD", "+5511999999999")
+		assert.ErrorIs(t, repo.UpdateAccount(ctx, missing), sql.ErrNoRows)
+	})
+
+	t.Run("lock account", func(t *testing.T) {
+		tx, err := repo.NewTransaction(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		txCtx := gateway.InjectTransaction(ctx, tx)
+		require.NoError(t, repo.LockAccount(txCtx, personal.ID))
+		assert.ErrorIs(t, repo.LockAccount(txCtx, uuid.New()), sql.ErrNoRows)
+		require.NoError(t, tx.Commit())
+	})
+
+	t.Run("list accounts", func(t *testing.T) {
+		listed := make([]entity.Account, 0)
+		for i := 0; i < 3; i++ {
+			account := entity.NewAccount(entity.Personal, fmt.Sprintf("Lister %s %d", suffix, i), fmt.Sprintf("lister-%d-%s", i, suffix), fmt.Sprintf("lister-%d-%s@example.com", i, suffix), "PASSWORD", "+5511999999999")
+			require.NoError(t, repo.CreateAccount(ctx, account))
+			listed = append(listed, account)
+		}
+
+		filter := entity.AccountFilter{NamePrefix: "lister " + suffix, Limit: 2}
+		total, err := repo.CountAccounts(ctx, filter)
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+
+		page, err := repo.ListAccounts(ctx, filter)
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+		assert.Equal(t, listed[2].ID, page[0].ID)
+		assert.Equal(t, listed[1].ID, page[1].ID)
+
+		filter.Cursor = &entity.AccountCursor{CreatedAt: page[1].CreatedAt, ID: page[1].ID}
+		page, err = repo.ListAccounts(ctx, filter)
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		assert.Equal(t, listed[0].ID, page[0].ID)
+
+		page, err = repo.ListAccounts(ctx, entity.AccountFilter{EmailPrefix: "PERSONAL-" + suffix})
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		assert.Equal(t, personal.ID, page[0].ID)
+		assert.Equal(t, 69*entity.Real, page[0].Balance)
+		assert.Empty(t, page[0].Wallet)
+
+		page, err = repo.ListAccounts(ctx, entity.AccountFilter{EmailPrefix: "seller-" + suffix, Types: []entity.AccountType{entity.Seller}})
+		require.NoError(t, err)
+		assert.Len(t, page, 1)
+
+		page, err = repo.ListAccounts(ctx, entity.AccountFilter{EmailPrefix: "seller-" + suffix, Statuses: []entity.AccountStatus{entity.AccountStatusActive}})
+		require.NoError(t, err)
+		assert.Empty(t, page)
+
+		total, err = repo.CountAccounts(ctx, entity.AccountFilter{NamePrefix: "lister_" + suffix})
+		require.NoError(t, err)
+		assert.Zero(t, total)
+	})
+}
@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("embedded", func(t *testing.T) {
+		postgres, err := Load(DialectPostgres)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, postgres)
+
+		for i, m := range postgres {
+			assert.Equal(t, int64(i+1), m.Version)
+			assert.NotEmpty(t, m.Down)
+			assert.Len(t, m.Checksum, 64)
+		}
+
+		sqlite, err := Load(DialectSQLite)
+		assert.NoError(t, err)
+		assert.Len(t, sqlite, len(postgres))
+		for i := range sqlite {
+			assert.Equal(t, postgres[i].Version, sqlite[i].Version)
+			assert.Equal(t, postgres[i].Name, sqlite[i].Name)
+		}
+
+		_, err = Load("mysql")
+		assert.Error(t, err)
+	})
+
+	t.Run("ordered by version", func(t *testing.T) {
+		migrations, err := load(fstest.MapFS{
+			"0010_second.up.sql":  {Data: []byte("SELECT 2;")},
+			"0002_first.up.sql":   {Data: []byte("SELECT 1;")},
+			"0002_first.down.sql": {Data: []byte("SELECT 0;")},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, migrations, 2)
+		assert.Equal(t, "first", migrations[0].Name)
+		assert.Equal(t, "SELECT 0;", migrations[0].Down)
+		assert.Equal(t, int64(10), migrations[1].Version)
+		assert.Empty(t, migrations[1].Down)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := load(fstest.MapFS{"first.up.sql": {Data: []byte("SELECT 1;")}})
+		assert.Error(t, err)
+
+		_, err = load(fstest.MapFS{"0001_first.down.sql": {Data: []byte("SELECT 1;")}})
+		assert.Error(t, err)
+	})
+}
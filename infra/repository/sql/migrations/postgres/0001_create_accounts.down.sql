@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_account_document_number;
+
+DROP INDEX IF EXISTS idx_account_email;
+
+DROP TABLE IF EXISTS transactions;
+
+DROP TABLE IF EXISTS accounts;
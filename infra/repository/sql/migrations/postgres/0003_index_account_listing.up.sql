@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_account_created_at ON accounts(created_at DESC, id DESC);
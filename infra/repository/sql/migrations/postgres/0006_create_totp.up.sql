@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS totp_credentials (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id),
+    secret VARCHAR(64) NOT NULL,
+    recovery_codes TEXT NOT NULL,
+    last_step BIGINT NOT NULL,
+    confirmed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_refresh_token_account_id;
+
+DROP TABLE IF EXISTS password_reset_tokens;
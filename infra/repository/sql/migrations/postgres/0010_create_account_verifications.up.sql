@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS account_verifications (
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    channel VARCHAR(20) NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    attempts INTEGER NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL,
+    verified_at TIMESTAMPTZ,
+    PRIMARY KEY (account_id, channel)
+);
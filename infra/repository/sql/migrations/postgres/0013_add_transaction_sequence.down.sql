@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS uq_transaction_account_feed_seq;
+ALTER TABLE transactions DROP COLUMN feed_seq;
+ALTER TABLE accounts DROP COLUMN feed_seq;
@@ -0,0 +1,13 @@
+ALTER TABLE accounts ADD COLUMN feed_seq BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE transactions ADD COLUMN feed_seq BIGINT;
+
+UPDATE transactions t SET feed_seq = numbered.seq
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY account_id ORDER BY timestamp, id) AS seq FROM transactions) numbered
+WHERE t.id = numbered.id;
+
+UPDATE accounts a SET feed_seq = COALESCE((SELECT MAX(t.feed_seq) FROM transactions t WHERE t.account_id = a.id), 0);
+
+ALTER TABLE transactions ALTER COLUMN feed_seq SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_transaction_account_feed_seq ON transactions(account_id, feed_seq);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_transaction_correlated_id;
+
+DROP INDEX IF EXISTS idx_transaction_account_id;
+
+DROP INDEX IF EXISTS idx_account_document_number;
+
+DROP INDEX IF EXISTS idx_account_email;
+
+DROP TABLE IF EXISTS transactions;
+
+DROP TABLE IF EXISTS accounts;
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS accounts(
+    id TEXT PRIMARY KEY,
+    account_type VARCHAR(50) NOT NULL,
+    customer_name VARCHAR(100) NOT NULL,
+    document_number VARCHAR(20) NOT NULL UNIQUE,
+    email VARCHAR(100) NOT NULL UNIQUE,
+    password_encoded TEXT NOT NULL,
+    phone_number VARCHAR(20) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS transactions (
+    id TEXT PRIMARY KEY,
+    correlated_id TEXT,
+    account_id TEXT NOT NULL REFERENCES accounts(id),
+    transaction_type VARCHAR(50) NOT NULL,
+    timestamp DATETIME NOT NULL,
+    amount BIGINT NOT NULL,
+    snapshot_id TEXT REFERENCES transactions(id),
+    parent_id TEXT REFERENCES transactions(id),
+    CONSTRAINT uq_account_id_parent_id UNIQUE(account_id, parent_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_email ON accounts(email);
+
+CREATE INDEX IF NOT EXISTS idx_account_document_number ON accounts(document_number);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_account_id ON transactions(account_id, timestamp);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_correlated_id ON transactions(correlated_id);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_account_created_at;
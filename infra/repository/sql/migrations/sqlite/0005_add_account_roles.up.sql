@@ -0,0 +1,3 @@
+ALTER TABLE accounts ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'CUSTOMER';
+
+UPDATE accounts SET role = 'SELLER' WHERE account_type = 'SELLER';
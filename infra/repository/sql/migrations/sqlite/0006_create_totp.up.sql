@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS totp_credentials (
+    account_id TEXT PRIMARY KEY REFERENCES accounts(id),
+    secret VARCHAR(64) NOT NULL,
+    recovery_codes TEXT NOT NULL,
+    last_step BIGINT NOT NULL,
+    confirmed_at DATETIME,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS account_verifications (
+    account_id TEXT NOT NULL REFERENCES accounts(id),
+    channel VARCHAR(20) NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    attempts INTEGER NOT NULL,
+    expires_at DATETIME NOT NULL,
+    sent_at DATETIME NOT NULL,
+    verified_at DATETIME,
+    PRIMARY KEY (account_id, channel)
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_api_key_account_id;
+DROP TABLE IF EXISTS api_keys;
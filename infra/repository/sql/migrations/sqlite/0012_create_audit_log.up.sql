@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+    id TEXT PRIMARY KEY,
+    action VARCHAR(64) NOT NULL,
+    actor_id TEXT,
+    actor_role VARCHAR(20) NOT NULL,
+    api_key_id TEXT,
+    subject_id TEXT,
+    ip VARCHAR(64) NOT NULL,
+    user_agent TEXT NOT NULL,
+    request_id VARCHAR(64) NOT NULL,
+    before_data TEXT,
+    after_data TEXT,
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor_id ON audit_log(actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_subject_id ON audit_log(subject_id);
+
+-- the audit trail is append-only: entries are never changed or removed
+CREATE TRIGGER IF NOT EXISTS audit_log_no_update BEFORE UPDATE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit_log is append-only');
+END;
+
+CREATE TRIGGER IF NOT EXISTS audit_log_no_delete BEFORE DELETE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit_log is append-only');
+END;
@@ -0,0 +1,11 @@
+ALTER TABLE accounts ADD COLUMN feed_seq INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE transactions ADD COLUMN feed_seq INTEGER NOT NULL DEFAULT 0;
+
+UPDATE transactions SET feed_seq = numbered.seq
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY account_id ORDER BY timestamp, id) AS seq FROM transactions) AS numbered
+WHERE transactions.id = numbered.id;
+
+UPDATE accounts SET feed_seq = COALESCE((SELECT MAX(t.feed_seq) FROM transactions t WHERE t.account_id = accounts.id), 0);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_transaction_account_feed_seq ON transactions(account_id, feed_seq);
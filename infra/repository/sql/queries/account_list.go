@@ -0,0 +1,120 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ListAccountsParams struct {
+	Types           []string
+	Statuses        []string
+	NamePrefix      string
+	EmailPrefix     string
+	CursorCreatedAt time.Time
+	CursorID        uuid.UUID
+	Limit           int
+}
+
+// ListAccounts reads one page of accounts, newest first. The balance is summed from the open
+// transactions by the query itself, so wallets are never loaded for listed accounts. The SQL is
+// shared by Postgres and SQLite.
+func (q *Queries) ListAccounts(ctx context.Context, params ListAccountsParams) ([]*AccountSummary, error) {
+	query := `SELECT ac.id,
+		ac.account_type,
+		ac.role,
+		ac.customer_name,
+		ac.document_number,
+		ac.email,
+		ac.password_encoded,
+		ac.phone_number,
+		ac.status,
+		ac.created_at,
+		ac.updated_at,
+		CAST(COALESCE((SELECT SUM(tr.amount) FROM transactions tr WHERE tr.account_id = ac.id AND tr.snapshot_id IS NULL), 0) AS BIGINT) AS balance
+	FROM accounts ac`
+
+	args := make([]any, 0)
+	conditions := accountListConditions(params, &args)
+	if params.CursorID != uuid.Nil {
+		args = append(args, params.CursorCreatedAt, params.CursorID)
+		conditions = append(conditions, fmt.Sprintf("(ac.created_at < $%d OR (ac.created_at = $%d AND ac.id < $%d))", len(args)-1, len(args)-1, len(args)))
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += " ORDER BY ac.created_at DESC, ac.id DESC"
+	if params.Limit > 0 {
+		args = append(args, params.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	var rows []*AccountSummary
+	if err := q.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return rows, nil
+}
+
+// CountAccounts counts every account matching the filters of ListAccounts, whatever the page.
+func (q *Queries) CountAccounts(ctx context.Context, params ListAccountsParams) (int, error) {
+	query := `SELECT COUNT(*) FROM accounts ac`
+	args := make([]any, 0)
+	if conditions := accountListConditions(params, &args); len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := q.db.GetContext(ctx, &total, query, args...); err != nil {
+		return 0, fmt.Errorf("database: %w", err)
+	}
+
+	return total, nil
+}
+
+func accountListConditions(params ListAccountsParams, args *[]any) []string {
+	conditions := make([]string, 0)
+	arg := func(v any) string {
+		*args = append(*args, v)
+		return fmt.Sprintf("$%d", len(*args))
+	}
+
+	in := func(column string, values []string) string {
+		placeholders := make([]string, 0, len(values))
+		for _, v := range values {
+			placeholders = append(placeholders, arg(v))
+		}
+		return fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ", "))
+	}
+
+	if len(params.Types) > 0 {
+		conditions = append(conditions, in("ac.account_type", params.Types))
+	}
+
+	if len(params.Statuses) > 0 {
+		conditions = append(conditions, in("ac.status", params.Statuses))
+	}
+
+	if params.NamePrefix != "" {
+		conditions = append(conditions, fmt.Sprintf(`LOWER(ac.customer_name) LIKE %s ESCAPE '\'`, arg(likePrefix(params.NamePrefix))))
+	}
+
+	if params.EmailPrefix != "" {
+		conditions = append(conditions, fmt.Sprintf(`LOWER(ac.email) LIKE %s ESCAPE '\'`, arg(likePrefix(params.EmailPrefix))))
+	}
+
+	return conditions
+}
+
+// likePrefix matches values starting with prefix, case insensitively and with the LIKE
+// wildcards in it taken literally.
+func likePrefix(prefix string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(strings.ToLower(prefix)) + "%"
+}
@@ -0,0 +1,60 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const selectAPIKey = `SELECT id, account_id, name, prefix, secret_hash, scopes, created_at, last_used_at, revoked_at FROM api_keys`
+
+func (q *Queries) InsertAPIKey(ctx context.Context, params APIKey) error {
+	const query = `INSERT INTO api_keys (id,account_id,name,prefix,secret_hash,scopes,created_at,last_used_at,revoked_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)`
+	_, err := q.db.ExecContext(ctx, query, params.ID, params.AccountID, params.Name, params.Prefix, params.SecretHash, params.Scopes, params.CreatedAt, params.LastUsedAt, params.RevokedAt)
+	return err
+}
+
+func (q *Queries) FindAPIKey(ctx context.Context, id uuid.UUID) (*APIKey, error) {
+	const query = selectAPIKey + ` WHERE id = $1`
+	var row APIKey
+	if err := q.db.GetContext(ctx, &row, query, id); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return &row, nil
+}
+
+func (q *Queries) FindAPIKeyByPrefix(ctx context.Context, prefix string) (*APIKey, error) {
+	const query = selectAPIKey + ` WHERE prefix = $1`
+	var row APIKey
+	if err := q.db.GetContext(ctx, &row, query, prefix); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return &row, nil
+}
+
+func (q *Queries) FindAPIKeysByAccount(ctx context.Context, accountID uuid.UUID) ([]APIKey, error) {
+	const query = selectAPIKey + ` WHERE account_id = $1 ORDER BY created_at DESC, id`
+	var rows []APIKey
+	if err := q.db.SelectContext(ctx, &rows, query, accountID); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (q *Queries) RevokeAPIKey(ctx context.Context, id uuid.UUID, at time.Time) error {
+	const query = `UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`
+	_, err := q.db.ExecContext(ctx, query, at, id)
+	return err
+}
+
+func (q *Queries) TouchAPIKey(ctx context.Context, id uuid.UUID, at time.Time) error {
+	const query = `UPDATE api_keys SET last_used_at = $1 WHERE id = $2`
+	_, err := q.db.ExecContext(ctx, query, at, id)
+	return err
+}
@@ -0,0 +1,91 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ListAuditEntriesParams struct {
+	Actions         []string
+	ActorID         uuid.UUID
+	SubjectID       uuid.UUID
+	RequestID       string
+	From            time.Time
+	To              time.Time
+	CursorCreatedAt time.Time
+	CursorID        uuid.UUID
+	Limit           int
+}
+
+func (q *Queries) InsertAuditEntry(ctx context.Context, params AuditEntry) error {
+	const query = `INSERT INTO audit_log (id,action,actor_id,actor_role,api_key_id,subject_id,ip,user_agent,request_id,before_data,after_data,created_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)`
+	_, err := q.db.ExecContext(ctx, query, params.ID, params.Action, params.ActorID, params.ActorRole, params.APIKeyID, params.SubjectID,
+		params.IP, params.UserAgent, params.RequestID, params.Before, params.After, params.CreatedAt)
+	return err
+}
+
+// ListAuditEntries reads one page of entries matching the filters, newest first.
+func (q *Queries) ListAuditEntries(ctx context.Context, params ListAuditEntriesParams) ([]AuditEntry, error) {
+	query := `SELECT id, action, actor_id, actor_role, api_key_id, subject_id, ip, user_agent, request_id, before_data, after_data, created_at FROM audit_log`
+
+	args := make([]any, 0)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conditions := make([]string, 0)
+	if len(params.Actions) > 0 {
+		placeholders := make([]string, 0, len(params.Actions))
+		for _, a := range params.Actions {
+			placeholders = append(placeholders, arg(a))
+		}
+		conditions = append(conditions, fmt.Sprintf("action IN (%s)", strings.Join(placeholders, ", ")))
+	}
+
+	if params.ActorID != uuid.Nil {
+		conditions = append(conditions, "actor_id = "+arg(params.ActorID))
+	}
+
+	if params.SubjectID != uuid.Nil {
+		conditions = append(conditions, "subject_id = "+arg(params.SubjectID))
+	}
+
+	if params.RequestID != "" {
+		conditions = append(conditions, "request_id = "+arg(params.RequestID))
+	}
+
+	if !params.From.IsZero() {
+		conditions = append(conditions, "created_at >= "+arg(params.From))
+	}
+
+	if !params.To.IsZero() {
+		conditions = append(conditions, "created_at <= "+arg(params.To))
+	}
+
+	if params.CursorID != uuid.Nil {
+		args = append(args, params.CursorCreatedAt, params.CursorID)
+		conditions = append(conditions, fmt.Sprintf("(created_at < $%d OR (created_at = $%d AND id < $%d))", len(args)-1, len(args)-1, len(args)))
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += " ORDER BY created_at DESC, id DESC"
+	if params.Limit > 0 {
+		query += " LIMIT " + arg(params.Limit)
+	}
+
+	var rows []AuditEntry
+	if err := q.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return rows, nil
+}
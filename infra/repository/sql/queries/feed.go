@@ -0,0 +1,51 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// NextFeedSequence reserves n positions in the account feed and returns the last one. The update
+// locks the account row until the transaction commits.
+func (q *Queries) NextFeedSequence(ctx context.Context, accountID uuid.UUID, n int) (int64, error) {
+	const query = `UPDATE accounts SET feed_seq = feed_seq + $1 WHERE id = $2 RETURNING feed_seq`
+	var seq int64
+	if err := q.db.GetContext(ctx, &seq, query, n, accountID); err != nil {
+		return 0, fmt.Errorf("database: %w", err)
+	}
+
+	return seq, nil
+}
+
+// FindTransactionsAfter reads the account transactions, snapshots included, that come after the
+// feed position, oldest first.
+func (q *Queries) FindTransactionsAfter(ctx context.Context, accountID uuid.UUID, seq int64, limit int) ([]*Transaction, error) {
+	const query = `SELECT id, correlated_id, account_id, transaction_type, timestamp, amount, snapshot_id, parent_id, feed_seq
+	FROM transactions
+	WHERE account_id = $1 AND feed_seq > $2
+	ORDER BY feed_seq
+	LIMIT $3`
+	var rows []*Transaction
+	if err := q.db.SelectContext(ctx, &rows, query, accountID, seq, limit); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return rows, nil
+}
+
+// FindLatestTransactions reads the most recent account transactions, snapshots included, newest first.
+func (q *Queries) FindLatestTransactions(ctx context.Context, accountID uuid.UUID, limit int) ([]*Transaction, error) {
+	const query = `SELECT id, correlated_id, account_id, transaction_type, timestamp, amount, snapshot_id, parent_id, feed_seq
+	FROM transactions
+	WHERE account_id = $1
+	ORDER BY feed_seq DESC
+	LIMIT $2`
+	var rows []*Transaction
+	if err := q.db.SelectContext(ctx, &rows, query, accountID, limit); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,57 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RecordLoginFailure increments the failures of the key in a single statement, so concurrent
+// guesses are all counted; a previous failure before since starts the count over.
+func (q *Queries) RecordLoginFailure(ctx context.Context, key string, at, since time.Time) (*LoginAttempt, error) {
+	const query = `INSERT INTO login_attempts (attempt_key,failures,last_failure_at) VALUES ($1,1,$2)
+	ON CONFLICT (attempt_key) DO UPDATE SET
+		failures = CASE WHEN login_attempts.last_failure_at < $3 THEN 1 ELSE login_attempts.failures + 1 END,
+		last_failure_at = excluded.last_failure_at
+	RETURNING attempt_key, failures, last_failure_at`
+	var row LoginAttempt
+	if err := q.db.GetContext(ctx, &row, query, key, at, since); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return &row, nil
+}
+
+func (q *Queries) FindLoginAttempts(ctx context.Context, keys []string) ([]*LoginAttempt, error) {
+	query, args := attemptKeysIn(`SELECT attempt_key, failures, last_failure_at FROM login_attempts WHERE attempt_key IN `, keys)
+	var rows []*LoginAttempt
+	if err := q.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (q *Queries) DeleteLoginAttempts(ctx context.Context, keys []string) error {
+	query, args := attemptKeysIn(`DELETE FROM login_attempts WHERE attempt_key IN `, keys)
+	_, err := q.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func attemptKeysIn(prefix string, keys []string) (string, []any) {
+	args := make([]any, 0, len(keys))
+	placeholders := make([]string, 0, len(keys))
+	for _, key := range keys {
+		args = append(args, key)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+
+	return prefix + `(` + strings.Join(placeholders, ",") + `)`, args
+}
+
+func (q *Queries) DeleteStaleLoginAttempts(ctx context.Context, before time.Time) error {
+	const query = `DELETE FROM login_attempts WHERE last_failure_at < $1`
+	_, err := q.db.ExecContext(ctx, query, before)
+	return err
+}
@@ -0,0 +1,61 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (q *Queries) UpsertTOTPCredential(ctx context.Context, params TOTPCredential) error {
+	const query = `INSERT INTO totp_credentials (account_id,secret,recovery_codes,last_step,confirmed_at,created_at,updated_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7)
+	ON CONFLICT (account_id) DO UPDATE SET secret = excluded.secret, recovery_codes = excluded.recovery_codes,
+		last_step = excluded.last_step, confirmed_at = excluded.confirmed_at, created_at = excluded.created_at, updated_at = excluded.updated_at`
+	_, err := q.db.ExecContext(ctx, query, params.AccountID, params.Secret, params.RecoveryCodes, params.LastStep, params.ConfirmedAt, params.CreatedAt, params.UpdatedAt)
+	return err
+}
+
+type UseTOTPCredentialParams struct {
+	AccountID         uuid.UUID
+	RecoveryCodes     string
+	LastStep          int64
+	UpdatedAt         time.Time
+	PreviousLastStep  int64
+	PreviousUpdatedAt time.Time
+}
+
+// UseTOTPCredential saves the credential only while it still has the previous step and update time,
+// and reports whether it did.
+func (q *Queries) UseTOTPCredential(ctx context.Context, params UseTOTPCredentialParams) (bool, error) {
+	const query = `UPDATE totp_credentials SET recovery_codes = $1, last_step = $2, updated_at = $3
+	WHERE account_id = $4 AND last_step = $5 AND updated_at = $6`
+	result, err := q.db.ExecContext(ctx, query, params.RecoveryCodes, params.LastStep, params.UpdatedAt, params.AccountID, params.PreviousLastStep, params.PreviousUpdatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+func (q *Queries) FindTOTPCredential(ctx context.Context, accountID uuid.UUID) (*TOTPCredential, error) {
+	const query = `SELECT account_id, secret, recovery_codes, last_step, confirmed_at, created_at, updated_at FROM totp_credentials WHERE account_id = $1`
+	var row TOTPCredential
+	if err := q.db.GetContext(ctx, &row, query, accountID); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return &row, nil
+}
+
+func (q *Queries) DeleteTOTPCredential(ctx context.Context, accountID uuid.UUID) error {
+	const query = `DELETE FROM totp_credentials WHERE account_id = $1`
+	_, err := q.db.ExecContext(ctx, query, accountID)
+	return err
+}
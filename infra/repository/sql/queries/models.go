@@ -1,6 +1,8 @@
 package queries
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,6 +11,7 @@ import (
 type Account struct {
 	ID              uuid.UUID `db:"id" json:"id"`
 	AccountType     string    `db:"account_type" json:"account_type"`
+	Role            string    `db:"role" json:"role"`
 	CustomerName    string    `db:"customer_name" json:"customer_name"`
 	DocumentNumber  string    `db:"document_number" json:"document_number"`
 	Email           string    `db:"email" json:"email"`
@@ -21,19 +24,149 @@ type Account struct {
 
 type Transaction struct {
 	ID              uuid.UUID     `db:"id" json:"id"`
-	AccountID       uuid.UUID     `db:"account_type" json:"account_type"`
+	AccountID       uuid.UUID     `db:"account_id" json:"account_id"`
 	CorrelatedID    uuid.NullUUID `db:"correlated_id" json:"correlated_id"`
 	Timestamp       time.Time     `db:"timestamp" json:"timestamp"`
 	TransactionType string        `db:"transaction_type" json:"transaction_type"`
 	Amount          int64         `db:"amount" json:"amount"`
 	SnapshotID      uuid.NullUUID `db:"snapshot_id" json:"snapshot_id"`
-	ParentID        uuid.UUID     `db:"parent_id" json:"parent_id"`
+	ParentID        uuid.NullUUID `db:"parent_id" json:"parent_id"`
+	Sequence        int64         `db:"feed_seq" json:"feed_seq"`
 }
 
 type ResumeAccount struct {
 	ID          uuid.UUID `db:"id" json:"id"`
 	AccountType string    `db:"account_type" json:"account_type"`
+	Role        string    `db:"role" json:"role"`
 	Status      string    `db:"status" json:"status"`
 	Email       string    `db:"email" json:"email"`
 	Password    string    `db:"password_encoded" json:"password_encoded"`
 }
+
+type Webhook struct {
+	ID        uuid.UUID       `db:"id" json:"id"`
+	AccountID uuid.UUID       `db:"account_id" json:"account_id"`
+	URL       string          `db:"url" json:"url"`
+	Secret    string          `db:"secret" json:"secret"`
+	Events    json.RawMessage `db:"events" json:"events"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID       `db:"id" json:"id"`
+	WebhookID      uuid.UUID       `db:"webhook_id" json:"webhook_id"`
+	EventID        uuid.UUID       `db:"event_id" json:"event_id"`
+	EventType      string          `db:"event_type" json:"event_type"`
+	Payload        json.RawMessage `db:"payload" json:"payload"`
+	Status         string          `db:"status" json:"status"`
+	Attempts       int             `db:"attempts" json:"attempts"`
+	LastStatusCode int             `db:"last_status_code" json:"last_status_code"`
+	LastError      string          `db:"last_error" json:"last_error"`
+	NextAttemptAt  time.Time       `db:"next_attempt_at" json:"next_attempt_at"`
+	CreatedAt      time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+type StatementLine struct {
+	ID               uuid.UUID      `db:"id" json:"id"`
+	CorrelatedID     uuid.NullUUID  `db:"correlated_id" json:"correlated_id"`
+	AccountID        uuid.UUID      `db:"account_id" json:"account_id"`
+	TransactionType  string         `db:"transaction_type" json:"transaction_type"`
+	Timestamp        time.Time      `db:"timestamp" json:"timestamp"`
+	Amount           int64          `db:"amount" json:"amount"`
+	SnapshotID       uuid.NullUUID  `db:"snapshot_id" json:"snapshot_id"`
+	ParentID         uuid.NullUUID  `db:"parent_id" json:"parent_id"`
+	Balance          int64          `db:"balance" json:"balance"`
+	CounterpartyID   uuid.NullUUID  `db:"counterparty_id" json:"counterparty_id"`
+	CounterpartyType sql.NullString `db:"counterparty_type" json:"counterparty_type"`
+	CounterpartyName sql.NullString `db:"counterparty_name" json:"counterparty_name"`
+}
+
+type AccountSummary struct {
+	Account
+	Balance int64 `db:"balance" json:"balance"`
+}
+
+type RefreshToken struct {
+	ID        uuid.UUID    `db:"id" json:"id"`
+	FamilyID  uuid.UUID    `db:"family_id" json:"family_id"`
+	AccountID uuid.UUID    `db:"account_id" json:"account_id"`
+	TokenHash string       `db:"token_hash" json:"token_hash"`
+	ExpiresAt time.Time    `db:"expires_at" json:"expires_at"`
+	CreatedAt time.Time    `db:"created_at" json:"created_at"`
+	UsedAt    sql.NullTime `db:"used_at" json:"used_at"`
+	RevokedAt sql.NullTime `db:"revoked_at" json:"revoked_at"`
+}
+
+type RevokedToken struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+	RevokedAt time.Time `db:"revoked_at" json:"revoked_at"`
+}
+
+type TOTPCredential struct {
+	AccountID     uuid.UUID    `db:"account_id" json:"account_id"`
+	Secret        string       `db:"secret" json:"secret"`
+	RecoveryCodes string       `db:"recovery_codes" json:"recovery_codes"`
+	LastStep      int64        `db:"last_step" json:"last_step"`
+	ConfirmedAt   sql.NullTime `db:"confirmed_at" json:"confirmed_at"`
+	CreatedAt     time.Time    `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time    `db:"updated_at" json:"updated_at"`
+}
+
+type LoginAttempt struct {
+	AttemptKey    string    `db:"attempt_key" json:"attempt_key"`
+	Failures      int       `db:"failures" json:"failures"`
+	LastFailureAt time.Time `db:"last_failure_at" json:"last_failure_at"`
+}
+
+type RateLimitBucket struct {
+	Tokens  float64 `db:"tokens" json:"tokens"`
+	Allowed bool    `db:"allowed" json:"allowed"`
+}
+
+type PasswordResetToken struct {
+	AccountID uuid.UUID    `db:"account_id" json:"account_id"`
+	TokenHash string       `db:"token_hash" json:"token_hash"`
+	ExpiresAt time.Time    `db:"expires_at" json:"expires_at"`
+	CreatedAt time.Time    `db:"created_at" json:"created_at"`
+	UsedAt    sql.NullTime `db:"used_at" json:"used_at"`
+}
+
+type AccountVerification struct {
+	AccountID  uuid.UUID    `db:"account_id" json:"account_id"`
+	Channel    string       `db:"channel" json:"channel"`
+	CodeHash   string       `db:"code_hash" json:"code_hash"`
+	Attempts   int          `db:"attempts" json:"attempts"`
+	ExpiresAt  time.Time    `db:"expires_at" json:"expires_at"`
+	SentAt     time.Time    `db:"sent_at" json:"sent_at"`
+	VerifiedAt sql.NullTime `db:"verified_at" json:"verified_at"`
+}
+
+type APIKey struct {
+	ID         uuid.UUID    `db:"id" json:"id"`
+	AccountID  uuid.UUID    `db:"account_id" json:"account_id"`
+	Name       string       `db:"name" json:"name"`
+	Prefix     string       `db:"prefix" json:"prefix"`
+	SecretHash string       `db:"secret_hash" json:"secret_hash"`
+	Scopes     string       `db:"scopes" json:"scopes"`
+	CreatedAt  time.Time    `db:"created_at" json:"created_at"`
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+	RevokedAt  sql.NullTime `db:"revoked_at" json:"revoked_at"`
+}
+
+type AuditEntry struct {
+	ID        uuid.UUID       `db:"id" json:"id"`
+	Action    string          `db:"action" json:"action"`
+	ActorID   uuid.NullUUID   `db:"actor_id" json:"actor_id"`
+	ActorRole string          `db:"actor_role" json:"actor_role"`
+	APIKeyID  uuid.NullUUID   `db:"api_key_id" json:"api_key_id"`
+	SubjectID uuid.NullUUID   `db:"subject_id" json:"subject_id"`
+	IP        string          `db:"ip" json:"ip"`
+	UserAgent string          `db:"user_agent" json:"user_agent"`
+	RequestID string          `db:"request_id" json:"request_id"`
+	Before    json.RawMessage `db:"before_data" json:"before_data"`
+	After     json.RawMessage `db:"after_data" json:"after_data"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
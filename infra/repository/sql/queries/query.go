@@ -9,13 +9,13 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/mattn/go-sqlite3"
 )
 
 type Query interface {
 	GetContext(context.Context, any, string, ...any) error
 	SelectContext(context.Context, any, string, ...any) error
 	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error)
 }
 
 type Queries struct {
@@ -40,6 +40,7 @@ type FindAccountRow struct {
 func (q *Queries) FindAccountByID(ctx context.Context, id uuid.UUID) (*FindAccountRow, error) {
 	const findAccountByID = `SELECT ac.id, 
 		ac.account_type, 
+		ac.role,
 		ac.customer_name, 
 		ac.document_number, 
 		ac.email, 
@@ -66,6 +67,7 @@ func (q *Queries) FindAccountByID(ctx context.Context, id uuid.UUID) (*FindAccou
 type SaveAccountParams struct {
 	ID              uuid.UUID `db:"id" json:"id"`
 	AccountType     string    `db:"account_type" json:"account_type"`
+	Role            string    `db:"role" json:"role"`
 	CustomerName    string    `db:"customer_name" json:"customer_name"`
 	DocumentNumber  string    `db:"document_number" json:"document_number"`
 	Email           string    `db:"email" json:"email"`
@@ -78,9 +80,9 @@ type SaveAccountParams struct {
 
 func (q *Queries) SaveAccount(ctx context.Context, params SaveAccountParams) error {
 	const query = `
-	INSERT INTO accounts (id,account_type,customer_name,document_number,email,password_encoded,phone_number,status,created_at,updated_at) 
-	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10);`
-	_, err := q.db.ExecContext(ctx, query, params.ID, params.AccountType, params.CustomerName, params.DocumentNumber, params.Email, params.PasswordEncoded, params.PhoneNumber, params.Status, params.CreatedAt, params.UpdatedAt)
+	INSERT INTO accounts (id,account_type,role,customer_name,document_number,email,password_encoded,phone_number,status,created_at,updated_at) 
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11);`
+	_, err := q.db.ExecContext(ctx, query, params.ID, params.AccountType, params.Role, params.CustomerName, params.DocumentNumber, params.Email, params.PasswordEncoded, params.PhoneNumber, params.Status, params.CreatedAt, params.UpdatedAt)
 	return err
 }
 
@@ -93,42 +95,16 @@ type SaveTransactionParams struct {
 	Amount          int64         `db:"amount" json:"amount"`
 	SnapshotID      uuid.NullUUID `db:"snapshot_id" json:"snapshot_id"`
 	ParentID        uuid.NullUUID `db:"parent_id" json:"parent_id"`
+	Sequence        int64         `db:"feed_seq" json:"feed_seq"`
 }
 
 func (q *Queries) SaveTransaction(ctx context.Context, params SaveTransactionParams) error {
-	const query = `INSERT INTO transactions (id,correlated_id,account_id,transaction_type,timestamp,amount,snapshot_id,parent_id)
-	VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`
-	_, err := q.db.ExecContext(ctx, query, params.ID, params.CorrelatedID, params.AccountID, params.TransactionType, params.Timestamp, params.Amount, params.SnapshotID, params.ParentID)
+	const query = `INSERT INTO transactions (id,correlated_id,account_id,transaction_type,timestamp,amount,snapshot_id,parent_id,feed_seq)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)`
+	_, err := q.db.ExecContext(ctx, query, params.ID, params.CorrelatedID, params.AccountID, params.TransactionType, params.Timestamp, params.Amount, params.SnapshotID, params.ParentID, params.Sequence)
 	return err
 }
 
-func (q *Queries) FindAll(ctx context.Context) ([]*FindAccountRow, error) {
-	const query = `SELECT ac.id, 
-		ac.account_type, 
-		ac.customer_name, 
-		ac.document_number, 
-		ac.email, 
-		ac.password_encoded, 
-		ac.phone_number, 
-		ac.status, 
-		ac.created_at, 
-		ac.updated_at,
-		CASE
-			WHEN tr.account_id IS NULL THEN 'null'::json
-			ELSE json_agg(tr.*)
-		END AS transactions
-	FROM accounts ac LEFT JOIN transactions tr on tr.account_id = ac.id 
-	WHERE tr.snapshot_id IS NULL
-	GROUP BY ac.id, tr.account_id ORDER BY ac.created_at desc;`
-
-	var rows []*FindAccountRow
-	if err := q.db.SelectContext(ctx, &rows, query); err != nil {
-		return nil, fmt.Errorf("database: %w", err)
-	}
-
-	return rows, nil
-}
-
 func (q *Queries) SetSnapshotTransactions(ctx context.Context, snapshotID uuid.UUID, transactionIDs uuid.UUIDs) error {
 	query := "UPDATE transactions SET snapshot_id = $1 WHERE id IN ("
 	for i, id := range transactionIDs {
@@ -159,6 +135,7 @@ func (q *Queries) SetSnapshotTransactions(ctx context.Context, snapshotID uuid.U
 func (q *Queries) FindAccountByEmail(ctx context.Context, email string) (*FindAccountRow, error) {
 	const query = `SELECT ac.id, 
 		ac.account_type, 
+		ac.role,
 		ac.customer_name, 
 		ac.document_number, 
 		ac.email, 
@@ -181,7 +158,7 @@ func (q *Queries) FindAccountByEmail(ctx context.Context, email string) (*FindAc
 }
 
 func (q *Queries) FindResumeAccount(ctx context.Context, email string) (*ResumeAccount, error) {
-	const query = `SELECT id, account_type, status, email, password_encoded FROM accounts WHERE email = $1`
+	const query = `SELECT id, account_type, role, status, email, password_encoded FROM accounts WHERE email = $1`
 	var row ResumeAccount
 	if err := q.db.GetContext(ctx, &row, query, email); err != nil {
 		return nil, err
@@ -189,3 +166,100 @@ func (q *Queries) FindResumeAccount(ctx context.Context, email string) (*ResumeA
 
 	return &row, nil
 }
+
+func (q *Queries) FindResumeAccountByID(ctx context.Context, id uuid.UUID) (*ResumeAccount, error) {
+	const query = `SELECT id, account_type, role, status, email, password_encoded FROM accounts WHERE id = $1`
+	var row ResumeAccount
+	if err := q.db.GetContext(ctx, &row, query, id); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return &row, nil
+}
+
+func (q *Queries) FindAccountByDocument(ctx context.Context, document string) (*FindAccountRow, error) {
+	const query = `SELECT ac.id, 
+		ac.account_type, 
+		ac.role,
+		ac.customer_name, 
+		ac.document_number, 
+		ac.email, 
+		ac.password_encoded, 
+		ac.phone_number, 
+		ac.status, 
+		ac.created_at, 
+		ac.updated_at,
+		CASE
+			WHEN tr.account_id IS NULL THEN 'null'::json
+			ELSE json_agg(tr.*)
+		END AS transactions
+	FROM accounts ac
+	LEFT JOIN transactions tr ON ac.id = tr.account_id
+	WHERE ac.document_number = $1 AND tr.snapshot_id IS NULL GROUP BY ac.id, tr.account_id`
+
+	var row FindAccountRow
+	err := q.db.GetContext(ctx, &row, query, document)
+	return &row, err
+}
+
+type UpdateAccountParams struct {
+	ID              uuid.UUID `db:"id" json:"id"`
+	PasswordEncoded string    `db:"password_encoded" json:"password_encoded"`
+	Status          string    `db:"status" json:"status"`
+	Role            string    `db:"role" json:"role"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *Queries) UpdateAccount(ctx context.Context, params UpdateAccountParams) error {
+	const query = `UPDATE accounts SET password_encoded = $1, status = $2, role = $3, updated_at = $4 WHERE id = $5`
+	result, err := q.db.ExecContext(ctx, query, params.PasswordEncoded, params.Status, params.Role, params.UpdatedAt, params.ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("database: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// LockAccount locks the account row until the transaction commits or rolls back.
+func (q *Queries) LockAccount(ctx context.Context, id uuid.UUID) error {
+	const query = `SELECT id FROM accounts WHERE id = $1 FOR UPDATE`
+	var locked uuid.UUID
+	if err := q.db.GetContext(ctx, &locked, query, id); err != nil {
+		return fmt.Errorf("database: %w", err)
+	}
+
+	return nil
+}
+
+func (q *Queries) FindTransactionsByCorrelatedID(ctx context.Context, correlatedID uuid.UUID) ([]*Transaction, error) {
+	const query = `SELECT id, correlated_id, account_id, transaction_type, timestamp, amount, snapshot_id, parent_id
+	FROM transactions WHERE correlated_id = $1`
+	var rows []*Transaction
+	if err := q.db.SelectContext(ctx, &rows, query, correlatedID); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (q *Queries) HasTransferredTo(ctx context.Context, payer, payee uuid.UUID) (bool, error) {
+	const query = `SELECT EXISTS (SELECT 1 FROM transactions sent
+	JOIN transactions received ON received.correlated_id = sent.correlated_id
+	WHERE sent.account_id = $1 AND sent.transaction_type = 'TRANSFER_PAYER'
+		AND received.account_id = $2 AND received.transaction_type = 'TRANSFER_PAYEE')`
+	var exists bool
+	if err := q.db.GetContext(ctx, &exists, query, payer, payee); err != nil {
+		return false, fmt.Errorf("database: %w", err)
+	}
+
+	return exists, nil
+}
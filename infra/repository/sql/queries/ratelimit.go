@@ -0,0 +1,54 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// The bucket arithmetic below is written with CASE rather than LEAST or MIN, and instants as seconds
+// since the epoch, so both dialects run it. $2 is the burst, $3 the current instant and $4 the
+// refill rate in tokens per second.
+const (
+	// refilledTokens is the bucket content refilled up to now, capped at the burst.
+	refilledTokens = `CASE WHEN rate_limit_buckets.tokens + ($3 - rate_limit_buckets.updated_at) * $4 > $2 THEN $2
+		ELSE rate_limit_buckets.tokens + ($3 - rate_limit_buckets.updated_at) * $4 END`
+	// takenTokens is what is left once a token is taken, when there is a whole one to take.
+	takenTokens = `CASE WHEN {refilled} >= 1 THEN {refilled} - 1 ELSE {refilled} END`
+)
+
+// takeRateLimitToken refills the bucket and takes a token in one statement, which locks the row, so
+// replicas sharing the database share the limit. allowed records whether the take succeeded, as the
+// tokens left alone cannot tell, and full_at when the bucket is full again and may be deleted.
+var takeRateLimitToken = strings.NewReplacer(
+	"$2", "CAST($2 AS DOUBLE PRECISION)",
+	"$3", "CAST($3 AS DOUBLE PRECISION)",
+	"$4", "CAST($4 AS DOUBLE PRECISION)",
+).Replace(strings.NewReplacer(
+	"{taken}", "("+strings.ReplaceAll(takenTokens, "{refilled}", "("+refilledTokens+")")+")",
+	"{refilled}", "("+refilledTokens+")",
+).Replace(`INSERT INTO rate_limit_buckets (bucket_key,tokens,allowed,updated_at,full_at)
+	VALUES ($1, $2 - 1, TRUE, $3, $3 + 1 / $4)
+	ON CONFLICT (bucket_key) DO UPDATE SET
+		tokens = {taken},
+		allowed = {refilled} >= 1,
+		updated_at = excluded.updated_at,
+		full_at = $3 + ($2 - {taken}) / $4
+	RETURNING tokens, allowed`))
+
+func (q *Queries) TakeRateLimitToken(ctx context.Context, key string, burst, now, rate float64) (*RateLimitBucket, error) {
+	var row RateLimitBucket
+	if err := q.db.GetContext(ctx, &row, takeRateLimitToken, key, burst, now, rate); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return &row, nil
+}
+
+// DeleteFullRateLimitBuckets removes the buckets that have refilled by now: a full bucket is the
+// same as none.
+func (q *Queries) DeleteFullRateLimitBuckets(ctx context.Context, now float64) error {
+	const query = `DELETE FROM rate_limit_buckets WHERE full_at <= $1`
+	_, err := q.db.ExecContext(ctx, query, now)
+	return err
+}
@@ -0,0 +1,73 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The queries below avoid Postgres only constructs (json_agg, ::json casts, SKIP LOCKED) and back
+// the SQLite repository; accounts and their open transactions are read in two steps instead.
+
+const selectAccount = `SELECT id, account_type, role, customer_name, document_number, email, password_encoded, phone_number, status, created_at, updated_at FROM accounts`
+
+func (q *Queries) FindAccountRecordByID(ctx context.Context, id uuid.UUID) (*Account, error) {
+	var row Account
+	if err := q.db.GetContext(ctx, &row, selectAccount+` WHERE id = $1`, id); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return &row, nil
+}
+
+func (q *Queries) FindAccountRecordByEmail(ctx context.Context, email string) (*Account, error) {
+	var row Account
+	if err := q.db.GetContext(ctx, &row, selectAccount+` WHERE email = $1`, email); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return &row, nil
+}
+
+func (q *Queries) FindAccountRecordByDocument(ctx context.Context, document string) (*Account, error) {
+	var row Account
+	if err := q.db.GetContext(ctx, &row, selectAccount+` WHERE document_number = $1`, document); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return &row, nil
+}
+
+// FindOpenTransactions returns the transactions of the account not yet consolidated in a snapshot,
+// the same set the Postgres queries aggregate into the account wallet.
+func (q *Queries) FindOpenTransactions(ctx context.Context, accountID uuid.UUID) ([]*Transaction, error) {
+	const query = `SELECT id, correlated_id, account_id, transaction_type, timestamp, amount, snapshot_id, parent_id
+	FROM transactions WHERE account_id = $1 AND snapshot_id IS NULL ORDER BY timestamp, id`
+	var rows []*Transaction
+	if err := q.db.SelectContext(ctx, &rows, query, accountID); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return rows, nil
+}
+
+// ClaimWebhookDeliveriesSQLite leases due deliveries like ClaimWebhookDeliveries; SQLite has a
+// single writer, so the update alone keeps concurrent workers from claiming the same rows.
+func (q *Queries) ClaimWebhookDeliveriesSQLite(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*WebhookDelivery, error) {
+	const query = `UPDATE webhook_deliveries SET next_attempt_at = $1
+	WHERE id IN (
+		SELECT id FROM webhook_deliveries
+		WHERE status = 'PENDING' AND next_attempt_at <= $2
+		ORDER BY next_attempt_at
+		LIMIT $3
+	)
+	RETURNING id, webhook_id, event_id, event_type, payload, status, attempts, last_status_code, last_error, next_attempt_at, created_at, updated_at`
+	var rows []*WebhookDelivery
+	if err := q.db.SelectContext(ctx, &rows, query, now.Add(lease), now, limit); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,168 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FindStatementParams struct {
+	AccountID       uuid.UUID
+	From            time.Time
+	To              time.Time
+	Types           []string
+	MinAmount       int64
+	MaxAmount       int64
+	CursorTimestamp time.Time
+	CursorID        uuid.UUID
+	Limit           int
+	Ascending       bool
+}
+
+// FindStatement reads the whole history of the account, snapshotted transactions included.
+// The running balance is computed over every non SNAPSHOT transaction before the filters apply,
+// so it stays correct whatever page or range is requested.
+func (q *Queries) FindStatement(ctx context.Context, params FindStatementParams) ([]*StatementLine, error) {
+	query, args := buildStatementQuery(params)
+	var rows []*StatementLine
+	if err := q.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return rows, nil
+}
+
+// StreamStatement runs the same query as FindStatement but hands the lines to fn one at a time,
+// so exports of long histories are never fully loaded into memory.
+func (q *Queries) StreamStatement(ctx context.Context, params FindStatementParams, fn func(*StatementLine) error) error {
+	query, args := buildStatementQuery(params)
+	rows, err := q.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("database: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row StatementLine
+		if err := rows.StructScan(&row); err != nil {
+			return fmt.Errorf("database: %w", err)
+		}
+
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func buildStatementQuery(params FindStatementParams) (string, []any) {
+	query := `WITH statement AS (
+		SELECT tr.id,
+			tr.correlated_id,
+			tr.account_id,
+			tr.transaction_type,
+			tr.timestamp,
+			tr.amount,
+			tr.snapshot_id,
+			tr.parent_id,
+			SUM(tr.amount) OVER (ORDER BY tr.timestamp, tr.id) AS balance
+		FROM transactions tr
+		WHERE tr.account_id = $1 AND tr.transaction_type <> 'SNAPSHOT'
+	)
+	SELECT st.*,
+		cp.account_id AS counterparty_id,
+		ac.account_type AS counterparty_type,
+		ac.customer_name AS counterparty_name
+	FROM statement st
+	LEFT JOIN transactions cp ON cp.correlated_id = st.correlated_id AND cp.id <> st.id
+	LEFT JOIN accounts ac ON ac.id = cp.account_id`
+
+	args := []any{params.AccountID}
+	conditions := make([]string, 0)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if !params.From.IsZero() {
+		conditions = append(conditions, "st.timestamp >= "+arg(params.From))
+	}
+
+	if !params.To.IsZero() {
+		conditions = append(conditions, "st.timestamp <= "+arg(params.To))
+	}
+
+	if len(params.Types) > 0 {
+		placeholders := make([]string, 0, len(params.Types))
+		for _, t := range params.Types {
+			placeholders = append(placeholders, arg(t))
+		}
+		conditions = append(conditions, fmt.Sprintf("st.transaction_type IN (%s)", strings.Join(placeholders, ", ")))
+	}
+
+	if params.MinAmount > 0 {
+		conditions = append(conditions, "ABS(st.amount) >= "+arg(params.MinAmount))
+	}
+
+	if params.MaxAmount > 0 {
+		conditions = append(conditions, "ABS(st.amount) <= "+arg(params.MaxAmount))
+	}
+
+	direction, comparison := "DESC", "<"
+	if params.Ascending {
+		direction, comparison = "ASC", ">"
+	}
+
+	if params.CursorID != uuid.Nil {
+		ts, id := arg(params.CursorTimestamp), arg(params.CursorID)
+		conditions = append(conditions, fmt.Sprintf("(st.timestamp %[1]s %[2]s OR (st.timestamp = %[2]s AND st.id %[1]s %[3]s))", comparison, ts, id))
+	}
+
+	if len(conditions) > 0 {
+		query += "\n\tWHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += fmt.Sprintf("\n\tORDER BY st.timestamp %[1]s, st.id %[1]s", direction)
+	if params.Limit > 0 {
+		query += " LIMIT " + arg(params.Limit)
+	}
+
+	return query, args
+}
+
+func (q *Queries) FindSnapshotBefore(ctx context.Context, accountID uuid.UUID, at time.Time) (*Transaction, error) {
+	const query = `SELECT id, correlated_id, account_id, transaction_type, timestamp, amount, snapshot_id, parent_id
+	FROM transactions
+	WHERE account_id = $1 AND transaction_type = 'SNAPSHOT' AND timestamp <= $2
+	ORDER BY timestamp DESC LIMIT 1`
+	var row Transaction
+	if err := q.db.GetContext(ctx, &row, query, accountID, at); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return &row, nil
+}
+
+// FindTransactionsAfterSnapshot returns the transactions up to the instant that are not already
+// consolidated in the snapshot taken at snapshotAt (or in any snapshot before it). A transaction
+// consolidated by a later snapshot still counts, as that snapshot happened after snapshotAt.
+func (q *Queries) FindTransactionsAfterSnapshot(ctx context.Context, accountID uuid.UUID, snapshotAt, at time.Time) ([]*Transaction, error) {
+	const query = `SELECT tr.id, tr.correlated_id, tr.account_id, tr.transaction_type, tr.timestamp, tr.amount, tr.snapshot_id, tr.parent_id
+	FROM transactions tr
+	LEFT JOIN transactions sn ON sn.id = tr.snapshot_id
+	WHERE tr.account_id = $1
+		AND tr.transaction_type <> 'SNAPSHOT'
+		AND tr.timestamp <= $2
+		AND (sn.id IS NULL OR sn.timestamp > $3)
+	ORDER BY tr.timestamp, tr.id`
+	var rows []*Transaction
+	if err := q.db.SelectContext(ctx, &rows, query, accountID, at, snapshotAt); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,135 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (q *Queries) SaveRefreshToken(ctx context.Context, params RefreshToken) error {
+	const query = `INSERT INTO refresh_tokens (id,family_id,account_id,token_hash,expires_at,created_at,used_at,revoked_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`
+	_, err := q.db.ExecContext(ctx, query, params.ID, params.FamilyID, params.AccountID, params.TokenHash, params.ExpiresAt, params.CreatedAt, params.UsedAt, params.RevokedAt)
+	return err
+}
+
+func (q *Queries) FindRefreshTokenByHash(ctx context.Context, tokenHash string) (*RefreshToken, error) {
+	const query = `SELECT id, family_id, account_id, token_hash, expires_at, created_at, used_at, revoked_at FROM refresh_tokens WHERE token_hash = $1`
+	var row RefreshToken
+	if err := q.db.GetContext(ctx, &row, query, tokenHash); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return &row, nil
+}
+
+// UseRefreshToken sets used_at only while the token is unused and not revoked, so of two
+// concurrent exchanges of the same token only one affects a row.
+func (q *Queries) UseRefreshToken(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	const query = `UPDATE refresh_tokens SET used_at = $1 WHERE id = $2 AND used_at IS NULL AND revoked_at IS NULL`
+	result, err := q.db.ExecContext(ctx, query, at, id)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+func (q *Queries) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID, at time.Time) error {
+	const query = `UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL`
+	_, err := q.db.ExecContext(ctx, query, at, familyID)
+	return err
+}
+
+// SaveRevokedToken keeps the first revocation of an id: tokens cannot be issued under a revoked
+// id, so a later one never needs to last longer.
+func (q *Queries) SaveRevokedToken(ctx context.Context, params RevokedToken) error {
+	const query = `INSERT INTO revoked_tokens (id,expires_at,revoked_at) VALUES ($1,$2,$3) ON CONFLICT (id) DO NOTHING`
+	_, err := q.db.ExecContext(ctx, query, params.ID, params.ExpiresAt, params.RevokedAt)
+	return err
+}
+
+func (q *Queries) CountRevokedTokens(ctx context.Context, ids []uuid.UUID, now time.Time) (int, error) {
+	args := []any{now}
+	placeholders := make([]string, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+
+	query := `SELECT COUNT(*) FROM revoked_tokens WHERE expires_at > $1 AND id IN (` + strings.Join(placeholders, ",") + `)`
+	var count int
+	if err := q.db.GetContext(ctx, &count, query, args...); err != nil {
+		return 0, fmt.Errorf("database: %w", err)
+	}
+
+	return count, nil
+}
+
+func (q *Queries) DeleteExpiredRevokedTokens(ctx context.Context, now time.Time) error {
+	const query = `DELETE FROM revoked_tokens WHERE expires_at <= $1`
+	_, err := q.db.ExecContext(ctx, query, now)
+	return err
+}
+
+// FindOpenTokenFamilies returns the sessions of the account with a refresh token still exchangeable.
+func (q *Queries) FindOpenTokenFamilies(ctx context.Context, accountID uuid.UUID, now time.Time) ([]uuid.UUID, error) {
+	const query = `SELECT DISTINCT family_id FROM refresh_tokens WHERE account_id = $1 AND revoked_at IS NULL AND expires_at > $2`
+	var rows []uuid.UUID
+	if err := q.db.SelectContext(ctx, &rows, query, accountID, now); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (q *Queries) RevokeAccountRefreshTokens(ctx context.Context, accountID uuid.UUID, at time.Time) error {
+	const query = `UPDATE refresh_tokens SET revoked_at = $1 WHERE account_id = $2 AND revoked_at IS NULL`
+	_, err := q.db.ExecContext(ctx, query, at, accountID)
+	return err
+}
+
+// UpsertPasswordResetToken replaces the reset token the account may have.
+func (q *Queries) UpsertPasswordResetToken(ctx context.Context, params PasswordResetToken) error {
+	const query = `INSERT INTO password_reset_tokens (account_id,token_hash,expires_at,created_at,used_at)
+	VALUES ($1,$2,$3,$4,$5)
+	ON CONFLICT (account_id) DO UPDATE SET token_hash = excluded.token_hash, expires_at = excluded.expires_at,
+		created_at = excluded.created_at, used_at = excluded.used_at`
+	_, err := q.db.ExecContext(ctx, query, params.AccountID, params.TokenHash, params.ExpiresAt, params.CreatedAt, params.UsedAt)
+	return err
+}
+
+func (q *Queries) FindPasswordResetTokenByHash(ctx context.Context, tokenHash string) (*PasswordResetToken, error) {
+	const query = `SELECT account_id, token_hash, expires_at, created_at, used_at FROM password_reset_tokens WHERE token_hash = $1`
+	var row PasswordResetToken
+	if err := q.db.GetContext(ctx, &row, query, tokenHash); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return &row, nil
+}
+
+// UsePasswordResetToken sets used_at only while the token is unused, so of two concurrent resets
+// with the same token only one affects a row.
+func (q *Queries) UsePasswordResetToken(ctx context.Context, tokenHash string, at time.Time) (bool, error) {
+	const query = `UPDATE password_reset_tokens SET used_at = $1 WHERE token_hash = $2 AND used_at IS NULL`
+	result, err := q.db.ExecContext(ctx, query, at, tokenHash)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
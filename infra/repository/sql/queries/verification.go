@@ -0,0 +1,45 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+func (q *Queries) UpsertAccountVerification(ctx context.Context, params AccountVerification) error {
+	const query = `INSERT INTO account_verifications (account_id,channel,code_hash,attempts,expires_at,sent_at,verified_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7)
+	ON CONFLICT (account_id, channel) DO UPDATE SET code_hash = excluded.code_hash, attempts = excluded.attempts,
+		expires_at = excluded.expires_at, sent_at = excluded.sent_at, verified_at = excluded.verified_at`
+	_, err := q.db.ExecContext(ctx, query, params.AccountID, params.Channel, params.CodeHash, params.Attempts, params.ExpiresAt, params.SentAt, params.VerifiedAt)
+	return err
+}
+
+// CountAccountVerificationAttempt increments attempts only while the code has some left, so
+// concurrent checks cannot go past the limit.
+func (q *Queries) CountAccountVerificationAttempt(ctx context.Context, accountID uuid.UUID, channel string, maxAttempts int) (bool, error) {
+	const query = `UPDATE account_verifications SET attempts = attempts + 1
+	WHERE account_id = $1 AND channel = $2 AND attempts < $3 AND verified_at IS NULL`
+	result, err := q.db.ExecContext(ctx, query, accountID, channel, maxAttempts)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+func (q *Queries) FindAccountVerifications(ctx context.Context, accountID uuid.UUID) ([]AccountVerification, error) {
+	const query = `SELECT account_id, channel, code_hash, attempts, expires_at, sent_at, verified_at FROM account_verifications WHERE account_id = $1 ORDER BY channel`
+	var rows []AccountVerification
+	if err := q.db.SelectContext(ctx, &rows, query, accountID); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return rows, nil
+}
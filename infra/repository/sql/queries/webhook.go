@@ -0,0 +1,111 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (q *Queries) SaveWebhook(ctx context.Context, params Webhook) error {
+	const query = `INSERT INTO webhooks (id,account_id,url,secret,events,created_at,updated_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7)`
+	_, err := q.db.ExecContext(ctx, query, params.ID, params.AccountID, params.URL, params.Secret, params.Events, params.CreatedAt, params.UpdatedAt)
+	return err
+}
+
+func (q *Queries) FindWebhookByID(ctx context.Context, id uuid.UUID) (*Webhook, error) {
+	const query = `SELECT id, account_id, url, secret, events, created_at, updated_at FROM webhooks WHERE id = $1`
+	var row Webhook
+	if err := q.db.GetContext(ctx, &row, query, id); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return &row, nil
+}
+
+func (q *Queries) FindWebhooksByAccountID(ctx context.Context, accountID uuid.UUID) ([]*Webhook, error) {
+	const query = `SELECT id, account_id, url, secret, events, created_at, updated_at FROM webhooks WHERE account_id = $1 ORDER BY created_at`
+	var rows []*Webhook
+	if err := q.db.SelectContext(ctx, &rows, query, accountID); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (q *Queries) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	const query = `DELETE FROM webhooks WHERE id = $1`
+	result, err := q.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("database: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (q *Queries) SaveWebhookDelivery(ctx context.Context, params WebhookDelivery) error {
+	const query = `INSERT INTO webhook_deliveries (id,webhook_id,event_id,event_type,payload,status,attempts,last_status_code,last_error,next_attempt_at,created_at,updated_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)`
+	_, err := q.db.ExecContext(ctx, query, params.ID, params.WebhookID, params.EventID, params.EventType, params.Payload, params.Status, params.Attempts, params.LastStatusCode, params.LastError, params.NextAttemptAt, params.CreatedAt, params.UpdatedAt)
+	return err
+}
+
+func (q *Queries) UpdateWebhookDelivery(ctx context.Context, params WebhookDelivery) error {
+	const query = `UPDATE webhook_deliveries SET status = $1, attempts = $2, last_status_code = $3, last_error = $4, next_attempt_at = $5, updated_at = $6 WHERE id = $7`
+	_, err := q.db.ExecContext(ctx, query, params.Status, params.Attempts, params.LastStatusCode, params.LastError, params.NextAttemptAt, params.UpdatedAt, params.ID)
+	return err
+}
+
+func (q *Queries) FindWebhookDeliveryByID(ctx context.Context, id uuid.UUID) (*WebhookDelivery, error) {
+	const query = `SELECT id, webhook_id, event_id, event_type, payload, status, attempts, last_status_code, last_error, next_attempt_at, created_at, updated_at
+	FROM webhook_deliveries WHERE id = $1`
+	var row WebhookDelivery
+	if err := q.db.GetContext(ctx, &row, query, id); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return &row, nil
+}
+
+func (q *Queries) FindWebhookDeliveriesByWebhookID(ctx context.Context, webhookID uuid.UUID) ([]*WebhookDelivery, error) {
+	const query = `SELECT id, webhook_id, event_id, event_type, payload, status, attempts, last_status_code, last_error, next_attempt_at, created_at, updated_at
+	FROM webhook_deliveries WHERE webhook_id = $1 ORDER BY created_at DESC LIMIT 100`
+	var rows []*WebhookDelivery
+	if err := q.db.SelectContext(ctx, &rows, query, webhookID); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return rows, nil
+}
+
+// ClaimWebhookDeliveries leases due deliveries by pushing next_attempt_at forward,
+// so concurrent workers on other replicas skip them while they are being sent.
+func (q *Queries) ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*WebhookDelivery, error) {
+	const query = `UPDATE webhook_deliveries SET next_attempt_at = $1
+	WHERE id IN (
+		SELECT id FROM webhook_deliveries
+		WHERE status = 'PENDING' AND next_attempt_at <= $2
+		ORDER BY next_attempt_at
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING id, webhook_id, event_id, event_type, payload, status, attempts, last_status_code, last_error, next_attempt_at, created_at, updated_at`
+	var rows []*WebhookDelivery
+	if err := q.db.SelectContext(ctx, &rows, query, now.Add(lease), now, limit); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/infra/repository/sql/queries"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+)
+
+// sqliteAccountRepository runs the account repository on SQLite, for local development and tests.
+// The database has a single connection, so every statement goes through the transaction carried
+// by the context (a query outside it would wait forever for the connection) and nothing runs
+// concurrently.
+type sqliteAccountRepository struct {
+	*accountRepository
+}
+
+func newSQLiteAccountRepository(db *sqlx.DB) *sqliteAccountRepository {
+	return &sqliteAccountRepository{accountRepository: newAccountRepository(db)}
+}
+
+func (r *sqliteAccountRepository) CreateAccount(ctx context.Context, account entity.Account) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "CreateAccount")
+	defer span.End()
+
+	err := r.query(ctx).SaveAccount(ctx, queries.SaveAccountParams{
+		ID:              account.ID,
+		CustomerName:    account.CustomerName,
+		DocumentNumber:  account.DocumentNumber,
+		Email:           account.Email,
+		PasswordEncoded: string(account.PasswordEncoded),
+		Status:          string(account.Status),
+		AccountType:     string(account.AccountType),
+		Role:            string(account.Role),
+		PhoneNumber:     account.PhoneNumber,
+		CreatedAt:       account.CreatedAt.UTC(),
+		UpdatedAt:       account.UpdatedAt.UTC(),
+	})
+
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return uniqueViolation(err)
+}
+
+func (r *sqliteAccountRepository) FindAccount(ctx context.Context, accountID uuid.UUID) (*entity.Account, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindAccount")
+	defer span.End()
+
+	row, err := r.query(ctx).FindAccountRecordByID(ctx, accountID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	account, err := r.withWallet(ctx, row)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (r *sqliteAccountRepository) FindAccountByIDs(ctx context.Context, ids ...uuid.UUID) (map[uuid.UUID]*entity.Account, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindAccountByIDs")
+	defer span.End()
+
+	result := make(map[uuid.UUID]*entity.Account)
+	for _, id := range ids {
+		account, err := r.FindAccount(ctx, id)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+
+		result[account.ID] = account
+	}
+
+	return result, nil
+}
+
+func (r *sqliteAccountRepository) SaveAtomicTransactions(ctx context.Context, transactions ...entity.Transaction) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "SaveAtomicTransactions")
+	defer span.End()
+
+	sequences, err := feedSequences(ctx, r.query(ctx), transactions)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	for i, transaction := range transactions {
+		err := r.query(ctx).SaveTransaction(ctx, queries.SaveTransactionParams{
+			ID:              transaction.ID,
+			CorrelatedID:    transaction.CorrelatedID,
+			AccountID:       transaction.AccountID,
+			TransactionType: string(transaction.TransactionType),
+			Timestamp:       transaction.Timestamp.UTC(),
+			Amount:          int64(transaction.Amount),
+			ParentID:        transaction.ParentID,
+			Sequence:        sequences[i],
+		})
+
+		if err != nil {
+			span.RecordError(err)
+			return fmt.Errorf("database: %w", uniqueViolation(err))
+		}
+	}
+
+	return nil
+}
+
+func (r *sqliteAccountRepository) FindAccountByEmail(ctx context.Context, email string) (*entity.Account, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindAccountByEmail")
+	defer span.End()
+
+	row, err := r.query(ctx).FindAccountRecordByEmail(ctx, email)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return r.withWallet(ctx, row)
+}
+
+func (r *sqliteAccountRepository) FindAccountByDocument(ctx context.Context, document string) (*entity.Account, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindAccountByDocument")
+	defer span.End()
+
+	row, err := r.query(ctx).FindAccountRecordByDocument(ctx, document)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return r.withWallet(ctx, row)
+}
+
+func (r *sqliteAccountRepository) UpdateAccount(ctx context.Context, account entity.Account) error {
+	account.UpdatedAt = account.UpdatedAt.UTC()
+	return r.accountRepository.UpdateAccount(ctx, account)
+}
+
+// LockAccount only checks the account exists: SQLite has no row locks, and the transaction holds
+// the single connection, so no other one runs until it ends.
+func (r *sqliteAccountRepository) LockAccount(ctx context.Context, accountID uuid.UUID) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "LockAccount")
+	defer span.End()
+
+	if _, err := r.query(ctx).FindAccountRecordByID(ctx, accountID); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *sqliteAccountRepository) withWallet(ctx context.Context, row *queries.Account) (*entity.Account, error) {
+	rows, err := r.query(ctx).FindOpenTransactions(ctx, row.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	account := entity.Account{
+		ID:              row.ID,
+		AccountType:     entity.AccountType(row.AccountType),
+		Role:            entity.Role(row.Role),
+		CustomerName:    row.CustomerName,
+		DocumentNumber:  row.DocumentNumber,
+		Email:           row.Email,
+		PasswordEncoded: entity.Password(row.PasswordEncoded),
+		PhoneNumber:     row.PhoneNumber,
+		Status:          entity.AccountStatus(row.Status),
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+	}
+
+	for _, t := range rows {
+		account.Wallet = append(account.Wallet, parseTransaction(t))
+	}
+
+	return &account, nil
+}
+
+type sqliteWebhookRepository struct {
+	*webhookRepository
+}
+
+func (r *sqliteWebhookRepository) ClaimWebhookDeliveries(ctx context.Context, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "ClaimWebhookDeliveries")
+	defer span.End()
+
+	rows, err := r.query(ctx).ClaimWebhookDeliveriesSQLite(ctx, time.Now().UTC(), lease, limit)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return parseWebhookDeliveries(rows), nil
+}
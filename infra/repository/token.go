@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/infra/repository/sql/queries"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+)
+
+type tokenRepository struct {
+	repositoryBase
+	queries *queries.Queries
+}
+
+// NewTokenRepository stores refresh tokens and revocations with queries both dialects run, binding
+// times in UTC as sqlite compares them as text.
+func NewTokenRepository(db *sqlx.DB) gateway.TokenRepository {
+	return &tokenRepository{
+		repositoryBase: repositoryBase{
+			db: db,
+		},
+		queries: queries.New(db),
+	}
+}
+
+func (r *tokenRepository) SaveRefreshToken(ctx context.Context, token entity.RefreshToken) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "SaveRefreshToken")
+	defer span.End()
+
+	err := r.query(ctx).SaveRefreshToken(ctx, queries.RefreshToken{
+		ID:        token.ID,
+		FamilyID:  token.FamilyID,
+		AccountID: token.AccountID,
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt.UTC(),
+		CreatedAt: token.CreatedAt.UTC(),
+		UsedAt:    utcNullTime(token.UsedAt),
+		RevokedAt: utcNullTime(token.RevokedAt),
+	})
+
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+func (r *tokenRepository) FindRefreshToken(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindRefreshToken")
+	defer span.End()
+
+	row, err := r.query(ctx).FindRefreshTokenByHash(ctx, tokenHash)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &entity.RefreshToken{
+		ID:        row.ID,
+		FamilyID:  row.FamilyID,
+		AccountID: row.AccountID,
+		TokenHash: row.TokenHash,
+		ExpiresAt: row.ExpiresAt,
+		CreatedAt: row.CreatedAt,
+		UsedAt:    row.UsedAt,
+		RevokedAt: row.RevokedAt,
+	}, nil
+}
+
+func (r *tokenRepository) UseRefreshToken(ctx context.Context, tokenID uuid.UUID, at time.Time) (bool, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "UseRefreshToken")
+	defer span.End()
+
+	used, err := r.query(ctx).UseRefreshToken(ctx, tokenID, at.UTC())
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return used, err
+}
+
+func (r *tokenRepository) RevokeTokenFamily(ctx context.Context, familyID uuid.UUID, at time.Time) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "RevokeTokenFamily")
+	defer span.End()
+
+	err := r.query(ctx).RevokeRefreshTokenFamily(ctx, familyID, at.UTC())
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+// RevokeTokens also prunes revocations whose tokens have all expired.
+func (r *tokenRepository) RevokeTokens(ctx context.Context, tokens ...entity.RevokedToken) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "RevokeTokens")
+	defer span.End()
+
+	for _, token := range tokens {
+		err := r.query(ctx).SaveRevokedToken(ctx, queries.RevokedToken{
+			ID:        token.ID,
+			ExpiresAt: token.ExpiresAt.UTC(),
+			RevokedAt: token.RevokedAt.UTC(),
+		})
+
+		if err != nil {
+			span.RecordError(err)
+			return err
+		}
+	}
+
+	if err := r.query(ctx).DeleteExpiredRevokedTokens(ctx, time.Now().UTC()); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *tokenRepository) IsTokenRevoked(ctx context.Context, ids ...uuid.UUID) (bool, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "IsTokenRevoked")
+	defer span.End()
+
+	if len(ids) == 0 {
+		return false, nil
+	}
+
+	count, err := r.query(ctx).CountRevokedTokens(ctx, ids, time.Now().UTC())
+	if err != nil {
+		span.RecordError(err)
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *tokenRepository) RevokeAccountSessions(ctx context.Context, accountID uuid.UUID, at time.Time) ([]uuid.UUID, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "RevokeAccountSessions")
+	defer span.End()
+
+	families, err := r.query(ctx).FindOpenTokenFamilies(ctx, accountID, at.UTC())
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if err := r.query(ctx).RevokeAccountRefreshTokens(ctx, accountID, at.UTC()); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return families, nil
+}
+
+func (r *tokenRepository) SavePasswordResetToken(ctx context.Context, token entity.PasswordResetToken) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "SavePasswordResetToken")
+	defer span.End()
+
+	err := r.query(ctx).UpsertPasswordResetToken(ctx, queries.PasswordResetToken{
+		AccountID: token.AccountID,
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt.UTC(),
+		CreatedAt: token.CreatedAt.UTC(),
+		UsedAt:    utcNullTime(token.UsedAt),
+	})
+
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+func (r *tokenRepository) FindPasswordResetToken(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindPasswordResetToken")
+	defer span.End()
+
+	row, err := r.query(ctx).FindPasswordResetTokenByHash(ctx, tokenHash)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &entity.PasswordResetToken{
+		AccountID: row.AccountID,
+		TokenHash: row.TokenHash,
+		ExpiresAt: row.ExpiresAt,
+		CreatedAt: row.CreatedAt,
+		UsedAt:    row.UsedAt,
+	}, nil
+}
+
+func (r *tokenRepository) UsePasswordResetToken(ctx context.Context, tokenHash string, at time.Time) (bool, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "UsePasswordResetToken")
+	defer span.End()
+
+	used, err := r.query(ctx).UsePasswordResetToken(ctx, tokenHash, at.UTC())
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return used, err
+}
+
+func (r *tokenRepository) query(ctx context.Context) *queries.Queries {
+	tx, ok := gateway.GetTransactionContext(ctx)
+	if !ok {
+		return r.queries
+	}
+	txSQL := tx.(*sqlx.Tx)
+	return r.queries.WithTx(txSQL)
+}
+
+func utcNullTime(t sql.NullTime) sql.NullTime {
+	if t.Valid {
+		t.Time = t.Time.UTC()
+	}
+
+	return t
+}
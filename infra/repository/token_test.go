@@ -0,0 +1,136 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTokenRepositoryContract is the behaviour every gateway.TokenRepository must share.
+func testTokenRepositoryContract(t *testing.T, accounts gateway.AccountRepository, repo gateway.TokenRepository) {
+	ctx := context.Background()
+	suffix := uuid.NewString()[:8]
+	account := entity.NewAccount(entity.Personal, "tokens", "tokens-"+suffix, "tokens-"+suffix+"@example.com", "PASSWORD", "+5511999999999")
+	require.NoError(t, accounts.CreateAccount(ctx, account))
+
+	t.Run("refresh token rotation", func(t *testing.T) {
+		familyID := uuid.New()
+		token, value := entity.NewRefreshToken(account.ID, familyID, time.Hour)
+		require.NoError(t, repo.SaveRefreshToken(ctx, token))
+
+		found, err := repo.FindRefreshToken(ctx, entity.HashToken(value))
+		require.NoError(t, err)
+		assert.Equal(t, token.ID, found.ID)
+		assert.Equal(t, familyID, found.FamilyID)
+		assert.True(t, found.Active(time.Now()))
+
+		_, err = repo.FindRefreshToken(ctx, entity.HashToken("unknown"))
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		tx, err := repo.NewTransaction(ctx)
+		require.NoError(t, err)
+		used, err := repo.UseRefreshToken(gateway.InjectTransaction(ctx, tx), token.ID, time.Now())
+		require.NoError(t, err)
+		assert.True(t, used)
+		require.NoError(t, tx.Commit())
+
+		used, err = repo.UseRefreshToken(ctx, token.ID, time.Now())
+		require.NoError(t, err)
+		assert.False(t, used)
+
+		next, _ := entity.NewRefreshToken(account.ID, familyID, time.Hour)
+		require.NoError(t, repo.SaveRefreshToken(ctx, next))
+		require.NoError(t, repo.RevokeTokenFamily(ctx, familyID, time.Now()))
+
+		revoked, err := repo.FindRefreshToken(ctx, next.TokenHash)
+		require.NoError(t, err)
+		assert.True(t, revoked.RevokedAt.Valid)
+		assert.False(t, revoked.Active(time.Now()))
+
+		used, err = repo.UseRefreshToken(ctx, next.ID, time.Now())
+		require.NoError(t, err)
+		assert.False(t, used)
+	})
+
+	t.Run("revoked tokens", func(t *testing.T) {
+		active, expired := uuid.New(), uuid.New()
+		require.NoError(t, repo.RevokeTokens(ctx,
+			entity.NewRevokedToken(active, time.Now().Add(time.Hour)),
+			entity.NewRevokedToken(expired, time.Now().Add(-time.Minute)),
+		))
+		// revoking again is a no-op
+		require.NoError(t, repo.RevokeTokens(ctx, entity.NewRevokedToken(active, time.Now().Add(time.Hour))))
+
+		revoked, err := repo.IsTokenRevoked(ctx, uuid.New(), active)
+		require.NoError(t, err)
+		assert.True(t, revoked)
+
+		revoked, err = repo.IsTokenRevoked(ctx, expired, uuid.New())
+		require.NoError(t, err)
+		assert.False(t, revoked)
+	})
+	t.Run("account sessions", func(t *testing.T) {
+		owner := entity.NewAccount(entity.Personal, "sessions", "sessions-"+suffix, "sessions-"+suffix+"@example.com", "PASSWORD", "+5511999999999")
+		require.NoError(t, accounts.CreateAccount(ctx, owner))
+
+		first, second, expired := uuid.New(), uuid.New(), uuid.New()
+		rotated, _ := entity.NewRefreshToken(owner.ID, first, time.Hour)
+		current, _ := entity.NewRefreshToken(owner.ID, first, time.Hour)
+		other, _ := entity.NewRefreshToken(owner.ID, second, time.Hour)
+		old, _ := entity.NewRefreshToken(owner.ID, expired, -time.Minute)
+		for _, token := range []entity.RefreshToken{rotated, current, other, old} {
+			require.NoError(t, repo.SaveRefreshToken(ctx, token))
+		}
+
+		sessions, err := repo.RevokeAccountSessions(ctx, owner.ID, time.Now())
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{first, second}, sessions)
+
+		found, err := repo.FindRefreshToken(ctx, current.TokenHash)
+		require.NoError(t, err)
+		assert.True(t, found.RevokedAt.Valid)
+
+		sessions, err = repo.RevokeAccountSessions(ctx, owner.ID, time.Now())
+		require.NoError(t, err)
+		assert.Empty(t, sessions)
+	})
+
+	t.Run("password reset tokens", func(t *testing.T) {
+		first, _ := entity.NewPasswordResetToken(account.ID, time.Hour)
+		require.NoError(t, repo.SavePasswordResetToken(ctx, first))
+		second, _ := entity.NewPasswordResetToken(account.ID, time.Hour)
+		require.NoError(t, repo.SavePasswordResetToken(ctx, second))
+
+		// a new token replaces the previous one
+		_, err := repo.FindPasswordResetToken(ctx, first.TokenHash)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		found, err := repo.FindPasswordResetToken(ctx, second.TokenHash)
+		require.NoError(t, err)
+		assert.Equal(t, account.ID, found.AccountID)
+		assert.True(t, found.Active(time.Now()))
+
+		tx, err := repo.NewTransaction(ctx)
+		require.NoError(t, err)
+		used, err := repo.UsePasswordResetToken(gateway.InjectTransaction(ctx, tx), second.TokenHash, time.Now())
+		require.NoError(t, err)
+		assert.True(t, used)
+		require.NoError(t, tx.Commit())
+
+		used, err = repo.UsePasswordResetToken(ctx, second.TokenHash, time.Now())
+		require.NoError(t, err)
+		assert.False(t, used)
+
+		found, err = repo.FindPasswordResetToken(ctx, second.TokenHash)
+		require.NoError(t, err)
+		assert.True(t, found.UsedAt.Valid)
+		assert.False(t, found.Active(time.Now()))
+	})
+}
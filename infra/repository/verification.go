@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/infra/repository/sql/queries"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+)
+
+type verificationRepository struct {
+	repositoryBase
+	queries *queries.Queries
+}
+
+func NewVerificationRepository(db *sqlx.DB) gateway.VerificationRepository {
+	return &verificationRepository{
+		repositoryBase: repositoryBase{
+			db: db,
+		},
+		queries: queries.New(db),
+	}
+}
+
+func (r *verificationRepository) SaveVerification(ctx context.Context, v entity.Verification) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "SaveVerification")
+	defer span.End()
+
+	err := r.query(ctx).UpsertAccountVerification(ctx, queries.AccountVerification{
+		AccountID:  v.AccountID,
+		Channel:    string(v.Channel),
+		CodeHash:   v.CodeHash,
+		Attempts:   v.Attempts,
+		ExpiresAt:  v.ExpiresAt.UTC(),
+		SentAt:     v.SentAt.UTC(),
+		VerifiedAt: utcNullTime(v.VerifiedAt),
+	})
+
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+func (r *verificationRepository) FindVerifications(ctx context.Context, accountID uuid.UUID) ([]*entity.Verification, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindVerifications")
+	defer span.End()
+
+	rows, err := r.query(ctx).FindAccountVerifications(ctx, accountID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	verifications := make([]*entity.Verification, 0, len(rows))
+	for _, row := range rows {
+		verifications = append(verifications, &entity.Verification{
+			AccountID:  row.AccountID,
+			Channel:    entity.VerificationChannel(row.Channel),
+			CodeHash:   row.CodeHash,
+			Attempts:   row.Attempts,
+			ExpiresAt:  row.ExpiresAt,
+			SentAt:     row.SentAt,
+			VerifiedAt: row.VerifiedAt,
+		})
+	}
+
+	return verifications, nil
+}
+
+func (r *verificationRepository) CountVerificationAttempt(ctx context.Context, accountID uuid.UUID, channel entity.VerificationChannel, maxAttempts int) (bool, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "CountVerificationAttempt")
+	defer span.End()
+
+	counted, err := r.query(ctx).CountAccountVerificationAttempt(ctx, accountID, string(channel), maxAttempts)
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return counted, err
+}
+
+func (r *verificationRepository) query(ctx context.Context) *queries.Queries {
+	tx, ok := gateway.GetTransactionContext(ctx)
+	if !ok {
+		return r.queries
+	}
+	txSQL := tx.(*sqlx.Tx)
+	return r.queries.WithTx(txSQL)
+}
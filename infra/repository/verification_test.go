@@ -0,0 +1,84 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testVerificationRepositoryContract is the behaviour every gateway.VerificationRepository must share.
+func testVerificationRepositoryContract(t *testing.T, accounts gateway.AccountRepository, repo gateway.VerificationRepository) {
+	ctx := context.Background()
+	suffix := uuid.NewString()[:8]
+	account := entity.NewAccount(entity.Personal, "verification", "verification-"+suffix, "verification-"+suffix+"@example.com", "PASSWORD", "+5511999999999")
+	require.NoError(t, accounts.CreateAccount(ctx, account))
+
+	t.Run("pending account", func(t *testing.T) {
+		found, err := accounts.FindAccount(ctx, account.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entity.AccountStatusPending, found.Status)
+	})
+
+	t.Run("codes per channel", func(t *testing.T) {
+		none, err := repo.FindVerifications(ctx, account.ID)
+		require.NoError(t, err)
+		assert.Empty(t, none)
+
+		email, _ := entity.NewVerification(account.ID, entity.VerificationEmail, time.Hour)
+		phone, _ := entity.NewVerification(account.ID, entity.VerificationPhone, time.Hour)
+		require.NoError(t, repo.SaveVerification(ctx, email))
+		require.NoError(t, repo.SaveVerification(ctx, phone))
+
+		// a new code replaces the one the channel had
+		resent, _ := entity.NewVerification(account.ID, entity.VerificationEmail, time.Hour)
+		resent.Attempts = 2
+		resent.VerifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		require.NoError(t, repo.SaveVerification(ctx, resent))
+
+		found, err := repo.FindVerifications(ctx, account.ID)
+		require.NoError(t, err)
+		require.Len(t, found, 2)
+		assert.Equal(t, entity.VerificationEmail, found[0].Channel)
+		assert.Equal(t, resent.CodeHash, found[0].CodeHash)
+		assert.Equal(t, 2, found[0].Attempts)
+		assert.True(t, found[0].Verified())
+		assert.WithinDuration(t, resent.ExpiresAt, found[0].ExpiresAt, time.Second)
+		assert.Equal(t, entity.VerificationPhone, found[1].Channel)
+		assert.False(t, found[1].Verified())
+	})
+
+	t.Run("attempts stop at the limit", func(t *testing.T) {
+		for range 2 {
+			counted, err := repo.CountVerificationAttempt(ctx, account.ID, entity.VerificationPhone, 2)
+			require.NoError(t, err)
+			assert.True(t, counted)
+		}
+
+		counted, err := repo.CountVerificationAttempt(ctx, account.ID, entity.VerificationPhone, 2)
+		require.NoError(t, err)
+		assert.False(t, counted)
+
+		found, err := repo.FindVerifications(ctx, account.ID)
+		require.NoError(t, err)
+		require.Len(t, found, 2)
+		assert.Equal(t, 2, found[1].Attempts)
+
+		// the email code is verified, and a channel without a code has nothing to count
+		counted, err = repo.CountVerificationAttempt(ctx, account.ID, entity.VerificationEmail, 5)
+		require.NoError(t, err)
+		assert.False(t, counted)
+
+		other := entity.NewAccount(entity.Personal, "verification", "verification-other-"+suffix, "verification-other-"+suffix+"@example.com", "PASSWORD", "+5511999999999")
+		require.NoError(t, accounts.CreateAccount(ctx, other))
+		counted, err = repo.CountVerificationAttempt(ctx, other.ID, entity.VerificationEmail, 5)
+		require.NoError(t, err)
+		assert.False(t, counted)
+	})
+}
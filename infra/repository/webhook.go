@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	"github.com/guilhermealvess/guicpay/infra/repository/sql/queries"
+	"github.com/guilhermealvess/guicpay/internal/database"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+)
+
+type webhookRepository struct {
+	repositoryBase
+	queries *queries.Queries
+}
+
+// NewWebhookRepository returns the repository matching the driver the database was opened with.
+func NewWebhookRepository(db *sqlx.DB) gateway.WebhookRepository {
+	if db.DriverName() == database.DriverSQLite {
+		return &sqliteWebhookRepository{webhookRepository: newWebhookRepository(db)}
+	}
+
+	return newWebhookRepository(db)
+}
+
+func newWebhookRepository(db *sqlx.DB) *webhookRepository {
+	return &webhookRepository{
+		repositoryBase: repositoryBase{
+			db: db,
+		},
+		queries: queries.New(db),
+	}
+}
+
+func (r *webhookRepository) CreateWebhook(ctx context.Context, webhook entity.Webhook) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "CreateWebhook")
+	defer span.End()
+
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return err
+	}
+
+	err = r.query(ctx).SaveWebhook(ctx, queries.Webhook{
+		ID:        webhook.ID,
+		AccountID: webhook.AccountID,
+		URL:       webhook.URL,
+		Secret:    webhook.Secret,
+		Events:    events,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	})
+
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+func (r *webhookRepository) FindWebhook(ctx context.Context, webhookID uuid.UUID) (*entity.Webhook, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindWebhook")
+	defer span.End()
+
+	row, err := r.query(ctx).FindWebhookByID(ctx, webhookID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return parseWebhook(row)
+}
+
+func (r *webhookRepository) FindWebhooksByAccount(ctx context.Context, accountID uuid.UUID) ([]*entity.Webhook, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindWebhooksByAccount")
+	defer span.End()
+
+	rows, err := r.query(ctx).FindWebhooksByAccountID(ctx, accountID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	webhooks := make([]*entity.Webhook, 0, len(rows))
+	for _, row := range rows {
+		webhook, err := parseWebhook(row)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, nil
+}
+
+func (r *webhookRepository) DeleteWebhook(ctx context.Context, webhookID uuid.UUID) error {
+	return r.query(ctx).DeleteWebhook(ctx, webhookID)
+}
+
+func (r *webhookRepository) SaveWebhookDeliveries(ctx context.Context, deliveries ...entity.WebhookDelivery) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "SaveWebhookDeliveries")
+	defer span.End()
+
+	for _, d := range deliveries {
+		if err := r.query(ctx).SaveWebhookDelivery(ctx, toWebhookDeliveryModel(d)); err != nil {
+			span.RecordError(err)
+			return fmt.Errorf("database: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) UpdateWebhookDelivery(ctx context.Context, delivery entity.WebhookDelivery) error {
+	return r.query(ctx).UpdateWebhookDelivery(ctx, toWebhookDeliveryModel(delivery))
+}
+
+func (r *webhookRepository) FindWebhookDelivery(ctx context.Context, deliveryID uuid.UUID) (*entity.WebhookDelivery, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindWebhookDelivery")
+	defer span.End()
+
+	row, err := r.query(ctx).FindWebhookDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	delivery := parseWebhookDelivery(row)
+	return &delivery, nil
+}
+
+func (r *webhookRepository) FindWebhookDeliveries(ctx context.Context, webhookID uuid.UUID) ([]*entity.WebhookDelivery, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "FindWebhookDeliveries")
+	defer span.End()
+
+	rows, err := r.query(ctx).FindWebhookDeliveriesByWebhookID(ctx, webhookID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return parseWebhookDeliveries(rows), nil
+}
+
+func (r *webhookRepository) ClaimWebhookDeliveries(ctx context.Context, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "ClaimWebhookDeliveries")
+	defer span.End()
+
+	rows, err := r.query(ctx).ClaimWebhookDeliveries(ctx, time.Now().UTC(), lease, limit)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return parseWebhookDeliveries(rows), nil
+}
+
+func (r *webhookRepository) query(ctx context.Context) *queries.Queries {
+	tx, ok := gateway.GetTransactionContext(ctx)
+	if !ok {
+		return r.queries
+	}
+	txSQL := tx.(*sqlx.Tx)
+	return r.queries.WithTx(txSQL)
+}
+
+func parseWebhook(row *queries.Webhook) (*entity.Webhook, error) {
+	webhook := entity.Webhook{
+		ID:        row.ID,
+		AccountID: row.AccountID,
+		URL:       row.URL,
+		Secret:    row.Secret,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+
+	if err := json.Unmarshal(row.Events, &webhook.Events); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+func parseWebhookDelivery(row *queries.WebhookDelivery) entity.WebhookDelivery {
+	return entity.WebhookDelivery{
+		ID:             row.ID,
+		WebhookID:      row.WebhookID,
+		EventID:        row.EventID,
+		EventType:      entity.WebhookEventType(row.EventType),
+		Payload:        row.Payload,
+		Status:         entity.WebhookDeliveryStatus(row.Status),
+		Attempts:       row.Attempts,
+		LastStatusCode: row.LastStatusCode,
+		LastError:      row.LastError,
+		NextAttemptAt:  row.NextAttemptAt,
+		CreatedAt:      row.CreatedAt,
+		UpdatedAt:      row.UpdatedAt,
+	}
+}
+
+func parseWebhookDeliveries(rows []*queries.WebhookDelivery) []*entity.WebhookDelivery {
+	deliveries := make([]*entity.WebhookDelivery, 0, len(rows))
+	for _, row := range rows {
+		delivery := parseWebhookDelivery(row)
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries
+}
+
+func toWebhookDeliveryModel(d entity.WebhookDelivery) queries.WebhookDelivery {
+	return queries.WebhookDelivery{
+		ID:             d.ID,
+		WebhookID:      d.WebhookID,
+		EventID:        d.EventID,
+		EventType:      string(d.EventType),
+		Payload:        d.Payload,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		NextAttemptAt:  d.NextAttemptAt,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
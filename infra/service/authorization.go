@@ -3,7 +3,6 @@ package service
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -52,11 +51,11 @@ func (s *authorizationService) Authorize(ctx context.Context, account entity.Acc
 	var data map[string]interface{}
 	if err := res.Bind(&data); err != nil {
 		span.RecordError(err)
-		return fmt.Errorf("TODO: ... %w", err)
+		return fmt.Errorf("authorization error: %w", err)
 	}
 
 	if data["message"] != true {
-		err := errors.New("TODO:")
+		err := entity.ErrAuthorizationDenied.Errorf("account %s not authorized", account.ID)
 		span.RecordError(err)
 		return err
 	}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"time"
 
 	"github.com/guilhermealvess/guicpay/domain/entity"
 	"github.com/guilhermealvess/guicpay/domain/gateway"
@@ -37,19 +38,67 @@ func (s *notificationService) Notify(ctx context.Context, account entity.Account
 	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "NotificationService.Notify")
 	defer span.End()
 
-	const endpoint = "/dispatch"
 	payload := map[string]string{
 		"message": fmt.Sprintf("%s, você recebeu uma nova transferência no valor de %s", account.CustomerName, transaction.Amount.String()),
 	}
 
+	if err := s.dispatch(ctx, payload); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *notificationService) NotifyPasswordReset(ctx context.Context, account entity.Account, token string, expiresAt time.Time) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "NotificationService.NotifyPasswordReset")
+	defer span.End()
+
+	payload := map[string]string{
+		"email": account.Email,
+		"message": fmt.Sprintf("%s, use o código %s para redefinir sua senha. Ele vale até %s e só pode ser usado uma vez.",
+			account.CustomerName, token, expiresAt.Format(time.RFC3339)),
+	}
+
+	if err := s.dispatch(ctx, payload); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *notificationService) NotifyVerificationCode(ctx context.Context, account entity.Account, channel entity.VerificationChannel, code string, expiresAt time.Time) error {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "NotificationService.NotifyVerificationCode")
+	defer span.End()
+
+	payload := map[string]string{
+		"message": fmt.Sprintf("%s, seu código de verificação é %s. Ele vale até %s.", account.CustomerName, code, expiresAt.Format(time.RFC3339)),
+	}
+
+	switch channel {
+	case entity.VerificationEmail:
+		payload["email"] = account.Email
+	case entity.VerificationPhone:
+		payload["phone_number"] = account.PhoneNumber
+	}
+
+	if err := s.dispatch(ctx, payload); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *notificationService) dispatch(ctx context.Context, payload map[string]string) error {
+	const endpoint = "/dispatch"
 	res, err := s.clientHttp.Request(ctx, http.MethodPost, endpoint, clienthttp.WithPayload(payload))
 	if err != nil {
-		span.RecordError(err)
 		return err
 	}
 
 	if err := res.Error(); err != nil {
-		span.RecordError(err)
 		return err
 	}
 
@@ -58,12 +107,10 @@ func (s *notificationService) Notify(ctx context.Context, account entity.Account
 	}
 
 	if err := res.Bind(&data); err != nil {
-		span.RecordError(err)
 		return fmt.Errorf("notification error: %w", err)
 	}
 
 	if data.Message != "Autorizado" {
-		span.RecordError(errors.New(data.Message))
 		return errors.New(data.Message)
 	}
 
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+	clienthttp "github.com/guilhermealvess/guicpay/internal/client_http"
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	HeaderWebhookSignature = "X-Guicpay-Signature"
+	HeaderWebhookTimestamp = "X-Guicpay-Timestamp"
+	HeaderWebhookEvent     = "X-Guicpay-Event"
+	HeaderWebhookDelivery  = "X-Guicpay-Delivery"
+)
+
+type webhookService struct {
+	client  clienthttp.HTTPClient
+	timeout time.Duration
+}
+
+var errWebhookTarget = errors.New("webhook target is not a public address")
+
+// NewWebhookService delivers webhooks through a client of its own: it connects only to public
+// addresses, checked after DNS resolution so a name cannot point deliveries at internal services,
+// and it does not follow redirects. insecure lifts the address check, for local development.
+func NewWebhookService(timeout time.Duration, insecure bool) gateway.WebhookService {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !insecure {
+		dialer.Control = checkWebhookTarget
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConnsPerHost: 4,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return &webhookService{
+		client:  clienthttp.NewHTTPClientWith("", client),
+		timeout: timeout,
+	}
+}
+
+// checkWebhookTarget runs on the resolved address of every connection the delivery client opens.
+func checkWebhookTarget(network, address string, _ syscall.RawConn) error {
+	addr, err := netip.ParseAddrPort(address)
+	if err != nil || !entity.WebhookTargetAllowed(addr.Addr()) {
+		return fmt.Errorf("%w: %s", errWebhookTarget, address)
+	}
+
+	return nil
+}
+
+func (s *webhookService) Send(ctx context.Context, webhook entity.Webhook, delivery entity.WebhookDelivery) (int, error) {
+	ctx, span := otel.GetTracerProvider().Tracer("my-server").Start(ctx, "WebhookService.Send")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	now := time.Now().UTC()
+	signature := webhook.Sign(now, delivery.Payload)
+	res, err := s.client.Request(ctx, http.MethodPost, webhook.URL,
+		clienthttp.WithBody(delivery.Payload),
+		clienthttp.WithUserAgent("guicpay-webhook"),
+		clienthttp.WithHeader(HeaderWebhookTimestamp, strconv.FormatInt(now.Unix(), 10)),
+		clienthttp.WithHeader(HeaderWebhookSignature, fmt.Sprintf("sha256=%s", signature)),
+		clienthttp.WithHeader(HeaderWebhookEvent, string(delivery.EventType)),
+		clienthttp.WithHeader(HeaderWebhookDelivery, delivery.ID.String()),
+	)
+	if err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	if err := res.Error(); err != nil {
+		span.RecordError(err)
+		return res.Response.StatusCode, err
+	}
+
+	if res.Response.StatusCode >= 300 {
+		err := fmt.Errorf("%d Redirect not followed", res.Response.StatusCode)
+		span.RecordError(err)
+		return res.Response.StatusCode, err
+	}
+
+	return res.Response.StatusCode, nil
+}
@@ -0,0 +1,58 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/infra/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookService(t *testing.T) {
+	var calls int
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path == "/moved" {
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	send := func(t *testing.T, insecure bool, path string) (int, error) {
+		webhook, err := entity.NewWebhook(uuid.New(), receiver.URL+path, "", []entity.WebhookEventType{entity.WebhookEventTransferReceived}, true)
+		require.NoError(t, err)
+		delivery, err := entity.NewWebhookDelivery(webhook, entity.NewWebhookEvent(entity.WebhookEventTransferReceived, nil))
+		require.NoError(t, err)
+
+		return service.NewWebhookService(time.Second, insecure).Send(context.Background(), webhook, delivery)
+	}
+
+	t.Run("private addresses are refused", func(t *testing.T) {
+		calls = 0
+		_, err := send(t, false, "/hooks")
+		assert.ErrorContains(t, err, "not a public address")
+		assert.Zero(t, calls)
+	})
+
+	t.Run("insecure targets reach private addresses", func(t *testing.T) {
+		statusCode, err := send(t, true, "/hooks")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, statusCode)
+	})
+
+	t.Run("redirects are not followed", func(t *testing.T) {
+		calls = 0
+		statusCode, err := send(t, true, "/moved")
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusFound, statusCode)
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -2,8 +2,14 @@ package grpcport
 
 import (
 	"context"
+	"net"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 )
 
 type AccountContext string
@@ -22,3 +28,50 @@ func getAccountContext(ctx context.Context) (uuid.UUID, bool) {
 func setAccountContext(ctx context.Context, accountID uuid.UUID) context.Context {
 	return context.WithValue(ctx, AccountContextKey, accountID)
 }
+
+// callerContext puts the audit actor of the call in the context, along with the account of the
+// token when the call is authenticated.
+func callerContext(ctx context.Context, payload *tokenPayload) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	requestID, _ := ctx.Value("requestID").(string)
+	actor := entity.AuditActor{
+		IP:        getClientIP(ctx),
+		UserAgent: strings.Join(md.Get("user-agent"), " "),
+		RequestID: requestID,
+	}
+
+	if payload != nil {
+		actor.AccountID, actor.Role, actor.APIKeyID = payload.AccountID, payload.Role, payload.APIKeyID
+		ctx = setAccountContext(ctx, payload.AccountID)
+	}
+
+	return usecase.InjectActor(ctx, actor)
+}
+
+// otpMetadata carries the one-time password on Auth and Transfer calls of accounts with two-factor
+// authentication enabled, as the X-OTP header does in the REST API.
+const otpMetadata = "x-otp"
+
+func getOTP(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(otpMetadata); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// getClientIP is the address of the peer; failed logins are also counted per address.
+func getClientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
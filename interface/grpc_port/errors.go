@@ -0,0 +1,55 @@
+package grpcport
+
+import (
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/internal/logger"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const errorDomain = "guicpay"
+
+// buildStatusError maps an error to the status code of its kind in the error catalog and carries
+// the catalog code as the ErrorInfo reason, so clients of both API versions can branch on it.
+func buildStatusError(err error) error {
+	e := entity.ErrorOf(err)
+	code := codeOf(e.Kind)
+
+	message := e.Detail
+	if code == codes.Internal {
+		logger.Logger.Error("Error in rpc", zap.Error(err))
+		message = e.Title
+	}
+
+	return withErrorInfo(status.New(code, message), e.Code)
+}
+
+func codeOf(kind error) codes.Code {
+	switch kind {
+	case entity.ErrNotFound:
+		return codes.NotFound
+	case entity.ErrUnprocessableEntity:
+		return codes.InvalidArgument
+	case entity.ErrConflict:
+		return codes.AlreadyExists
+	case entity.ErrUnauthorized:
+		return codes.Unauthenticated
+	case entity.ErrForbidden:
+		return codes.PermissionDenied
+	case entity.ErrTooManyRequests:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
+
+func withErrorInfo(st *status.Status, reason string) error {
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain})
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
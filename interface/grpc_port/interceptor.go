@@ -0,0 +1,124 @@
+package grpcport
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/internal/token"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// publicMethods are served without a token: they are how a client gets one.
+var publicMethods = map[string]bool{
+	"/pb.Accounts/Create":    true,
+	"/pb.Auth/Auth":          true,
+	"/pb.v2.Accounts/Create": true,
+	"/pb.v2.Auth/Auth":       true,
+	"/pb.v2.Auth/Refresh":    true,
+}
+
+// methodPermissions is the permission each authenticated RPC requires. An RPC missing from both
+// maps is denied, so a new RPC cannot be served before its permission is decided.
+var methodPermissions = map[string]entity.Permission{
+	"/pb.Accounts/Fetch":           entity.PermissionAccountRead,
+	"/pb.Accounts/Statement":       entity.PermissionAccountRead,
+	"/pb.Accounts/Balance":         entity.PermissionAccountRead,
+	"/pb.Accounts/List":            entity.PermissionAccountsList,
+	"/pb.Transactions/Deposit":     entity.PermissionDeposit,
+	"/pb.Transactions/Transfer":    entity.PermissionTransfer,
+	"/pb.Transactions/Watch":       entity.PermissionAccountRead,
+	"/pb.v2.Accounts/Fetch":        entity.PermissionAccountRead,
+	"/pb.v2.Accounts/Statement":    entity.PermissionAccountRead,
+	"/pb.v2.Accounts/Balance":      entity.PermissionAccountRead,
+	"/pb.v2.Accounts/List":         entity.PermissionAccountsList,
+	"/pb.v2.Transactions/Deposit":  entity.PermissionDeposit,
+	"/pb.v2.Transactions/Transfer": entity.PermissionTransfer,
+	"/pb.v2.Transactions/Watch":    entity.PermissionAccountRead,
+}
+
+type tokenPayload struct {
+	AccountID   uuid.UUID   `json:"account_id"`
+	AccountType string      `json:"account_type"`
+	Role        entity.Role `json:"role"`
+	Scope       string      `json:"scope"`
+	APIKeyID    uuid.UUID   `json:"api_key_id"`
+}
+
+// can mirrors the REST Payload.Can: tokens without a scope get the default role of their account type.
+func (p *tokenPayload) can(permission entity.Permission) bool {
+	if p.Scope == "" {
+		return entity.DefaultRole(entity.AccountType(p.AccountType)).Can(permission)
+	}
+
+	return permission.GrantedBy(p.Scope)
+}
+
+// AuthInterceptor validates the bearer token in the authorization metadata, the same way the
+// REST API reads the Authorization header, and puts the account in the context.
+func AuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if publicMethods[info.FullMethod] {
+		return handler(callerContext(ctx, nil), req)
+	}
+
+	payload, err := authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(callerContext(ctx, payload), req)
+}
+
+// AuthStreamInterceptor does for streaming RPCs what AuthInterceptor does for unary ones.
+func AuthStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if publicMethods[info.FullMethod] {
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: callerContext(ss.Context(), nil)})
+	}
+
+	payload, err := authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: callerContext(ss.Context(), payload)})
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authorize authenticates the caller and checks the token grants the permission of the method.
+func authorize(ctx context.Context, method string) (*tokenPayload, error) {
+	payload, err := authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	permission, ok := methodPermissions[method]
+	if !ok || !payload.can(permission) {
+		return nil, buildStatusError(entity.ErrPermissionDenied.Errorf("%s is not allowed", method))
+	}
+
+	return payload, nil
+}
+
+func authenticate(ctx context.Context) (*tokenPayload, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, buildStatusError(entity.ErrUnauthenticated.New("missing authorization metadata"))
+	}
+
+	var payload tokenPayload
+	if err := token.Middle(ctx, values[0], &payload); err != nil || payload.AccountID == uuid.Nil {
+		return nil, buildStatusError(entity.ErrUnauthenticated.New("invalid token"))
+	}
+
+	return &payload, nil
+}
@@ -0,0 +1,133 @@
+package grpcport
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/internal/ratelimit"
+	"github.com/guilhermealvess/guicpay/internal/testkit"
+	"github.com/guilhermealvess/guicpay/internal/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthInterceptor(t *testing.T) {
+	token.InitJWT("secret", time.Minute)
+	account := entity.ResumeAccount{ID: uuid.New()}
+	signed, err := token.JWT.Generate(account.JsonRawMessage())
+	require.NoError(t, err)
+
+	call := func(ctx context.Context, method string) (uuid.UUID, error) {
+		var accountID uuid.UUID
+		_, err := AuthInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			accountID, _ = getAccountContext(ctx)
+			return nil, nil
+		})
+		return accountID, err
+	}
+
+	withToken := func(v string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", v))
+	}
+
+	t.Run("public methods", func(t *testing.T) {
+		_, err := call(context.Background(), "/pb.Auth/Auth")
+		assert.NoError(t, err)
+
+		_, err = call(context.Background(), "/pb.Accounts/Create")
+		assert.NoError(t, err)
+	})
+
+	t.Run("valid token", func(t *testing.T) {
+		accountID, err := call(withToken("Bearer "+signed), "/pb.Transactions/Deposit")
+		require.NoError(t, err)
+		assert.Equal(t, account.ID, accountID)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		for _, ctx := range []context.Context{context.Background(), withToken(signed), withToken("Bearer invalid")} {
+			_, err := call(ctx, "/pb.Accounts/Fetch")
+			assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		}
+	})
+
+	t.Run("revoked session", func(t *testing.T) {
+		store := testkit.NewStore()
+		token.UseRevocationStore(store)
+		t.Cleanup(func() { token.UseRevocationStore(nil) })
+
+		sessionID := uuid.New()
+		raw, err := json.Marshal(map[string]string{"account_id": account.ID.String(), "sid": sessionID.String()})
+		require.NoError(t, err)
+		session, err := token.JWT.Generate(raw)
+		require.NoError(t, err)
+
+		_, err = call(withToken("Bearer "+session), "/pb.Accounts/Fetch")
+		require.NoError(t, err)
+
+		require.NoError(t, store.RevokeTokens(context.Background(), entity.NewRevokedToken(sessionID, time.Now().Add(time.Minute))))
+		_, err = call(withToken("Bearer "+session), "/pb.Accounts/Fetch")
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("permissions", func(t *testing.T) {
+		signedAs := func(role entity.Role) context.Context {
+			raw, err := json.Marshal(map[string]string{"account_id": account.ID.String(), "role": string(role), "scope": role.Scope()})
+			require.NoError(t, err)
+			signed, err := token.JWT.Generate(raw)
+			require.NoError(t, err)
+			return withToken("Bearer " + signed)
+		}
+
+		_, err := call(withToken("Bearer "+signed), "/pb.v2.Accounts/List")
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = call(signedAs(entity.RoleSupport), "/pb.v2.Accounts/List")
+		assert.NoError(t, err)
+
+		_, err = call(signedAs(entity.RoleSupport), "/pb.v2.Transactions/Deposit")
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = call(signedAs(entity.RoleSeller), "/pb.Transactions/Transfer")
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = call(signedAs(entity.RoleAdmin), "/pb.v2.Accounts/Unknown")
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{}, map[ratelimit.Class]ratelimit.Limit{
+		ratelimit.ClassMoney: {Burst: 1, Period: time.Minute},
+	})
+	interceptor := RateLimitInterceptor(limiter)
+
+	call := func(ctx context.Context, method string) error {
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		return err
+	}
+
+	ctx := setAccountContext(context.Background(), uuid.New())
+	require.NoError(t, call(ctx, "/pb.v2.Transactions/Transfer"))
+
+	err := call(ctx, "/pb.Transactions/Deposit")
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 2)
+	assert.Equal(t, "RATE_LIMITED", st.Details()[0].(*errdetails.ErrorInfo).Reason)
+	assert.Greater(t, st.Details()[1].(*errdetails.RetryInfo).RetryDelay.AsDuration(), 59*time.Second)
+
+	assert.NoError(t, call(ctx, "/pb.v2.Accounts/Fetch"), "reads have their own limit")
+	assert.NoError(t, call(setAccountContext(context.Background(), uuid.New()), "/pb.Transactions/Deposit"))
+}
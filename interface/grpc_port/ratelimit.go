@@ -0,0 +1,91 @@
+package grpcport
+
+import (
+	"context"
+	"math"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/internal/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// methodClasses is the rate limit class of the RPCs that sign in or move money; every other RPC is
+// a read.
+var methodClasses = map[string]ratelimit.Class{
+	"/pb.Accounts/Create":          ratelimit.ClassAuth,
+	"/pb.Auth/Auth":                ratelimit.ClassAuth,
+	"/pb.v2.Accounts/Create":       ratelimit.ClassAuth,
+	"/pb.v2.Auth/Auth":             ratelimit.ClassAuth,
+	"/pb.v2.Auth/Refresh":          ratelimit.ClassAuth,
+	"/pb.Transactions/Deposit":     ratelimit.ClassMoney,
+	"/pb.Transactions/Transfer":    ratelimit.ClassMoney,
+	"/pb.v2.Transactions/Deposit":  ratelimit.ClassMoney,
+	"/pb.v2.Transactions/Transfer": ratelimit.ClassMoney,
+}
+
+func methodClass(method string) ratelimit.Class {
+	if class, ok := methodClasses[method]; ok {
+		return class
+	}
+
+	return ratelimit.ClassRead
+}
+
+// RateLimitInterceptor runs after AuthInterceptor, so authenticated RPCs are limited per account.
+func RateLimitInterceptor(l *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := allow(ctx, l, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// RateLimitStreamInterceptor limits opening streams; messages within a stream are not counted.
+func RateLimitStreamInterceptor(l *ratelimit.Limiter) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := allow(ss.Context(), l, info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func allow(ctx context.Context, l *ratelimit.Limiter, method string) error {
+	if l == nil {
+		return nil
+	}
+
+	accountID, _ := getAccountContext(ctx)
+	class := methodClass(method)
+	result := l.Allow(ctx, class, getClientIP(ctx), accountID)
+	if result.Allowed {
+		return nil
+	}
+
+	return buildRateLimitError(class, result)
+}
+
+// buildRateLimitError answers ResourceExhausted with the RATE_LIMITED reason and, as RetryInfo, how
+// long to wait: what the Retry-After header tells REST clients.
+func buildRateLimitError(class ratelimit.Class, result ratelimit.Result) error {
+	seconds := math.Ceil(result.RetryAfter.Seconds())
+	e := entity.ErrRateLimited.Errorf("%s rate limit exceeded, retry in %.0fs", class, seconds)
+	st := status.New(codes.ResourceExhausted, e.Detail)
+
+	detailed, err := st.WithDetails(
+		&errdetails.ErrorInfo{Reason: e.Code, Domain: errorDomain},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(result.RetryAfter)},
+	)
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
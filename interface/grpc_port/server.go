@@ -9,22 +9,34 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/guilhermealvess/guicpay/internal/ratelimit"
 	"github.com/guilhermealvess/guicpay/pkg/pb"
+	pbv2 "github.com/guilhermealvess/guicpay/pkg/pb/v2"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
 
 type app struct {
-	accountService     *accountServer
-	authService        *authService
-	transactionService *transactionService
+	accountService       *accountServer
+	authService          *authService
+	transactionService   *transactionService
+	accountServiceV2     *accountServerV2
+	authServiceV2        *authServiceV2
+	transactionServiceV2 *transactionServiceV2
+	limiter              *ratelimit.Limiter
 }
 
-func NewApp(u usecase.AccountUseCase) *app {
+func NewApp(u usecase.AccountUseCase, auth usecase.AuthUseCase, l *ratelimit.Limiter) *app {
 	return &app{
 		accountService:     &accountServer{usecase: u},
-		authService:        &authService{usecase: u},
+		authService:        &authService{usecase: auth},
 		transactionService: &transactionService{usecase: u},
+
+		accountServiceV2:     &accountServerV2{usecase: u},
+		authServiceV2:        &authServiceV2{usecase: auth},
+		transactionServiceV2: &transactionServiceV2{usecase: u},
+
+		limiter: l,
 	}
 }
 
@@ -36,12 +48,23 @@ func (a *app) Start(port int) {
 	}
 
 	s := grpc.NewServer(
-		grpc.UnaryInterceptor(
+		grpc.ChainUnaryInterceptor(
 			RequestIDInterceptor,
+			AuthInterceptor,
+			RateLimitInterceptor(a.limiter),
+		),
+		grpc.ChainStreamInterceptor(
+			AuthStreamInterceptor,
+			RateLimitStreamInterceptor(a.limiter),
 		),
 	)
 
 	pb.RegisterAccountsServer(s, a.accountService)
+	pb.RegisterAuthServer(s, a.authService)
+	pb.RegisterTransactionsServer(s, a.transactionService)
+	pbv2.RegisterAccountsServer(s, a.accountServiceV2)
+	pbv2.RegisterAuthServer(s, a.authServiceV2)
+	pbv2.RegisterTransactionsServer(s, a.transactionServiceV2)
 	log.Printf("gRPC server listening at %v", lis.Addr())
 	if err := s.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)
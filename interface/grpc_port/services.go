@@ -2,9 +2,10 @@ package grpcport
 
 import (
 	"context"
-	"database/sql"
-	"errors"
+	"math"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/guilhermealvess/guicpay/domain/usecase"
 	"github.com/guilhermealvess/guicpay/pkg/pb"
 	"google.golang.org/grpc/codes"
@@ -36,7 +37,7 @@ func (s *accountServer) Create(ctx context.Context, input *pb.CreateAccountReque
 func (s *accountServer) Fetch(ctx context.Context, input *pb.FetchAccountRequest) (*pb.FetchAccountResponse, error) {
 	accountID, ok := getAccountContext(ctx)
 	if !ok {
-		return nil, status.Errorf(codes.FailedPrecondition, "TODO: ")
+		return nil, status.Errorf(codes.Unauthenticated, "missing account")
 	}
 
 	output, err := s.usecase.FindByID(ctx, accountID)
@@ -49,40 +50,149 @@ func (s *accountServer) Fetch(ctx context.Context, input *pb.FetchAccountRequest
 		AccountType:  output.AccountType,
 		CustomerName: output.CustomerName,
 		Email:        output.Email,
-		Balance:      output.Balance,
+		Balance:      output.Balance.String(),
 		Status:       output.Status,
 	}, nil
 }
 
 func (s *accountServer) List(ctx context.Context, input *pb.ListRequest) (*pb.ListResponse, error) {
-	output, err := s.usecase.FindAll(ctx)
+	output, err := s.usecase.ListAccounts(ctx, usecase.AccountListInput{
+		Types:    input.Types,
+		Statuses: input.Statuses,
+		Name:     input.Name,
+		Email:    input.Email,
+		Cursor:   input.Cursor,
+		Limit:    int(input.Limit),
+	})
 	if err != nil {
 		return nil, buildStatusError(err)
 	}
 
-	accounts := make([]*pb.FetchAccountResponse, 0)
-	for _, it := range output {
+	accounts := make([]*pb.FetchAccountResponse, 0, len(output.Accounts))
+	for _, it := range output.Accounts {
 		account := pb.FetchAccountResponse{
 			Id:           it.ID.String(),
 			AccountType:  it.AccountType,
 			CustomerName: it.CustomerName,
 			Email:        it.Email,
-			Balance:      it.Balance,
+			Balance:      it.Balance.String(),
 			Status:       it.Status,
 		}
 		accounts = append(accounts, &account)
 	}
 
-	return &pb.ListResponse{Accounts: accounts}, nil
+	return &pb.ListResponse{Accounts: accounts, Total: int64(output.Total), NextCursor: output.NextCursor}, nil
+}
+
+func (s *accountServer) Statement(ctx context.Context, input *pb.StatementRequest) (*pb.StatementResponse, error) {
+	accountID, ok := getAccountContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "missing account")
+	}
+
+	filter := usecase.StatementInput{
+		Types:     input.Types,
+		MinAmount: uint64(max(input.MinAmount, 0)),
+		MaxAmount: uint64(max(input.MaxAmount, 0)),
+		Cursor:    input.Cursor,
+		Limit:     int(input.Limit),
+	}
+
+	var err error
+	if input.From != "" {
+		if filter.From, err = time.Parse(time.RFC3339, input.From); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid from: %s", err)
+		}
+	}
+
+	if input.To != "" {
+		if filter.To, err = time.Parse(time.RFC3339, input.To); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid to: %s", err)
+		}
+	}
+
+	output, err := s.usecase.FindStatement(ctx, accountID, filter)
+	if err != nil {
+		return nil, buildStatusError(err)
+	}
+
+	lines := make([]*pb.StatementLine, 0, len(output.Transactions))
+	for _, it := range output.Transactions {
+		line := pb.StatementLine{
+			Id:              it.ID.String(),
+			TransactionType: it.TransactionType,
+			Amount:          it.Amount.String(),
+			Balance:         it.Balance.String(),
+			Timestamp:       it.Timestamp.Format(time.RFC3339Nano),
+		}
+
+		if it.CorrelatedID != nil {
+			line.CorrelatedId = it.CorrelatedID.String()
+		}
+
+		if it.Counterparty != nil {
+			line.Counterparty = &pb.Counterparty{
+				Id:           it.Counterparty.ID.String(),
+				AccountType:  it.Counterparty.AccountType,
+				CustomerName: it.Counterparty.CustomerName,
+			}
+		}
+
+		lines = append(lines, &line)
+	}
+
+	return &pb.StatementResponse{Transactions: lines, NextCursor: output.NextCursor}, nil
+}
+
+func (s *accountServer) Balance(ctx context.Context, input *pb.BalanceRequest) (*pb.BalanceResponse, error) {
+	accountID, ok := getAccountContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "missing account")
+	}
+
+	var at time.Time
+	if input.At != "" {
+		var err error
+		if at, err = time.Parse(time.RFC3339, input.At); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid at: %s", err)
+		}
+	}
+
+	output, err := s.usecase.FindBalanceAt(ctx, accountID, at)
+	if err != nil {
+		return nil, buildStatusError(err)
+	}
+
+	response := pb.BalanceResponse{
+		AccountId: output.AccountID.String(),
+		Balance:   output.Balance.String(),
+		At:        output.At.Format(time.RFC3339Nano),
+	}
+
+	if output.SnapshotID != nil {
+		response.SnapshotId = output.SnapshotID.String()
+	}
+
+	return &response, nil
 }
 
 type authService struct {
 	pb.AuthServer
-	usecase usecase.AccountUseCase
+	usecase usecase.AuthUseCase
 }
 
 func (s *authService) Auth(ctx context.Context, input *pb.AuthRequest) (*pb.AuthResponse, error) {
-	return &pb.AuthResponse{}, nil
+	output, err := s.usecase.ExecuteLogin(ctx, usecase.LoginInput{
+		Email:    input.Email,
+		Password: input.Password,
+		OTP:      getOTP(ctx),
+		ClientIP: getClientIP(ctx),
+	})
+	if err != nil {
+		return nil, buildStatusError(err)
+	}
+
+	return &pb.AuthResponse{Token: output.AccessToken}, nil
 }
 
 type transactionService struct {
@@ -91,19 +201,88 @@ type transactionService struct {
 }
 
 func (s *transactionService) Deposit(ctx context.Context, input *pb.DepositRequest) (*pb.TransactionResponse, error) {
-	return &pb.TransactionResponse{}, nil
+	accountID, ok := getAccountContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "missing account")
+	}
+
+	value, err := parseValue(input.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := s.usecase.ExecuteDeposit(ctx, accountID, value)
+	if err != nil {
+		return nil, buildStatusError(err)
+	}
+
+	return &pb.TransactionResponse{Id: output.String()}, nil
 }
 
 func (s *transactionService) Transfer(ctx context.Context, input *pb.TransferRequest) (*pb.TransactionResponse, error) {
-	return &pb.TransactionResponse{}, nil
+	accountID, ok := getAccountContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "missing account")
+	}
+
+	payeeID, err := uuid.Parse(input.PayeeId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid payee_id: %s", err)
+	}
+
+	value, err := parseValue(input.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := s.usecase.ExecuteTransfer(ctx, accountID, payeeID, value, getOTP(ctx))
+	if err != nil {
+		return nil, buildStatusError(err)
+	}
+
+	return &pb.TransactionResponse{Id: output.String()}, nil
 }
 
-func buildStatusError(err error) error {
-	switch {
-	case errors.Is(err, sql.ErrNoRows):
-		return status.Errorf(codes.NotFound, err.Error())
+func (s *transactionService) Watch(input *pb.WatchRequest, stream pb.Transactions_WatchServer) error {
+	ctx := stream.Context()
+	accountID, ok := getAccountContext(ctx)
+	if !ok {
+		return status.Errorf(codes.Unauthenticated, "missing account")
+	}
 
-	default:
-		return status.Errorf(codes.Internal, err.Error())
+	err := s.usecase.WatchTransactions(ctx, accountID, usecase.WatchInput{Cursor: input.Cursor, Replay: int(input.Replay)}, func(t *usecase.TransactionOutput) error {
+		event := pb.TransactionEvent{
+			Id:              t.ID.String(),
+			TransactionType: t.TransactionType,
+			Amount:          t.Amount.String(),
+			Timestamp:       t.Timestamp.Format(time.RFC3339Nano),
+			Cursor:          t.Cursor,
+		}
+
+		if t.CorrelatedID != nil {
+			event.CorrelatedId = t.CorrelatedID.String()
+		}
+
+		return stream.Send(&event)
+	})
+
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+
+	if err != nil {
+		return buildStatusError(err)
 	}
+
+	return nil
+}
+
+// parseValue converts an amount in reais to cents, rejecting anything under one cent.
+func parseValue(v float32) (uint64, error) {
+	cents := math.Round(float64(v) * 100)
+	if cents < 1 {
+		return 0, status.Errorf(codes.InvalidArgument, "value must be at least 0.01")
+	}
+
+	return uint64(cents), nil
 }
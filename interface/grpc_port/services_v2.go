@@ -0,0 +1,367 @@
+package grpcport
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	pbv2 "github.com/guilhermealvess/guicpay/pkg/pb/v2"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type accountServerV2 struct {
+	pbv2.AccountsServer
+	usecase usecase.AccountUseCase
+}
+
+func (s *accountServerV2) Create(ctx context.Context, input *pbv2.CreateAccountRequest) (*pbv2.CreateAccountResponse, error) {
+	if input.AccountType == pbv2.AccountType_ACCOUNT_TYPE_UNSPECIFIED {
+		return nil, invalidArgumentV2("account_type", "account type is required")
+	}
+
+	output, err := s.usecase.ExecuteNewAccount(ctx, usecase.NewAccountInput{
+		Name:           input.CustomerName,
+		Email:          input.Email,
+		Password:       input.Password,
+		Type:           enumName(input.AccountType.String(), "ACCOUNT_TYPE_"),
+		DocumentNumber: input.DocumentNumber,
+		PhoneNumber:    input.PhoneNumber,
+	})
+
+	if err != nil {
+		return nil, buildStatusError(err)
+	}
+
+	return &pbv2.CreateAccountResponse{Id: output.String()}, nil
+}
+
+func (s *accountServerV2) Fetch(ctx context.Context, input *pbv2.FetchAccountRequest) (*pbv2.Account, error) {
+	accountID, ok := getAccountContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "missing account")
+	}
+
+	output, err := s.usecase.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, buildStatusError(err)
+	}
+
+	return buildAccountV2(output), nil
+}
+
+func (s *accountServerV2) List(ctx context.Context, input *pbv2.ListAccountsRequest) (*pbv2.ListAccountsResponse, error) {
+	filter := usecase.AccountListInput{
+		Name:   input.NamePrefix,
+		Email:  input.EmailPrefix,
+		Cursor: input.Cursor,
+		Limit:  int(input.Limit),
+	}
+
+	for _, t := range input.Types {
+		filter.Types = append(filter.Types, enumName(t.String(), "ACCOUNT_TYPE_"))
+	}
+
+	for _, st := range input.Statuses {
+		filter.Statuses = append(filter.Statuses, enumName(st.String(), "ACCOUNT_STATUS_"))
+	}
+
+	output, err := s.usecase.ListAccounts(ctx, filter)
+	if err != nil {
+		return nil, buildStatusError(err)
+	}
+
+	accounts := make([]*pbv2.Account, 0, len(output.Accounts))
+	for _, it := range output.Accounts {
+		accounts = append(accounts, buildAccountV2(it))
+	}
+
+	return &pbv2.ListAccountsResponse{Accounts: accounts, Total: int64(output.Total), NextCursor: output.NextCursor}, nil
+}
+
+func (s *accountServerV2) Statement(ctx context.Context, input *pbv2.StatementRequest) (*pbv2.StatementResponse, error) {
+	accountID, ok := getAccountContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "missing account")
+	}
+
+	filter := usecase.StatementInput{
+		Cursor: input.Cursor,
+		Limit:  int(input.Limit),
+	}
+
+	if input.From != nil {
+		filter.From = input.From.AsTime()
+	}
+
+	if input.To != nil {
+		filter.To = input.To.AsTime()
+	}
+
+	for _, t := range input.Types {
+		filter.Types = append(filter.Types, enumName(t.String(), "TRANSACTION_TYPE_"))
+	}
+
+	var err error
+	if input.MinAmount != nil {
+		if filter.MinAmount, err = parseMoneyV2("min_amount", input.MinAmount, false); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.MaxAmount != nil {
+		if filter.MaxAmount, err = parseMoneyV2("max_amount", input.MaxAmount, false); err != nil {
+			return nil, err
+		}
+	}
+
+	output, err := s.usecase.FindStatement(ctx, accountID, filter)
+	if err != nil {
+		return nil, buildStatusError(err)
+	}
+
+	lines := make([]*pbv2.StatementLine, 0, len(output.Transactions))
+	for _, it := range output.Transactions {
+		line := pbv2.StatementLine{
+			Id:              it.ID.String(),
+			TransactionType: transactionTypeV2(it.TransactionType),
+			Amount:          buildMoneyV2(it.Amount),
+			Balance:         buildMoneyV2(it.Balance),
+			Timestamp:       timestamppb.New(it.Timestamp),
+		}
+
+		if it.CorrelatedID != nil {
+			line.CorrelatedId = it.CorrelatedID.String()
+		}
+
+		if it.Counterparty != nil {
+			line.Counterparty = &pbv2.Counterparty{
+				Id:           it.Counterparty.ID.String(),
+				AccountType:  accountTypeV2(it.Counterparty.AccountType),
+				CustomerName: it.Counterparty.CustomerName,
+			}
+		}
+
+		lines = append(lines, &line)
+	}
+
+	return &pbv2.StatementResponse{Transactions: lines, NextCursor: output.NextCursor}, nil
+}
+
+func (s *accountServerV2) Balance(ctx context.Context, input *pbv2.BalanceRequest) (*pbv2.BalanceResponse, error) {
+	accountID, ok := getAccountContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "missing account")
+	}
+
+	var at time.Time
+	if input.At != nil {
+		at = input.At.AsTime()
+	}
+
+	output, err := s.usecase.FindBalanceAt(ctx, accountID, at)
+	if err != nil {
+		return nil, buildStatusError(err)
+	}
+
+	response := pbv2.BalanceResponse{
+		AccountId: output.AccountID.String(),
+		Balance:   buildMoneyV2(output.Balance),
+		At:        timestamppb.New(output.At),
+	}
+
+	if output.SnapshotID != nil {
+		response.SnapshotId = output.SnapshotID.String()
+	}
+
+	return &response, nil
+}
+
+type authServiceV2 struct {
+	pbv2.AuthServer
+	usecase usecase.AuthUseCase
+}
+
+func (s *authServiceV2) Auth(ctx context.Context, input *pbv2.AuthRequest) (*pbv2.AuthResponse, error) {
+	output, err := s.usecase.ExecuteLogin(ctx, usecase.LoginInput{
+		Email:    input.Email,
+		Password: input.Password,
+		OTP:      getOTP(ctx),
+		ClientIP: getClientIP(ctx),
+	})
+	if err != nil {
+		return nil, buildStatusError(err)
+	}
+
+	return buildSessionV2(output), nil
+}
+
+func (s *authServiceV2) Refresh(ctx context.Context, input *pbv2.RefreshRequest) (*pbv2.AuthResponse, error) {
+	if input.RefreshToken == "" {
+		return nil, invalidArgumentV2("refresh_token", "refresh_token is required")
+	}
+
+	output, err := s.usecase.ExecuteRefresh(ctx, input.RefreshToken)
+	if err != nil {
+		return nil, buildStatusError(err)
+	}
+
+	return buildSessionV2(output), nil
+}
+
+func buildSessionV2(output *usecase.SessionOutput) *pbv2.AuthResponse {
+	return &pbv2.AuthResponse{
+		Token:                 output.AccessToken,
+		RefreshToken:          output.RefreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(output.RefreshTokenExpiresAt),
+	}
+}
+
+type transactionServiceV2 struct {
+	pbv2.TransactionsServer
+	usecase usecase.AccountUseCase
+}
+
+func (s *transactionServiceV2) Deposit(ctx context.Context, input *pbv2.DepositRequest) (*pbv2.TransactionResponse, error) {
+	accountID, ok := getAccountContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "missing account")
+	}
+
+	value, err := parseMoneyV2("amount", input.Amount, true)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := s.usecase.ExecuteDeposit(ctx, accountID, value)
+	if err != nil {
+		return nil, buildStatusError(err)
+	}
+
+	return &pbv2.TransactionResponse{Id: output.String()}, nil
+}
+
+func (s *transactionServiceV2) Transfer(ctx context.Context, input *pbv2.TransferRequest) (*pbv2.TransactionResponse, error) {
+	accountID, ok := getAccountContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "missing account")
+	}
+
+	payeeID, err := uuid.Parse(input.PayeeId)
+	if err != nil {
+		return nil, invalidArgumentV2("payee_id", "payee_id must be a UUID")
+	}
+
+	value, err := parseMoneyV2("amount", input.Amount, true)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := s.usecase.ExecuteTransfer(ctx, accountID, payeeID, value, getOTP(ctx))
+	if err != nil {
+		return nil, buildStatusError(err)
+	}
+
+	return &pbv2.TransactionResponse{Id: output.String()}, nil
+}
+
+func (s *transactionServiceV2) Watch(input *pbv2.WatchRequest, stream pbv2.Transactions_WatchServer) error {
+	ctx := stream.Context()
+	accountID, ok := getAccountContext(ctx)
+	if !ok {
+		return status.Errorf(codes.Unauthenticated, "missing account")
+	}
+
+	err := s.usecase.WatchTransactions(ctx, accountID, usecase.WatchInput{Cursor: input.Cursor, Replay: int(input.Replay)}, func(t *usecase.TransactionOutput) error {
+		transaction := pbv2.Transaction{
+			Id:              t.ID.String(),
+			TransactionType: transactionTypeV2(t.TransactionType),
+			Amount:          buildMoneyV2(t.Amount),
+			Timestamp:       timestamppb.New(t.Timestamp),
+			Cursor:          t.Cursor,
+		}
+
+		if t.CorrelatedID != nil {
+			transaction.CorrelatedId = t.CorrelatedID.String()
+		}
+
+		return stream.Send(&transaction)
+	})
+
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+
+	if err != nil {
+		return buildStatusError(err)
+	}
+
+	return nil
+}
+
+func buildAccountV2(output *usecase.AccountOutput) *pbv2.Account {
+	return &pbv2.Account{
+		Id:           output.ID.String(),
+		AccountType:  accountTypeV2(output.AccountType),
+		CustomerName: output.CustomerName,
+		Email:        output.Email,
+		Balance:      buildMoneyV2(output.Balance),
+		Status:       pbv2.AccountStatus(pbv2.AccountStatus_value["ACCOUNT_STATUS_"+output.Status]),
+	}
+}
+
+func buildMoneyV2(m usecase.Money) *pbv2.Money {
+	return &pbv2.Money{Units: int64(m), CurrencyCode: entity.Currency}
+}
+
+// parseMoneyV2 reads an amount in minor units; the currency may be left empty and defaults to the
+// ledger currency.
+func parseMoneyV2(field string, m *pbv2.Money, required bool) (uint64, error) {
+	if m == nil {
+		if required {
+			return 0, invalidArgumentV2(field, "amount is required")
+		}
+		return 0, nil
+	}
+
+	if m.CurrencyCode != "" && m.CurrencyCode != entity.Currency {
+		return 0, invalidArgumentV2(field+".currency_code", "unsupported currency "+m.CurrencyCode)
+	}
+
+	if m.Units < 0 || (required && m.Units == 0) {
+		return 0, invalidArgumentV2(field+".units", "amount must be positive")
+	}
+
+	return uint64(m.Units), nil
+}
+
+func accountTypeV2(t string) pbv2.AccountType {
+	return pbv2.AccountType(pbv2.AccountType_value["ACCOUNT_TYPE_"+t])
+}
+
+func transactionTypeV2(t string) pbv2.TransactionType {
+	return pbv2.TransactionType(pbv2.TransactionType_value["TRANSACTION_TYPE_"+t])
+}
+
+// enumName maps a v2 enum value back to the domain name, e.g. ACCOUNT_TYPE_SELLER to SELLER.
+func enumName(name, prefix string) string {
+	return strings.TrimPrefix(name, prefix)
+}
+
+func invalidArgumentV2(field, description string) error {
+	st := status.New(codes.InvalidArgument, description)
+	detailed, err := st.WithDetails(
+		&errdetails.ErrorInfo{Reason: "INVALID_ARGUMENT", Domain: errorDomain},
+		&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: description}}},
+	)
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
@@ -0,0 +1,67 @@
+package grpcport
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	pbv2 "github.com/guilhermealvess/guicpay/pkg/pb/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestV2Conversions(t *testing.T) {
+	t.Run("money", func(t *testing.T) {
+		value, err := parseMoneyV2("amount", &pbv2.Money{Units: 1029, CurrencyCode: "BRL"}, true)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1029), value)
+
+		money := buildMoneyV2(usecase.Money(-1029))
+		assert.Equal(t, int64(-1029), money.Units)
+		assert.Equal(t, entity.Currency, money.CurrencyCode)
+
+		for _, m := range []*pbv2.Money{nil, {Units: 0}, {Units: -1}, {Units: 100, CurrencyCode: "USD"}} {
+			_, err := parseMoneyV2("amount", m, true)
+			st := status.Convert(err)
+			assert.Equal(t, codes.InvalidArgument, st.Code())
+			require.Len(t, st.Details(), 2)
+			assert.IsType(t, &errdetails.BadRequest{}, st.Details()[1])
+		}
+	})
+
+	t.Run("enums", func(t *testing.T) {
+		assert.Equal(t, pbv2.AccountType_ACCOUNT_TYPE_SELLER, accountTypeV2(string(entity.Seller)))
+		assert.Equal(t, pbv2.TransactionType_TRANSACTION_TYPE_TRANSFER_PAYER, transactionTypeV2(string(entity.TransferPayer)))
+		assert.Equal(t, string(entity.Personal), enumName(pbv2.AccountType_ACCOUNT_TYPE_PERSONAL.String(), "ACCOUNT_TYPE_"))
+	})
+
+	t.Run("error details", func(t *testing.T) {
+		st := status.Convert(buildStatusError(errors.Join(entity.ErrNotFound, errors.New("account"))))
+		assert.Equal(t, codes.NotFound, st.Code())
+		require.Len(t, st.Details(), 1)
+		info := st.Details()[0].(*errdetails.ErrorInfo)
+		assert.Equal(t, "NOT_FOUND", info.Reason)
+		assert.Equal(t, errorDomain, info.Domain)
+
+		for err, code := range map[error]codes.Code{
+			entity.ErrInsufficientBalance.New("balance"): codes.InvalidArgument,
+			entity.ErrDuplicateEmail.New("email"):        codes.AlreadyExists,
+			entity.ErrInvalidCredentials.New("password"): codes.Unauthenticated,
+			entity.ErrAuthorizationDenied.New("denied"):  codes.PermissionDenied,
+			entity.ErrLoginLocked.New("locked"):          codes.ResourceExhausted,
+		} {
+			st := status.Convert(buildStatusError(err))
+			assert.Equal(t, code, st.Code())
+			require.Len(t, st.Details(), 1)
+			assert.Equal(t, err.(*entity.Error).Code, st.Details()[0].(*errdetails.ErrorInfo).Reason)
+		}
+
+		st = status.Convert(buildStatusError(errors.New("connection refused")))
+		assert.Equal(t, codes.Internal, st.Code())
+		assert.NotContains(t, st.Message(), "connection refused")
+	})
+}
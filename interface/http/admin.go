@@ -0,0 +1,173 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// The handlers below back the /admin routes: account management on behalf of any account, the
+// same operations cmd/admin offers operators.
+
+func (h *accountHandler) AdminFetch(c echo.Context) error {
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	output, err := h.usecase.FindByID(c.Request().Context(), accountID)
+	return buildResponse(c, err, output, http.StatusOK)
+}
+
+func (h *accountHandler) AdminChangeStatus(c echo.Context) error {
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	var data struct {
+		Status string `json:"status" validate:"required"`
+		DryRun bool   `json:"dry_run"`
+	}
+
+	if err := c.Bind(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if err := usecase.ValidateDTO(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	output, err := h.usecase.ExecuteChangeStatus(c.Request().Context(), accountID, data.Status, data.DryRun)
+	return buildResponse(c, err, output, http.StatusOK)
+}
+
+func (h *accountHandler) AdminChangeRole(c echo.Context) error {
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	var data struct {
+		Role   string `json:"role" validate:"required"`
+		DryRun bool   `json:"dry_run"`
+	}
+
+	if err := c.Bind(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if err := usecase.ValidateDTO(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	output, err := h.usecase.ExecuteChangeRole(c.Request().Context(), accountID, data.Role, data.DryRun)
+	return buildResponse(c, err, output, http.StatusOK)
+}
+
+func (h *accountHandler) AdminResetPassword(c echo.Context) error {
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	var data struct {
+		Password string `json:"password"`
+		DryRun   bool   `json:"dry_run"`
+	}
+
+	if err := c.Bind(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	output, err := h.usecase.ExecuteResetPassword(c.Request().Context(), accountID, data.Password, data.DryRun)
+	return buildResponse(c, err, output, http.StatusOK)
+}
+
+func (h *accountHandler) AdminUnlockLogin(c echo.Context) error {
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	var data struct {
+		DryRun bool `json:"dry_run"`
+	}
+
+	if err := c.Bind(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	output, err := h.usecase.ExecuteUnlockLogin(c.Request().Context(), accountID, data.DryRun)
+	return buildResponse(c, err, output, http.StatusOK)
+}
+
+func (h *accountHandler) AdminReverseTransfer(c echo.Context) error {
+	correlatedID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	var data struct {
+		DryRun bool `json:"dry_run"`
+	}
+
+	if err := c.Bind(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	output, err := h.usecase.ExecuteReverseTransfer(c.Request().Context(), correlatedID, data.DryRun)
+	return buildResponse(c, err, output, http.StatusCreated)
+}
+
+func (h *accountHandler) AdminAuditLog(c echo.Context) error {
+	input, err := bindAuditListInput(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	output, err := h.usecase.ListAuditEntries(c.Request().Context(), input)
+	return buildResponse(c, err, output, http.StatusOK)
+}
+
+func bindAuditListInput(c echo.Context) (usecase.AuditListInput, error) {
+	input := usecase.AuditListInput{
+		RequestID: c.QueryParam("request_id"),
+		Cursor:    c.QueryParam("cursor"),
+	}
+
+	// actions are lower case, unlike the other enums splitQueryList reads
+	for _, a := range splitQueryList(c.QueryParam("action")) {
+		input.Actions = append(input.Actions, strings.ToLower(a))
+	}
+
+	var err error
+	for param, target := range map[string]*uuid.UUID{"actor": &input.ActorID, "subject": &input.SubjectID} {
+		if v := c.QueryParam(param); v != "" {
+			if *target, err = uuid.Parse(v); err != nil {
+				return input, fmt.Errorf("invalid %s: %w", param, err)
+			}
+		}
+	}
+
+	if input.From, err = parseQueryTime(c.QueryParam("from"), false); err != nil {
+		return input, err
+	}
+
+	if input.To, err = parseQueryTime(c.QueryParam("to"), true); err != nil {
+		return input, err
+	}
+
+	if limit := c.QueryParam("limit"); limit != "" {
+		if input.Limit, err = strconv.Atoi(limit); err != nil {
+			return input, err
+		}
+	}
+
+	return input, nil
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type apiKeyHandler struct {
+	usecase usecase.APIKeyUseCase
+}
+
+func NewAPIKeyHandler(u usecase.APIKeyUseCase) *apiKeyHandler {
+	return &apiKeyHandler{
+		usecase: u,
+	}
+}
+
+func (h *apiKeyHandler) CreateAPIKey(c echo.Context) error {
+	v := c.Get(PayloadToken).(*Payload)
+	var input usecase.NewAPIKeyInput
+	if err := c.Bind(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if err := usecase.ValidateDTO(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	output, err := h.usecase.ExecuteNewAPIKey(c.Request().Context(), v.AccountID, input)
+	return buildResponse(c, err, output, http.StatusCreated)
+}
+
+func (h *apiKeyHandler) ListAPIKeys(c echo.Context) error {
+	v := c.Get(PayloadToken).(*Payload)
+	output, err := h.usecase.FindAPIKeys(c.Request().Context(), v.AccountID)
+	return buildResponse(c, err, output, http.StatusOK)
+}
+
+func (h *apiKeyHandler) RotateAPIKey(c echo.Context) error {
+	v := c.Get(PayloadToken).(*Payload)
+	keyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	output, err := h.usecase.ExecuteRotateAPIKey(c.Request().Context(), v.AccountID, keyID)
+	return buildResponse(c, err, output, http.StatusCreated)
+}
+
+func (h *apiKeyHandler) RevokeAPIKey(c echo.Context) error {
+	v := c.Get(PayloadToken).(*Payload)
+	keyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if err := h.usecase.ExecuteRevokeAPIKey(c.Request().Context(), v.AccountID, keyID); err != nil {
+		return buildResponse(c, err, nil, http.StatusNoContent)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
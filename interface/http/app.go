@@ -3,12 +3,17 @@ package http
 import (
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	_ "github.com/guilhermealvess/guicpay/docs"
+	"github.com/guilhermealvess/guicpay/domain/entity"
 	"github.com/guilhermealvess/guicpay/internal/properties"
+	"github.com/guilhermealvess/guicpay/internal/ratelimit"
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 	echoSwagger "github.com/swaggo/echo-swagger"
 
 	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
@@ -18,29 +23,102 @@ import (
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
-func NewServer(h *accountHandler) *echo.Echo {
+func NewServer(h *accountHandler, wh *webhookHandler, ah *authHandler, kh *apiKeyHandler, l *ratelimit.Limiter) *echo.Echo {
 	_, err := initTracer()
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	limit := rateLimit(l)
+	auth, money, read := limit(ratelimit.ClassAuth), limit(ratelimit.ClassMoney), limit(ratelimit.ClassRead)
+
+	extractor, err := ipExtractor(properties.Props.TrustedProxies)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	server := echo.New()
+	server.IPExtractor = extractor
+	server.HTTPErrorHandler = errorHandler
 	server.Use(otelecho.Middleware("my-server"))
+	// the request id is set before the actor reads it
+	server.Use(middleware.RequestID())
+	server.Use(actorMiddleware)
 	server.GET("/docs/*", echoSwagger.WrapHandler)
 	server.GET("/ping", func(c echo.Context) error {
 		return c.String(http.StatusOK, fmt.Sprintf("PONG %s", time.Now().UTC().String()))
 	})
 
-	server.POST("/accounts", h.CreateAccount)
-	server.GET("/accounts", h.List, validateTokenMiddleware)
-	server.GET("/accounts/me", h.Fetch, validateTokenMiddleware)
-	server.POST("/transactions/deposit", h.AccountDeposit, validateTokenMiddleware)
-	server.POST("/transactions/transfer", h.AccountTransfer, validateTokenMiddleware)
-	server.POST("/auth", h.Auth)
+	server.POST("/accounts", h.CreateAccount, auth)
+	server.GET("/accounts", h.List, validateTokenMiddleware, read, requirePermission(entity.PermissionAccountsList))
+	server.GET("/accounts/me", h.Fetch, validateTokenMiddleware, read, requirePermission(entity.PermissionAccountRead))
+	server.GET("/accounts/me/transactions", h.Statement, validateTokenMiddleware, read, requirePermission(entity.PermissionAccountRead))
+	server.GET("/accounts/me/statement", h.ExportStatement, validateTokenMiddleware, read, requirePermission(entity.PermissionAccountRead))
+	server.GET("/accounts/me/balance", h.Balance, validateTokenMiddleware, read, requirePermission(entity.PermissionAccountRead))
+	server.POST("/accounts/me/verification", h.SendVerification, validateTokenMiddleware, auth, requirePermission(entity.PermissionAccountRead))
+	server.POST("/accounts/me/verification/confirm", h.ConfirmVerification, validateTokenMiddleware, auth, requirePermission(entity.PermissionAccountRead))
+	server.POST("/transactions/deposit", h.AccountDeposit, validateTokenMiddleware, money, requirePermission(entity.PermissionDeposit))
+	server.POST("/transactions/transfer", h.AccountTransfer, validateTokenMiddleware, money, requirePermission(entity.PermissionTransfer))
+	server.POST("/auth", ah.Auth, auth)
+	server.POST("/auth/refresh", ah.Refresh, auth)
+	server.POST("/auth/logout", ah.Logout, validateTokenMiddleware, requireSession, auth)
+	server.POST("/auth/totp", ah.EnrollTOTP, validateTokenMiddleware, requireSession, auth)
+	server.POST("/auth/totp/confirm", ah.ConfirmTOTP, validateTokenMiddleware, requireSession, auth)
+	server.DELETE("/auth/totp", ah.DisableTOTP, validateTokenMiddleware, requireSession, auth)
+	server.POST("/auth/password/forgot", ah.ForgotPassword, auth)
+	server.POST("/auth/password/reset", ah.ResetPassword, auth)
+	server.PUT("/auth/password", ah.ChangePassword, validateTokenMiddleware, requireSession, auth)
+	server.GET("/.well-known/jwks.json", ah.JWKS, read)
+
+	manageWebhooks := requirePermission(entity.PermissionWebhookManage)
+	webhooks := server.Group("/webhooks", validateTokenMiddleware)
+	webhooks.POST("", wh.CreateWebhook, auth, manageWebhooks)
+	webhooks.GET("", wh.ListWebhooks, read, manageWebhooks)
+	webhooks.DELETE("/:id", wh.DeleteWebhook, auth, manageWebhooks)
+	webhooks.GET("/:id/deliveries", wh.ListDeliveries, read, manageWebhooks)
+	webhooks.POST("/deliveries/:id/replay", wh.ReplayDelivery, auth, manageWebhooks)
+
+	apiKeys := server.Group("/api-keys", validateTokenMiddleware, auth, requirePermission(entity.PermissionAPIKeyManage))
+	apiKeys.POST("", kh.CreateAPIKey)
+	apiKeys.GET("", kh.ListAPIKeys)
+	apiKeys.POST("/:id/rotate", kh.RotateAPIKey)
+	apiKeys.DELETE("/:id", kh.RevokeAPIKey)
+
+	admin := server.Group("/admin", validateTokenMiddleware)
+	admin.GET("/accounts", h.List, read, requirePermission(entity.PermissionAccountsList))
+	admin.GET("/accounts/:id", h.AdminFetch, read, requirePermission(entity.PermissionAccountsList))
+	admin.PATCH("/accounts/:id/status", h.AdminChangeStatus, auth, requirePermission(entity.PermissionAccountsManage))
+	admin.POST("/accounts/:id/password-reset", h.AdminResetPassword, auth, requirePermission(entity.PermissionAccountsManage))
+	admin.POST("/accounts/:id/unlock", h.AdminUnlockLogin, auth, requirePermission(entity.PermissionAccountsManage))
+	admin.PUT("/accounts/:id/role", h.AdminChangeRole, auth, requirePermission(entity.PermissionRolesManage))
+	admin.POST("/transfers/:id/reversal", h.AdminReverseTransfer, money, requirePermission(entity.PermissionTransferReverse))
+	admin.GET("/audit", h.AdminAuditLog, read, requirePermission(entity.PermissionAuditRead))
 
 	return server
 }
 
+// ipExtractor decides the client address that rate limits, login throttling and the audit log see.
+// X-Forwarded-For can be set by anyone, so it is only read when the request comes from one of the
+// trusted proxies, a comma separated list of CIDRs; without any, the address of the peer is used.
+func ipExtractor(trustedProxies string) (echo.IPExtractor, error) {
+	if strings.TrimSpace(trustedProxies) == "" {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	// only the listed ranges are trusted, not the private networks echo trusts by default
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, cidr := range strings.Split(trustedProxies, ",") {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxies: %w", err)
+		}
+
+		options = append(options, echo.TrustIPRange(network))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
 func initTracer() (*sdktrace.TracerProvider, error) {
 	exporter, err := zipkin.New(properties.Props.TraceCollectorURL)
 	if err != nil {
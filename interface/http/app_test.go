@@ -0,0 +1,160 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/guilhermealvess/guicpay/internal/ratelimit"
+	"github.com/guilhermealvess/guicpay/internal/testkit"
+	"github.com/guilhermealvess/guicpay/internal/token"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// apiKeyClaims authenticates every API key as the same key.
+type apiKeyClaims Payload
+
+func (p apiKeyClaims) AuthenticateAPIKey(context.Context, string) (json.RawMessage, error) {
+	return json.Marshal(p)
+}
+
+func TestServerAuthorization(t *testing.T) {
+	token.InitJWT("secret", time.Minute)
+
+	newServer := func(l *ratelimit.Limiter) *echo.Echo {
+		store := testkit.NewStore()
+		accounts := usecase.NewAccountUseCase(usecase.Dependencies{
+			Accounts:      store,
+			Webhooks:      store,
+			MFA:           store,
+			LoginAttempts: store,
+			Tokens:        store,
+			Verifications: store,
+			Audit:         store,
+			Authorizer:    testkit.NewAuthorizer(),
+			Notifier:      testkit.NewNotifier(),
+		})
+		// only the account handler is reached; the others are behind the checks under test
+		return NewServer(NewAccountHandler(accounts), NewWebhookHandler(nil), NewAuthHandler(nil), NewAPIKeyHandler(nil), l)
+	}
+
+	bearer := func(t *testing.T, role entity.Role) string {
+		raw, err := json.Marshal(map[string]any{"account_id": uuid.New(), "role": role, "scope": role.Scope(), "sid": uuid.New()})
+		require.NoError(t, err)
+		signed, err := token.JWT.Generate(raw)
+		require.NoError(t, err)
+		return "Bearer " + signed
+	}
+
+	serve := func(server *echo.Echo, method, path, authorization string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set(echo.HeaderAuthorization, authorization)
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	id := uuid.NewString()
+	adminRoutes := []struct {
+		method, path string
+		permission   entity.Permission
+	}{
+		{http.MethodGet, "/admin/accounts", entity.PermissionAccountsList},
+		{http.MethodGet, "/admin/accounts/" + id, entity.PermissionAccountsList},
+		{http.MethodPatch, "/admin/accounts/" + id + "/status", entity.PermissionAccountsManage},
+		{http.MethodPost, "/admin/accounts/" + id + "/password-reset", entity.PermissionAccountsManage},
+		{http.MethodPost, "/admin/accounts/" + id + "/unlock", entity.PermissionAccountsManage},
+		{http.MethodPut, "/admin/accounts/" + id + "/role", entity.PermissionRolesManage},
+		{http.MethodPost, "/admin/transfers/" + id + "/reversal", entity.PermissionTransferReverse},
+		{http.MethodGet, "/admin/audit", entity.PermissionAuditRead},
+	}
+
+	t.Run("admin routes require the permission of the route", func(t *testing.T) {
+		server := newServer(nil)
+		for _, role := range []entity.Role{entity.RoleCustomer, entity.RoleSeller, entity.RoleSupport} {
+			authorization := bearer(t, role)
+			for _, route := range adminRoutes {
+				if role.Can(route.permission) {
+					continue
+				}
+
+				assert.Equal(t, http.StatusForbidden, serve(server, route.method, route.path, authorization), "%s %s %s", role, route.method, route.path)
+			}
+		}
+
+		assert.Equal(t, http.StatusOK, serve(server, http.MethodGet, "/admin/accounts", bearer(t, entity.RoleSupport)))
+		assert.Equal(t, http.StatusOK, serve(server, http.MethodGet, "/admin/accounts", bearer(t, entity.RoleAdmin)))
+		assert.Equal(t, http.StatusUnauthorized, serve(server, http.MethodGet, "/admin/accounts", ""))
+	})
+
+	t.Run("api keys cannot manage the login", func(t *testing.T) {
+		token.UseAPIKeys(apiKeyClaims{AccountID: uuid.New(), Role: entity.RoleCustomer, Scope: entity.RoleCustomer.Scope(), APIKeyID: uuid.New()})
+		t.Cleanup(func() { token.UseAPIKeys(nil) })
+
+		server := newServer(nil)
+		for _, route := range []struct{ method, path string }{
+			{http.MethodPost, "/auth/logout"},
+			{http.MethodPost, "/auth/totp"},
+			{http.MethodPost, "/auth/totp/confirm"},
+			{http.MethodDelete, "/auth/totp"},
+			{http.MethodPut, "/auth/password"},
+		} {
+			assert.Equal(t, http.StatusForbidden, serve(server, route.method, route.path, "Bearer gpk_prefix_secret"), "%s %s", route.method, route.path)
+		}
+	})
+
+	t.Run("mutating routes use the auth rate class", func(t *testing.T) {
+		server := newServer(ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{}, map[ratelimit.Class]ratelimit.Limit{
+			ratelimit.ClassAuth: {Burst: 1, Period: time.Minute},
+		}))
+
+		for _, route := range []struct{ method, path string }{
+			{http.MethodPatch, "/admin/accounts/" + id + "/status"},
+			{http.MethodPost, "/webhooks"},
+			{http.MethodDelete, "/webhooks/" + id},
+			{http.MethodPost, "/webhooks/deliveries/" + id + "/replay"},
+		} {
+			// support has neither permission, so the requests stop after the limiter
+			authorization := bearer(t, entity.RoleSupport)
+			assert.Equal(t, http.StatusForbidden, serve(server, route.method, route.path, authorization), "%s %s", route.method, route.path)
+			assert.Equal(t, http.StatusTooManyRequests, serve(server, route.method, route.path, authorization), "%s %s", route.method, route.path)
+		}
+	})
+}
+
+func TestIPExtractor(t *testing.T) {
+	request := func(remote, forwarded string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.RemoteAddr = remote + ":4321"
+		req.Header.Set(echo.HeaderXForwardedFor, forwarded)
+		return req
+	}
+
+	t.Run("without trusted proxies the peer address is used", func(t *testing.T) {
+		extract, err := ipExtractor("")
+		require.NoError(t, err)
+		assert.Equal(t, "203.0.113.7", extract(request("203.0.113.7", "198.51.100.1")))
+		assert.Equal(t, "10.0.0.2", extract(request("10.0.0.2", "198.51.100.1")))
+	})
+
+	t.Run("forwarded addresses are read only from trusted proxies", func(t *testing.T) {
+		extract, err := ipExtractor("10.0.0.0/24, 192.0.2.0/28")
+		require.NoError(t, err)
+		assert.Equal(t, "198.51.100.1", extract(request("10.0.0.2", "198.51.100.1")))
+		assert.Equal(t, "198.51.100.1", extract(request("192.0.2.3", "198.51.100.1, 10.0.0.5")))
+		assert.Equal(t, "172.16.0.9", extract(request("172.16.0.9", "198.51.100.1")), "private networks are not trusted by default")
+	})
+
+	t.Run("invalid ranges", func(t *testing.T) {
+		_, err := ipExtractor("10.0.0.0/24,proxy")
+		assert.Error(t, err)
+	})
+}
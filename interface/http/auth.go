@@ -0,0 +1,179 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/guilhermealvess/guicpay/internal/token"
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderOTP carries the one-time password of the authenticator app, or a recovery code, on logins
+// and transfers of accounts with two-factor authentication enabled.
+const HeaderOTP = "X-OTP"
+
+type authHandler struct {
+	usecase usecase.AuthUseCase
+}
+
+func NewAuthHandler(u usecase.AuthUseCase) *authHandler {
+	return &authHandler{
+		usecase: u,
+	}
+}
+
+func (h *authHandler) Auth(c echo.Context) error {
+	var data struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+		OTP      string `json:"otp"`
+	}
+
+	if err := c.Bind(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if data.OTP == "" {
+		data.OTP = c.Request().Header.Get(HeaderOTP)
+	}
+
+	output, err := h.usecase.ExecuteLogin(c.Request().Context(), usecase.LoginInput{
+		Email:    data.Email,
+		Password: data.Password,
+		OTP:      data.OTP,
+		ClientIP: c.RealIP(),
+	})
+	return buildResponse(c, err, output, http.StatusCreated)
+}
+
+func (h *authHandler) Refresh(c echo.Context) error {
+	var data struct {
+		RefreshToken string `json:"refresh_token" validate:"required"`
+	}
+
+	if err := c.Bind(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if err := usecase.ValidateDTO(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	output, err := h.usecase.ExecuteRefresh(c.Request().Context(), data.RefreshToken)
+	return buildResponse(c, err, output, http.StatusCreated)
+}
+
+func (h *authHandler) Logout(c echo.Context) error {
+	v := c.Get(PayloadToken).(*Payload)
+	err := h.usecase.ExecuteLogout(c.Request().Context(), usecase.LogoutInput{
+		TokenID:   v.TokenID,
+		SessionID: v.SessionID,
+		ExpiresAt: time.Unix(v.ExpiresAt, 0),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *authHandler) EnrollTOTP(c echo.Context) error {
+	v := c.Get(PayloadToken).(*Payload)
+	output, err := h.usecase.ExecuteEnrollTOTP(c.Request().Context(), v.AccountID)
+	return buildResponse(c, err, output, http.StatusCreated)
+}
+
+func (h *authHandler) ConfirmTOTP(c echo.Context) error {
+	v := c.Get(PayloadToken).(*Payload)
+	var data struct {
+		Code string `json:"code" validate:"required"`
+	}
+
+	if err := c.Bind(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if err := usecase.ValidateDTO(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if err := h.usecase.ExecuteConfirmTOTP(c.Request().Context(), v.AccountID, data.Code); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *authHandler) DisableTOTP(c echo.Context) error {
+	v := c.Get(PayloadToken).(*Payload)
+	if err := h.usecase.ExecuteDisableTOTP(c.Request().Context(), v.AccountID, c.Request().Header.Get(HeaderOTP)); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ForgotPassword always answers 202, whether an account has the email or not.
+func (h *authHandler) ForgotPassword(c echo.Context) error {
+	var data struct {
+		Email string `json:"email" validate:"required"`
+	}
+
+	if err := c.Bind(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if err := usecase.ValidateDTO(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if err := h.usecase.ExecuteRequestPasswordReset(c.Request().Context(), data.Email); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+func (h *authHandler) ResetPassword(c echo.Context) error {
+	var data usecase.PasswordResetInput
+	if err := c.Bind(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if err := usecase.ValidateDTO(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if err := h.usecase.ExecutePasswordReset(c.Request().Context(), data); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *authHandler) ChangePassword(c echo.Context) error {
+	v := c.Get(PayloadToken).(*Payload)
+	var data usecase.ChangePasswordInput
+	if err := c.Bind(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if err := usecase.ValidateDTO(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	data.AccountID = v.AccountID
+	if err := h.usecase.ExecuteChangePassword(c.Request().Context(), data); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// JWKS publishes the public keys access tokens are signed with, for services verifying them offline.
+func (h *authHandler) JWKS(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+	return c.JSON(http.StatusOK, token.JWT.JWKS())
+}
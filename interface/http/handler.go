@@ -1,14 +1,19 @@
 package http
 
 import (
-	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
 	"github.com/guilhermealvess/guicpay/domain/usecase"
-	"github.com/guilhermealvess/guicpay/internal/token"
+	"github.com/guilhermealvess/guicpay/internal/export"
 	"github.com/labstack/echo/v4"
 )
 
@@ -22,26 +27,6 @@ func NewAccountHandler(u usecase.AccountUseCase) *accountHandler {
 	}
 }
 
-func (h *accountHandler) Auth(c echo.Context) error {
-	var data struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
-	}
-
-	if err := c.Bind(&data); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err)
-	}
-
-	account, err := h.usecase.ExecuteLogin(c.Request().Context(), data.Email, data.Password)
-	if err != nil {
-		return echo.NewHTTPError(http.StatusForbidden, err)
-	}
-
-	raw := account.JsonRawMessage()
-	t, err := token.JWT.Generate(raw)
-	return buildResponse(c, err, map[string]string{"token": t}, http.StatusCreated)
-}
-
 func (h *accountHandler) CreateAccount(c echo.Context) error {
 	var input usecase.NewAccountInput
 	if err := c.Bind(&input); err != nil {
@@ -63,18 +48,20 @@ func (h *accountHandler) CreateAccount(c echo.Context) error {
 func (h *accountHandler) AccountDeposit(c echo.Context) error {
 	v := c.Get(PayloadToken).(*Payload)
 	var data struct {
-		Value float64 `json:"value" validate:"required,min=0.01"`
+		Amount *usecase.AmountInput `json:"amount"`
+		Value  json.Number          `json:"value"`
 	}
 
 	if err := c.Bind(&data); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
-	if err := usecase.ValidateDTO(&data); err != nil {
+	value, err := bindAmount(data.Amount, data.Value)
+	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
-	output, err := h.usecase.ExecuteDeposit(c.Request().Context(), v.AccountID, uint64(data.Value*100))
+	output, err := h.usecase.ExecuteDeposit(c.Request().Context(), v.AccountID, value)
 	m := map[string]string{
 		"transaction_id": output.String(),
 	}
@@ -85,8 +72,9 @@ func (h *accountHandler) AccountDeposit(c echo.Context) error {
 func (h *accountHandler) AccountTransfer(c echo.Context) error {
 	v := c.Get(PayloadToken).(*Payload)
 	var data struct {
-		Value   float64   `json:"value" validate:"required,min=0.01"`
-		PayeeID uuid.UUID `json:"payee" validate:"required"`
+		Amount  *usecase.AmountInput `json:"amount"`
+		Value   json.Number          `json:"value"`
+		PayeeID uuid.UUID            `json:"payee" validate:"required"`
 	}
 
 	if err := c.Bind(&data); err != nil {
@@ -97,36 +85,232 @@ func (h *accountHandler) AccountTransfer(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
-	output, err := h.usecase.ExecuteTransfer(c.Request().Context(), v.AccountID, data.PayeeID, uint64(data.Value*100))
+	value, err := bindAmount(data.Amount, data.Value)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	output, err := h.usecase.ExecuteTransfer(c.Request().Context(), v.AccountID, data.PayeeID, value, c.Request().Header.Get(HeaderOTP))
 	m := map[string]string{
 		"transaction_id": output.String(),
 	}
 	return buildResponse(c, err, m, http.StatusOK)
 }
 
+// bindAmount reads the amount of a deposit or transfer from "amount", or from "value", the amount
+// in reais as a JSON number sent by older clients, which is read from its digits and not as a float.
+func bindAmount(amount *usecase.AmountInput, value json.Number) (uint64, error) {
+	var m entity.Money
+	switch {
+	case amount != nil:
+		m = entity.Money(*amount)
+
+	case value != "":
+		var err error
+		if m, err = entity.ParseMoney(value.String()); err != nil {
+			return 0, err
+		}
+
+	default:
+		return 0, errors.New("amount is required")
+	}
+
+	if m < entity.Cent {
+		return 0, errors.New("amount must be at least 0.01")
+	}
+
+	return uint64(m), nil
+}
+
 func (h *accountHandler) Fetch(c echo.Context) error {
 	v := c.Get(PayloadToken).(*Payload)
 	output, err := h.usecase.FindByID(c.Request().Context(), v.AccountID)
 	return buildResponse(c, err, output, http.StatusOK)
 }
 
-func (h *accountHandler) List(c echo.Context) error {
-	output, err := h.usecase.FindAll(c.Request().Context())
+func (h *accountHandler) Statement(c echo.Context) error {
+	v := c.Get(PayloadToken).(*Payload)
+	input, err := bindStatementInput(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	output, err := h.usecase.FindStatement(c.Request().Context(), v.AccountID, input)
 	return buildResponse(c, err, output, http.StatusOK)
 }
 
-func buildResponse(c echo.Context, err error, data any, statusCode int) error {
-	switch {
-	case err == nil:
-		return c.JSON(statusCode, data)
+func (h *accountHandler) Balance(c echo.Context) error {
+	v := c.Get(PayloadToken).(*Payload)
+	at, err := parseQueryTime(c.QueryParam("at"), true)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	output, err := h.usecase.FindBalanceAt(c.Request().Context(), v.AccountID, at)
+	return buildResponse(c, err, output, http.StatusOK)
+}
 
-	case errors.Is(err, sql.ErrNoRows):
+func (h *accountHandler) ExportStatement(c echo.Context) error {
+	v := c.Get(PayloadToken).(*Payload)
+	var input usecase.StatementExportInput
+	var err error
+
+	if input.From, err = parseQueryTime(c.QueryParam("from"), false); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
 
-	case errors.Is(err, entity.ErrUnprocessableEntity):
-		return echo.NewHTTPError(http.StatusUnprocessableEntity, err)
+	if input.To, err = parseQueryTime(c.QueryParam("to"), true); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
 
+	res := c.Response()
+	format := strings.ToLower(c.QueryParam("format"))
+	var encoder gateway.StatementEncoder
+	switch format {
+	case "ofx":
+		res.Header().Set(echo.HeaderContentType, export.ContentTypeOFX)
+		encoder = export.NewOFXEncoder(res)
+	case "csv", "":
+		format = "csv"
+		res.Header().Set(echo.HeaderContentType, export.ContentTypeCSV)
+		encoder = export.NewCSVEncoder(res)
 	default:
-		return echo.NewHTTPError(http.StatusInternalServerError, err)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("unsupported format %q", format))
+	}
+
+	filename := fmt.Sprintf("statement-%s-%s.%s", v.AccountID, time.Now().UTC().Format("20060102"), format)
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	err = h.usecase.ExportStatement(c.Request().Context(), v.AccountID, input, encoder)
+	if err != nil && !res.Committed {
+		res.Header().Del(echo.HeaderContentDisposition)
+		return buildResponse(c, err, nil, http.StatusOK)
+	}
+
+	if err != nil {
+		// headers are already sent, the client sees a truncated file
+		c.Logger().Error(err)
 	}
+
+	return nil
+}
+
+func bindStatementInput(c echo.Context) (usecase.StatementInput, error) {
+	var input usecase.StatementInput
+	var err error
+
+	if input.From, err = parseQueryTime(c.QueryParam("from"), false); err != nil {
+		return input, err
+	}
+
+	if input.To, err = parseQueryTime(c.QueryParam("to"), true); err != nil {
+		return input, err
+	}
+
+	input.Types = splitQueryList(c.QueryParam("type"))
+
+	if input.MinAmount, err = parseQueryAmount(c.QueryParam("min_amount")); err != nil {
+		return input, err
+	}
+
+	if input.MaxAmount, err = parseQueryAmount(c.QueryParam("max_amount")); err != nil {
+		return input, err
+	}
+
+	if limit := c.QueryParam("limit"); limit != "" {
+		if input.Limit, err = strconv.Atoi(limit); err != nil {
+			return input, err
+		}
+	}
+
+	input.Cursor = c.QueryParam("cursor")
+	return input, nil
+}
+
+// splitQueryList reads a comma separated list of enum values, such as "deposit,transfer_payer".
+func splitQueryList(v string) []string {
+	if v == "" {
+		return nil
+	}
+
+	values := make([]string, 0)
+	for _, it := range strings.Split(v, ",") {
+		values = append(values, strings.ToUpper(strings.TrimSpace(it)))
+	}
+
+	return values
+}
+
+// parseQueryTime accepts RFC 3339 timestamps or plain dates; a plain date used as an
+// upper bound covers the whole day.
+func parseQueryTime(v string, endOfDay bool) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", v)
+	}
+
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	return t, nil
+}
+
+func parseQueryAmount(v string) (uint64, error) {
+	if v == "" {
+		return 0, nil
+	}
+
+	m, err := entity.ParseMoney(v)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(m), nil
+}
+
+func (h *accountHandler) List(c echo.Context) error {
+	input, err := bindAccountListInput(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	output, err := h.usecase.ListAccounts(c.Request().Context(), input)
+	return buildResponse(c, err, output, http.StatusOK)
+}
+
+func bindAccountListInput(c echo.Context) (usecase.AccountListInput, error) {
+	input := usecase.AccountListInput{
+		Types:    splitQueryList(c.QueryParam("type")),
+		Statuses: splitQueryList(c.QueryParam("status")),
+		Name:     c.QueryParam("name"),
+		Email:    c.QueryParam("email"),
+		Cursor:   c.QueryParam("cursor"),
+	}
+
+	if limit := c.QueryParam("limit"); limit != "" {
+		var err error
+		if input.Limit, err = strconv.Atoi(limit); err != nil {
+			return input, err
+		}
+	}
+
+	return input, nil
+}
+
+// buildResponse writes data on success; errors are left to errorHandler, which renders them as problem+json.
+func buildResponse(c echo.Context, err error, data any, statusCode int) error {
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(statusCode, data)
 }
@@ -1,9 +1,9 @@
 package http
 
 import (
-	"net/http"
-
 	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
 	"github.com/guilhermealvess/guicpay/internal/token"
 	"github.com/labstack/echo/v4"
 )
@@ -11,36 +11,82 @@ import (
 const PayloadToken = "account"
 
 type Payload struct {
-	AccountID   uuid.UUID `json:"account_id"`
-	AccountType string    `json:"account_type"`
+	AccountID   uuid.UUID   `json:"account_id"`
+	AccountType string      `json:"account_type"`
+	Role        entity.Role `json:"role"`
+	Scope       string      `json:"scope"`
+	TokenID     uuid.UUID   `json:"jti"`
+	SessionID   uuid.UUID   `json:"sid"`
+	ExpiresAt   int64       `json:"exp"`
+	// APIKeyID is set when the request authenticated with an API key instead of a login.
+	APIKeyID uuid.UUID `json:"api_key_id"`
 }
 
 func validateTokenMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		tokenString := c.Request().Header.Get("Authorization")
-		if tokenString == "" {
-			return echo.NewHTTPError(http.StatusForbidden)
-		}
-
 		var payload Payload
-		if err := token.Middle(tokenString, &payload); err != nil {
-			return echo.NewHTTPError(http.StatusForbidden)
+		if err := token.Middle(c.Request().Context(), c.Request().Header.Get("Authorization"), &payload); err != nil {
+			return entity.ErrUnauthenticated.Wrap(err)
 		}
 
 		c.Set(PayloadToken, &payload)
+
+		actor := usecase.GetActor(c.Request().Context())
+		actor.AccountID, actor.Role, actor.APIKeyID = payload.AccountID, payload.Role, payload.APIKeyID
+		c.SetRequest(c.Request().WithContext(usecase.InjectActor(c.Request().Context(), actor)))
+		return next(c)
+	}
+}
+
+// actorMiddleware starts the audit actor of the request with where it came from; the account is
+// added by validateTokenMiddleware once the token is checked.
+func actorMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		actor := entity.AuditActor{
+			IP:        c.RealIP(),
+			UserAgent: req.UserAgent(),
+			RequestID: c.Response().Header().Get(echo.HeaderXRequestID),
+		}
+
+		c.SetRequest(req.WithContext(usecase.InjectActor(req.Context(), actor)))
 		return next(c)
 	}
 }
 
-func validatePermission(accountType string) echo.MiddlewareFunc {
+// Can reports whether the token grants the permission. Tokens issued before roles existed carry no
+// scope and get the default role of their account type.
+func (p *Payload) Can(permission entity.Permission) bool {
+	if p.Scope == "" {
+		return entity.DefaultRole(entity.AccountType(p.AccountType)).Can(permission)
+	}
+
+	return permission.GrantedBy(p.Scope)
+}
+
+// requirePermission runs after validateTokenMiddleware and rejects tokens without the permission.
+func requirePermission(permission entity.Permission) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			payload := c.Get(PayloadToken).(*Payload)
-			if payload.AccountType != accountType {
-				return echo.NewHTTPError(http.StatusForbidden)
+			if !payload.Can(permission) {
+				return entity.ErrPermissionDenied.Errorf("requires the %s permission", permission)
 			}
 
 			return next(c)
 		}
 	}
 }
+
+// requireSession runs after validateTokenMiddleware and rejects API keys on routes that manage the
+// login itself: the password, two-factor authentication and sessions.
+func requireSession(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		payload := c.Get(PayloadToken).(*Payload)
+		if payload.APIKeyID != uuid.Nil {
+			return entity.ErrPermissionDenied.New("api keys cannot manage the login of the account")
+		}
+
+		return next(c)
+	}
+}
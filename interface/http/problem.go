@@ -0,0 +1,124 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/internal/logger"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+const mimeProblemJSON = "application/problem+json"
+
+// problem is an RFC 7807 problem details document; Code is the stable code of the error catalog.
+type problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+}
+
+// errorHandler renders every error leaving a handler or middleware as problem+json.
+func errorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	p := buildProblem(err)
+	p.Instance = c.Request().URL.Path
+	if p.Status == http.StatusInternalServerError {
+		logger.Logger.Error("Error in request", zap.Error(err), zap.String("path", p.Instance))
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, mimeProblemJSON)
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(p.Status)
+	} else {
+		err = c.JSON(p.Status, p)
+	}
+
+	if err != nil {
+		logger.Logger.Error("Error writing problem", zap.Error(err))
+	}
+}
+
+func buildProblem(err error) problem {
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		return buildHTTPProblem(he)
+	}
+
+	e := entity.ErrorOf(err)
+	p := problem{
+		Type:   problemType(e.Code),
+		Title:  e.Title,
+		Status: statusOf(e.Kind),
+		Detail: e.Detail,
+		Code:   e.Code,
+	}
+
+	if p.Status == http.StatusInternalServerError {
+		// internal details stay in the logs
+		p.Detail = ""
+	}
+
+	return p
+}
+
+// buildHTTPProblem describes errors raised by echo or by handlers before reaching the use cases,
+// such as malformed bodies, keeping the status they were raised with.
+func buildHTTPProblem(he *echo.HTTPError) problem {
+	if err, ok := he.Message.(error); ok {
+		var e *entity.Error
+		if errors.As(err, &e) {
+			return buildProblem(e)
+		}
+	}
+
+	code := strings.ToUpper(strings.ReplaceAll(http.StatusText(he.Code), " ", "_"))
+	if he.Code == http.StatusBadRequest {
+		code = entity.ErrInvalidArgument.Code
+	}
+
+	p := problem{
+		Type:   problemType(code),
+		Title:  http.StatusText(he.Code),
+		Status: he.Code,
+		Code:   code,
+	}
+
+	if he.Message != nil && he.Code != http.StatusInternalServerError {
+		p.Detail = fmt.Sprint(he.Message)
+	}
+
+	return p
+}
+
+func problemType(code string) string {
+	return "urn:guicpay:error:" + code
+}
+
+func statusOf(kind error) int {
+	switch kind {
+	case entity.ErrNotFound:
+		return http.StatusNotFound
+	case entity.ErrUnprocessableEntity:
+		return http.StatusUnprocessableEntity
+	case entity.ErrConflict:
+		return http.StatusConflict
+	case entity.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case entity.ErrForbidden:
+		return http.StatusForbidden
+	case entity.ErrTooManyRequests:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/internal/ratelimit"
+	"github.com/labstack/echo/v4"
+)
+
+const HeaderRetryAfter = "Retry-After"
+
+// rateLimit builds the middleware limiting a route class. On authenticated routes it runs after
+// validateTokenMiddleware so the class limit applies to the account; a nil limiter does not limit.
+func rateLimit(l *ratelimit.Limiter) func(ratelimit.Class) echo.MiddlewareFunc {
+	return func(class ratelimit.Class) echo.MiddlewareFunc {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			if l == nil {
+				return next
+			}
+
+			return func(c echo.Context) error {
+				accountID := uuid.Nil
+				if payload, ok := c.Get(PayloadToken).(*Payload); ok {
+					accountID = payload.AccountID
+				}
+
+				result := l.Allow(c.Request().Context(), class, c.RealIP(), accountID)
+				if !result.Allowed {
+					seconds := int(math.Ceil(result.RetryAfter.Seconds()))
+					c.Response().Header().Set(HeaderRetryAfter, strconv.Itoa(seconds))
+					return entity.ErrRateLimited.Errorf("%s rate limit exceeded, retry in %ds", class, seconds)
+				}
+
+				return next(c)
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// SendVerification sends new codes to the channel in the body, or to every unverified channel.
+func (h *accountHandler) SendVerification(c echo.Context) error {
+	v := c.Get(PayloadToken).(*Payload)
+	var data struct {
+		Channel string `json:"channel"`
+	}
+
+	if err := c.Bind(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if err := h.usecase.ExecuteSendVerification(c.Request().Context(), v.AccountID, data.Channel); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+func (h *accountHandler) ConfirmVerification(c echo.Context) error {
+	v := c.Get(PayloadToken).(*Payload)
+	var data usecase.VerifyInput
+	if err := c.Bind(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if err := usecase.ValidateDTO(&data); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	output, err := h.usecase.ExecuteVerify(c.Request().Context(), v.AccountID, data)
+	return buildResponse(c, err, output, http.StatusOK)
+}
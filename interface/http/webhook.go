@@ -0,0 +1,76 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type webhookHandler struct {
+	usecase usecase.WebhookUseCase
+}
+
+func NewWebhookHandler(u usecase.WebhookUseCase) *webhookHandler {
+	return &webhookHandler{
+		usecase: u,
+	}
+}
+
+func (h *webhookHandler) CreateWebhook(c echo.Context) error {
+	v := c.Get(PayloadToken).(*Payload)
+	var input usecase.NewWebhookInput
+	if err := c.Bind(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if err := usecase.ValidateDTO(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	output, err := h.usecase.ExecuteNewWebhook(c.Request().Context(), v.AccountID, input)
+	return buildResponse(c, err, output, http.StatusCreated)
+}
+
+func (h *webhookHandler) ListWebhooks(c echo.Context) error {
+	v := c.Get(PayloadToken).(*Payload)
+	output, err := h.usecase.FindWebhooks(c.Request().Context(), v.AccountID)
+	return buildResponse(c, err, output, http.StatusOK)
+}
+
+func (h *webhookHandler) DeleteWebhook(c echo.Context) error {
+	v := c.Get(PayloadToken).(*Payload)
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if err := h.usecase.ExecuteDeleteWebhook(c.Request().Context(), v.AccountID, webhookID); err != nil {
+		return buildResponse(c, err, nil, http.StatusNoContent)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *webhookHandler) ListDeliveries(c echo.Context) error {
+	v := c.Get(PayloadToken).(*Payload)
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	output, err := h.usecase.FindWebhookDeliveries(c.Request().Context(), v.AccountID, webhookID)
+	return buildResponse(c, err, output, http.StatusOK)
+}
+
+func (h *webhookHandler) ReplayDelivery(c echo.Context) error {
+	v := c.Get(PayloadToken).(*Payload)
+	deliveryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	output, err := h.usecase.ExecuteReplayDelivery(c.Request().Context(), v.AccountID, deliveryID)
+	return buildResponse(c, err, output, http.StatusCreated)
+}
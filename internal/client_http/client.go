@@ -24,6 +24,20 @@ func WithPayload(v any) RequestOptions {
 	}
 }
 
+func WithBody(body []byte) RequestOptions {
+	return func(req *http.Request) {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+		req.Header.Set("Content-Type", "application/json")
+	}
+}
+
+func WithHeader(key, value string) RequestOptions {
+	return func(req *http.Request) {
+		req.Header.Set(key, value)
+	}
+}
+
 func WithToken(token string) RequestOptions {
 	return func(req *http.Request) {
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
@@ -44,11 +58,19 @@ func WithQueryParams(params url.Values) RequestOptions {
 
 type httpClient struct {
 	baseURL string
+	client  *http.Client
 }
 
 func NewHTTPClient(baseURL string) HTTPClient {
+	return NewHTTPClientWith(baseURL, http.DefaultClient)
+}
+
+// NewHTTPClientWith sends the requests through client, for callers that need their own transport or
+// redirect policy.
+func NewHTTPClientWith(baseURL string, client *http.Client) HTTPClient {
 	return &httpClient{
 		baseURL: baseURL,
+		client:  client,
 	}
 }
 
@@ -62,7 +84,7 @@ func (c *httpClient) Request(ctx context.Context, method string, url string, opt
 		opt(req)
 	}
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
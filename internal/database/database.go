@@ -6,12 +6,18 @@ import (
 
 	"github.com/guilhermealvess/guicpay/internal/properties"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq" // driver postgres
+	_ "github.com/lib/pq"           // driver postgres
+	_ "github.com/mattn/go-sqlite3" // driver sqlite
 	"github.com/uptrace/opentelemetry-go-extra/otelsqlx"
 )
 
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite3"
+)
+
 func NewConnectionDB() *sqlx.DB {
-	db, err := otelsqlx.Open("postgres", properties.Props.DatabaseURL)
+	db, err := Open(properties.Props.DatabaseDriver, properties.Props.DatabaseURL)
 	if err != nil {
 		log.Panicf("failed to connect on database: %v", err)
 	}
@@ -20,10 +26,29 @@ func NewConnectionDB() *sqlx.DB {
 		log.Fatal(err)
 	}
 
+	return db
+}
+
+// Open connects to the database with the given driver. SQLite allows a single writer, so it gets a
+// single long lived connection: transactions are serialized and in-memory databases survive.
+func Open(driver, url string) (*sqlx.DB, error) {
+	db, err := otelsqlx.Open(driver, url)
+	if err != nil {
+		return nil, err
+	}
+
+	if driver == DriverSQLite {
+		db.SetMaxOpenConns(1)
+		db.SetMaxIdleConns(1)
+		db.SetConnMaxIdleTime(0)
+		db.SetConnMaxLifetime(0)
+		return db, nil
+	}
+
 	db.SetMaxOpenConns(properties.Props.DatabaseMaxConn)
 	db.SetMaxIdleConns(properties.Props.DatabaseMaxIdle)
 	db.SetConnMaxIdleTime(time.Minute * 10)
 	db.SetConnMaxLifetime(time.Minute * 10)
 
-	return db
+	return db, nil
 }
@@ -0,0 +1,64 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+)
+
+const ContentTypeCSV = "text/csv; charset=utf-8"
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func NewCSVEncoder(w io.Writer) gateway.StatementEncoder {
+	return &csvEncoder{w: csv.NewWriter(w)}
+}
+
+func (e *csvEncoder) Begin(account entity.Account, from, to time.Time) error {
+	return e.w.Write([]string{
+		"transaction_id",
+		"timestamp",
+		"transaction_type",
+		"amount",
+		"balance",
+		"currency",
+		"correlated_id",
+		"counterparty_id",
+		"counterparty_name",
+	})
+}
+
+func (e *csvEncoder) Line(line *entity.StatementLine) error {
+	record := []string{
+		line.ID.String(),
+		line.Timestamp.UTC().Format(time.RFC3339),
+		string(line.TransactionType),
+		line.Amount.Decimal(),
+		line.Balance.Decimal(),
+		"BRL",
+		"",
+		"",
+		"",
+	}
+
+	if line.CorrelatedID.Valid {
+		record[6] = line.CorrelatedID.UUID.String()
+	}
+
+	if line.Counterparty != nil {
+		record[7] = line.Counterparty.AccountID.String()
+		record[8] = line.Counterparty.CustomerName
+	}
+
+	return e.w.Write(record)
+}
+
+func (e *csvEncoder) End(ledgerBalance entity.Money, asOf time.Time) error {
+	e.w.Flush()
+	return e.w.Error()
+}
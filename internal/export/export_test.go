@@ -0,0 +1,84 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func statementLines() []*entity.StatementLine {
+	now := time.Date(2024, 3, 31, 23, 59, 0, 0, time.UTC)
+	correlatedID := uuid.New()
+	return []*entity.StatementLine{
+		{
+			Transaction: entity.Transaction{ID: uuid.New(), TransactionType: entity.Deposit, Timestamp: now.Add(-time.Hour), Amount: 10*entity.Real + 29*entity.Cent},
+			Balance:     10*entity.Real + 29*entity.Cent,
+		},
+		{
+			Transaction:  entity.Transaction{ID: uuid.New(), TransactionType: entity.TransferPayer, Timestamp: now, Amount: -5 * entity.Real, CorrelatedID: uuid.NullUUID{UUID: correlatedID, Valid: true}},
+			Balance:      5*entity.Real + 29*entity.Cent,
+			Counterparty: &entity.Counterparty{AccountID: uuid.New(), CustomerName: "Loja & Cia <Ltda>"},
+		},
+	}
+}
+
+func TestExport(t *testing.T) {
+	account := entity.NewAccount(entity.Personal, "Fulano", "123", "f@example.com", "pass", "+55")
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		encoder := NewCSVEncoder(&buf)
+		assert.NoError(t, encoder.Begin(account, from, to))
+		for _, line := range statementLines() {
+			assert.NoError(t, encoder.Line(line))
+		}
+		assert.NoError(t, encoder.End(5*entity.Real+29*entity.Cent, to))
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, records, 3)
+		assert.Equal(t, "10.29", records[1][3])
+		assert.Equal(t, "-5.00", records[2][3])
+		assert.Equal(t, "5.29", records[2][4])
+		assert.Equal(t, "Loja & Cia <Ltda>", records[2][8])
+	})
+
+	t.Run("ofx", func(t *testing.T) {
+		var buf bytes.Buffer
+		lines := statementLines()
+		encoder := NewOFXEncoder(&buf)
+		assert.NoError(t, encoder.Begin(account, from, to))
+		for _, line := range lines {
+			assert.NoError(t, encoder.Line(line))
+		}
+		assert.NoError(t, encoder.End(5*entity.Real+29*entity.Cent, to))
+
+		var doc struct {
+			Transactions []struct {
+				Type   string `xml:"TRNTYPE"`
+				Amount string `xml:"TRNAMT"`
+				FITID  string `xml:"FITID"`
+				Name   string `xml:"NAME"`
+			} `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>BANKTRANLIST>STMTTRN"`
+			AccountID string `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>BANKACCTFROM>ACCTID"`
+			Ledger    string `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>LEDGERBAL>BALAMT"`
+		}
+		assert.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+		assert.Equal(t, account.ID.String(), doc.AccountID)
+		assert.Equal(t, "5.29", doc.Ledger)
+		assert.Len(t, doc.Transactions, 2)
+		assert.Equal(t, "DEP", doc.Transactions[0].Type)
+		assert.Equal(t, "10.29", doc.Transactions[0].Amount)
+		assert.Equal(t, lines[1].ID.String(), doc.Transactions[1].FITID)
+		assert.Equal(t, "-5.00", doc.Transactions[1].Amount)
+		assert.Equal(t, "Loja & Cia <Ltda>", doc.Transactions[1].Name)
+	})
+}
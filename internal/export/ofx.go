@@ -0,0 +1,109 @@
+package export
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+)
+
+const (
+	ContentTypeOFX = "application/x-ofx"
+
+	ofxBankID     = "GUICPAY"
+	ofxNameLength = 32
+)
+
+type ofxEncoder struct {
+	w *bufio.Writer
+}
+
+// NewOFXEncoder writes an OFX 2.2 bank statement (XML flavour).
+func NewOFXEncoder(w io.Writer) gateway.StatementEncoder {
+	return &ofxEncoder{w: bufio.NewWriter(w)}
+}
+
+func (e *ofxEncoder) Begin(account entity.Account, from, to time.Time) error {
+	now := time.Now().UTC()
+	fmt.Fprint(e.w, `<?xml version="1.0" encoding="UTF-8" standalone="no"?>`+"\n")
+	fmt.Fprint(e.w, `<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>`+"\n")
+	fmt.Fprint(e.w, "<OFX>\n")
+	fmt.Fprint(e.w, "<SIGNONMSGSRSV1><SONRS>")
+	fmt.Fprint(e.w, "<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>")
+	fmt.Fprintf(e.w, "<DTSERVER>%s</DTSERVER><LANGUAGE>POR</LANGUAGE>", ofxTime(now))
+	fmt.Fprint(e.w, "</SONRS></SIGNONMSGSRSV1>\n")
+	fmt.Fprint(e.w, "<BANKMSGSRSV1><STMTTRNRS>\n")
+	fmt.Fprintf(e.w, "<TRNUID>%s</TRNUID>", uuid.NewString())
+	fmt.Fprint(e.w, "<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n")
+	fmt.Fprint(e.w, "<STMTRS><CURDEF>BRL</CURDEF>\n")
+	fmt.Fprintf(e.w, "<BANKACCTFROM><BANKID>%s</BANKID><ACCTID>%s</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>\n", ofxBankID, account.ID)
+	fmt.Fprintf(e.w, "<BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>\n", ofxTime(from), ofxTime(to))
+	return e.w.Flush()
+}
+
+func (e *ofxEncoder) Line(line *entity.StatementLine) error {
+	fmt.Fprint(e.w, "<STMTTRN>")
+	fmt.Fprintf(e.w, "<TRNTYPE>%s</TRNTYPE>", ofxTransactionType(line))
+	fmt.Fprintf(e.w, "<DTPOSTED>%s</DTPOSTED>", ofxTime(line.Timestamp))
+	fmt.Fprintf(e.w, "<TRNAMT>%s</TRNAMT>", line.Amount.Decimal())
+	fmt.Fprintf(e.w, "<FITID>%s</FITID>", line.ID)
+	if line.Counterparty != nil {
+		fmt.Fprintf(e.w, "<NAME>%s</NAME>", ofxEscape(truncate(line.Counterparty.CustomerName, ofxNameLength)))
+	}
+	fmt.Fprintf(e.w, "<MEMO>%s</MEMO>", line.TransactionType)
+	fmt.Fprint(e.w, "</STMTTRN>\n")
+
+	if e.w.Buffered() > 4096 {
+		return e.w.Flush()
+	}
+
+	return nil
+}
+
+func (e *ofxEncoder) End(ledgerBalance entity.Money, asOf time.Time) error {
+	fmt.Fprint(e.w, "</BANKTRANLIST>\n")
+	fmt.Fprintf(e.w, "<LEDGERBAL><BALAMT>%s</BALAMT><DTASOF>%s</DTASOF></LEDGERBAL>\n", ledgerBalance.Decimal(), ofxTime(asOf))
+	fmt.Fprint(e.w, "</STMTRS></STMTTRNRS></BANKMSGSRSV1>\n")
+	fmt.Fprint(e.w, "</OFX>\n")
+	return e.w.Flush()
+}
+
+func ofxTransactionType(line *entity.StatementLine) string {
+	switch line.TransactionType {
+	case entity.Deposit:
+		return "DEP"
+	case entity.TransferPayer, entity.TransferPayee, entity.ReversalPayer, entity.ReversalPayee:
+		return "XFER"
+	}
+
+	if line.Amount < 0 {
+		return "DEBIT"
+	}
+
+	return "CREDIT"
+}
+
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405.000") + "[0:GMT]"
+}
+
+func ofxEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+
+	return string(r[:n])
+}
@@ -12,15 +12,55 @@ import (
 type props struct {
 	TransactionTimeout     time.Duration `env:"TRANSACTION_TIMEOUT,default=5s"`
 	RestPort               int           `env:"APP_PORT,default=3000"`
+	TrustedProxies         string        `env:"TRUSTED_PROXIES"`
 	GRPCPort               int           `env:"GRPC_PORT,default=5000"`
 	RedisAddress           string        `env:"REDIS_ADDRESS"`
 	AuthorizeServiceURL    string        `env:"AUTHORIZE_SERVICE_URL"`
 	NotificationServiceURL string        `env:"NOTIFICATION_SERVICE_URL"`
 	SnapshotWalletSize     int           `env:"SNAPSHOT_WALLET_SIZE,default=10"`
+	DatabaseDriver         string        `env:"DATABASE_DRIVER,default=postgres"`
 	DatabaseURL            string        `env:"DATABASE_URL"`
+	MigrateOnStart         bool          `env:"MIGRATE_ON_START,default=false"`
+	WatchPollInterval      time.Duration `env:"WATCH_POLL_INTERVAL,default=10s"`
 	JWT                    struct {
-		Secret string        `env:"JWT_SECRET"`
-		Expire time.Duration `env:"JWT_TOKEN_EXPIRE,default=3600s"`
+		Secret        string        `env:"JWT_SECRET"`
+		Expire        time.Duration `env:"JWT_TOKEN_EXPIRE,default=900s"`
+		RefreshExpire time.Duration `env:"JWT_REFRESH_TOKEN_EXPIRE,default=720h"`
+		KeysFile      string        `env:"JWT_KEYS_FILE"`
+		KeyOverlap    time.Duration `env:"JWT_KEY_OVERLAP,default=1h"`
+	}
+	MFA struct {
+		Issuer       string `env:"MFA_ISSUER,default=GuicPay"`
+		StepUpAmount uint64 `env:"MFA_STEP_UP_AMOUNT,default=100000"`
+	}
+	Login struct {
+		FreeAttempts   int           `env:"LOGIN_FREE_ATTEMPTS,default=3"`
+		LockAfter      int           `env:"LOGIN_LOCK_AFTER,default=10"`
+		IPFreeAttempts int           `env:"LOGIN_IP_FREE_ATTEMPTS,default=20"`
+		IPLockAfter    int           `env:"LOGIN_IP_LOCK_AFTER,default=100"`
+		BaseDelay      time.Duration `env:"LOGIN_BASE_DELAY,default=1s"`
+		Lockout        time.Duration `env:"LOGIN_LOCKOUT,default=15m"`
+		ResetExpire    time.Duration `env:"PASSWORD_RESET_EXPIRE,default=30m"`
+	}
+	Verification struct {
+		CodeExpire     time.Duration `env:"VERIFICATION_CODE_EXPIRE,default=15m"`
+		MaxAttempts    int           `env:"VERIFICATION_MAX_ATTEMPTS,default=5"`
+		ResendCooldown time.Duration `env:"VERIFICATION_RESEND_COOLDOWN,default=1m"`
+	}
+	RateLimit struct {
+		Store  string `env:"RATE_LIMIT_STORE,default=memory"`
+		Client string `env:"RATE_LIMIT_CLIENT,default=600/1m"`
+		Auth   string `env:"RATE_LIMIT_AUTH,default=20/1m"`
+		Money  string `env:"RATE_LIMIT_MONEY,default=60/1m"`
+		Read   string `env:"RATE_LIMIT_READ,default=300/1m"`
+	}
+	Webhook struct {
+		MaxAttempts     int           `env:"WEBHOOK_MAX_ATTEMPTS,default=8"`
+		Backoff         time.Duration `env:"WEBHOOK_BACKOFF,default=30s"`
+		Timeout         time.Duration `env:"WEBHOOK_TIMEOUT,default=10s"`
+		PollInterval    time.Duration `env:"WEBHOOK_POLL_INTERVAL,default=5s"`
+		BatchSize       int           `env:"WEBHOOK_BATCH_SIZE,default=50"`
+		InsecureTargets bool          `env:"WEBHOOK_INSECURE_TARGETS,default=false"`
 	}
 	TraceCollectorURL string `env:"TRACE_COLLECTOR_URL"`
 	DatabaseMaxConn   int    `env:"DATABASE_MAX_CONN,default=15"`
@@ -35,4 +75,28 @@ func init() {
 	}
 
 	token.InitJWT(Props.JWT.Secret, Props.JWT.Expire)
+	if Props.JWT.KeysFile != "" {
+		loadJWTKeys()
+	}
+}
+
+// loadJWTKeys switches token signing to the rotation schedule in JWT_KEYS_FILE. A JWT_SECRET still
+// set joins the schedule as its first key, so tokens it signed verify until the overlap after the
+// first scheduled key activates.
+func loadJWTKeys() {
+	var legacy []*token.Key
+	if Props.JWT.Secret != "" {
+		key, err := token.NewKey("", token.AlgorithmHS256, []byte(Props.JWT.Secret), time.Time{})
+		if err != nil {
+			log.Fatal(err)
+		}
+		legacy = append(legacy, key)
+	}
+
+	keys, err := token.LoadKeys(Props.JWT.KeysFile, Props.JWT.KeyOverlap, legacy...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	token.UseKeys(keys)
 }
@@ -0,0 +1,140 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/internal/logger"
+	"go.uber.org/zap"
+)
+
+// Class groups the routes that share a limit.
+type Class string
+
+const (
+	ClassAuth  Class = "auth"
+	ClassMoney Class = "money"
+	ClassRead  Class = "read"
+)
+
+// Limit is a token bucket holding up to Burst requests, refilled at Burst requests per Period. The
+// zero Limit does not limit.
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// ParseLimit reads a limit written as "requests/period", such as "60/1m"; an empty value or "0"
+// disables it.
+func ParseLimit(v string) (Limit, error) {
+	v = strings.TrimSpace(v)
+	if v == "" || v == "0" {
+		return Limit{}, nil
+	}
+
+	requests, period, ok := strings.Cut(v, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("rate limit %q must be requests/period", v)
+	}
+
+	burst, err := strconv.Atoi(requests)
+	if err != nil || burst < 0 {
+		return Limit{}, fmt.Errorf("rate limit %q: invalid request count", v)
+	}
+
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q: invalid period", v)
+	}
+
+	return Limit{Burst: burst, Period: d}, nil
+}
+
+func (l Limit) Enabled() bool {
+	return l.Burst > 0 && l.Period > 0
+}
+
+// Rate is the refill rate in requests per second.
+func (l Limit) Rate() float64 {
+	return float64(l.Burst) / l.Period.Seconds()
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Burst, l.Period)
+}
+
+// Result is the outcome of taking a request from a bucket; a denied request may be retried after
+// RetryAfter.
+type Result struct {
+	Allowed    bool
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets; Take refills the bucket of key up to now and takes one request from it
+// when it holds one.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// ResultOf builds the result of an attempt that left the bucket with tokens; a denied attempt
+// waits for the bucket to refill a whole request.
+func ResultOf(allowed bool, tokens float64, limit Limit) Result {
+	if allowed {
+		return Result{Allowed: true}
+	}
+
+	wait := (1 - tokens) / limit.Rate()
+	return Result{RetryAfter: time.Duration(math.Ceil(wait * float64(time.Second)))}
+}
+
+// Limiter applies a limit per client address to every request and a limit per class to the
+// account making it, or to the address for anonymous requests.
+type Limiter struct {
+	store   Store
+	client  Limit
+	classes map[Class]Limit
+}
+
+func NewLimiter(store Store, client Limit, classes map[Class]Limit) *Limiter {
+	return &Limiter{store: store, client: client, classes: classes}
+}
+
+// Allow takes a request from the address bucket and then from the class bucket. A store failure is
+// logged and lets the request through: rate limiting protects the service and should not take it
+// down with the store.
+func (l *Limiter) Allow(ctx context.Context, class Class, ip string, accountID uuid.UUID) Result {
+	subject := "ip:" + ip
+	if accountID != uuid.Nil {
+		subject = "account:" + accountID.String()
+	}
+
+	now := time.Now()
+	for _, b := range []struct {
+		key   string
+		limit Limit
+	}{
+		{key: "client:ip:" + ip, limit: l.client},
+		{key: string(class) + ":" + subject, limit: l.classes[class]},
+	} {
+		if !b.limit.Enabled() {
+			continue
+		}
+
+		result, err := l.store.Take(ctx, b.key, b.limit, now)
+		if err != nil {
+			logger.Logger.Error("Error in rate limit store", zap.String("key", b.key), zap.Error(err))
+			continue
+		}
+
+		if !result.Allowed {
+			return result
+		}
+	}
+
+	return Result{Allowed: true}
+}
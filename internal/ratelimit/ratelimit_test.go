@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Limit, time.Time) (Result, error) {
+	return Result{}, errors.New("store down")
+}
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("parse limit", func(t *testing.T) {
+		limit, err := ParseLimit("60/1m")
+		require.NoError(t, err)
+		assert.Equal(t, Limit{Burst: 60, Period: time.Minute}, limit)
+		assert.Equal(t, 1.0, limit.Rate())
+
+		for _, v := range []string{"", "0"} {
+			limit, err := ParseLimit(v)
+			require.NoError(t, err)
+			assert.False(t, limit.Enabled())
+		}
+
+		for _, v := range []string{"60", "x/1m", "60/x", "60/0s", "-1/1m"} {
+			_, err := ParseLimit(v)
+			assert.Error(t, err, v)
+		}
+	})
+
+	t.Run("class limit per account, or per address when anonymous", func(t *testing.T) {
+		l := NewLimiter(NewMemoryStore(), Limit{}, map[Class]Limit{ClassMoney: {Burst: 1, Period: time.Minute}})
+		account, other := uuid.New(), uuid.New()
+
+		assert.True(t, l.Allow(ctx, ClassMoney, "203.0.113.1", account).Allowed)
+		denied := l.Allow(ctx, ClassMoney, "203.0.113.2", account)
+		assert.False(t, denied.Allowed)
+		assert.Greater(t, denied.RetryAfter, 59*time.Second)
+
+		assert.True(t, l.Allow(ctx, ClassMoney, "203.0.113.1", other).Allowed)
+		assert.True(t, l.Allow(ctx, ClassMoney, "203.0.113.1", uuid.Nil).Allowed)
+		assert.False(t, l.Allow(ctx, ClassMoney, "203.0.113.1", uuid.Nil).Allowed)
+		assert.True(t, l.Allow(ctx, ClassRead, "203.0.113.1", uuid.Nil).Allowed, "classes without a limit are not limited")
+	})
+
+	t.Run("client limit spans classes", func(t *testing.T) {
+		l := NewLimiter(NewMemoryStore(), Limit{Burst: 2, Period: time.Minute}, nil)
+
+		assert.True(t, l.Allow(ctx, ClassAuth, "203.0.113.1", uuid.Nil).Allowed)
+		assert.True(t, l.Allow(ctx, ClassRead, "203.0.113.1", uuid.New()).Allowed)
+		assert.False(t, l.Allow(ctx, ClassMoney, "203.0.113.1", uuid.New()).Allowed)
+		assert.True(t, l.Allow(ctx, ClassMoney, "203.0.113.2", uuid.New()).Allowed)
+	})
+
+	t.Run("store failures let requests through", func(t *testing.T) {
+		l := NewLimiter(failingStore{}, Limit{Burst: 1, Period: time.Minute}, nil)
+		for range 3 {
+			assert.True(t, l.Allow(ctx, ClassRead, "203.0.113.1", uuid.Nil).Allowed)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+)
+
+func (s *Store) CreateAPIKey(ctx context.Context, key entity.APIKey) error {
+	key.Scopes = slices.Clone(key.Scopes)
+	return s.write(ctx, func(st *state) error {
+		if _, ok := st.accounts[key.AccountID]; !ok {
+			return fmt.Errorf("%w: account %s does not exist", ErrConstraint, key.AccountID)
+		}
+
+		if _, ok := st.apiKeys[key.ID]; ok {
+			return fmt.Errorf("%w: api key %s already exists", ErrConstraint, key.ID)
+		}
+
+		for _, k := range st.apiKeys {
+			if k.Prefix == key.Prefix {
+				return fmt.Errorf("%w: api key prefix %s already exists", ErrConstraint, key.Prefix)
+			}
+		}
+
+		st.apiKeys[key.ID] = key
+		return nil
+	})
+}
+
+func (s *Store) FindAPIKey(ctx context.Context, keyID uuid.UUID) (*entity.APIKey, error) {
+	var key *entity.APIKey
+	err := s.read(ctx, func(st *state) error {
+		k, ok := st.apiKeys[keyID]
+		if !ok {
+			return notFound("api key")
+		}
+
+		k.Scopes = slices.Clone(k.Scopes)
+		key = &k
+		return nil
+	})
+
+	return key, err
+}
+
+func (s *Store) FindAPIKeyByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error) {
+	var key *entity.APIKey
+	err := s.read(ctx, func(st *state) error {
+		for _, k := range st.apiKeys {
+			if k.Prefix == prefix {
+				k.Scopes = slices.Clone(k.Scopes)
+				key = &k
+				return nil
+			}
+		}
+
+		return notFound("api key")
+	})
+
+	return key, err
+}
+
+func (s *Store) FindAPIKeysByAccount(ctx context.Context, accountID uuid.UUID) ([]*entity.APIKey, error) {
+	var keys []*entity.APIKey
+	err := s.read(ctx, func(st *state) error {
+		keys = nil
+		for _, k := range st.apiKeys {
+			if k.AccountID == accountID {
+				k.Scopes = slices.Clone(k.Scopes)
+				keys = append(keys, &k)
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.After(keys[j].CreatedAt)
+		}
+		return keys[i].ID.String() < keys[j].ID.String()
+	})
+	return keys, err
+}
+
+func (s *Store) RevokeAPIKey(ctx context.Context, keyID uuid.UUID, at time.Time) error {
+	return s.write(ctx, func(st *state) error {
+		k, ok := st.apiKeys[keyID]
+		if !ok || k.RevokedAt.Valid {
+			return nil
+		}
+
+		k.RevokedAt.Time, k.RevokedAt.Valid = at.UTC(), true
+		st.apiKeys[keyID] = k
+		return nil
+	})
+}
+
+func (s *Store) TouchAPIKey(ctx context.Context, keyID uuid.UUID, at time.Time) error {
+	return s.write(ctx, func(st *state) error {
+		k, ok := st.apiKeys[keyID]
+		if !ok {
+			return nil
+		}
+
+		k.LastUsedAt.Time, k.LastUsedAt.Valid = at.UTC(), true
+		st.apiKeys[keyID] = k
+		return nil
+	})
+}
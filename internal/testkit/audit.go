@@ -0,0 +1,62 @@
+package testkit
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+)
+
+func (s *Store) SaveAuditEntry(ctx context.Context, entry entity.AuditEntry) error {
+	entry.Before, entry.After = slices.Clone(entry.Before), slices.Clone(entry.After)
+	return s.write(ctx, func(st *state) error {
+		st.audit = append(st.audit, entry)
+		return nil
+	})
+}
+
+func (s *Store) FindAuditEntries(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, error) {
+	var entries []*entity.AuditEntry
+	err := s.read(ctx, func(st *state) error {
+		entries = nil
+		for _, e := range st.audit {
+			if auditMatches(e, filter) {
+				entries = append(entries, &e)
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].CreatedAt.After(entries[j].CreatedAt)
+		}
+		return entries[i].ID.String() > entries[j].ID.String()
+	})
+
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+
+	return entries, err
+}
+
+func auditMatches(e entity.AuditEntry, f entity.AuditFilter) bool {
+	switch {
+	case len(f.Actions) > 0 && !slices.Contains(f.Actions, e.Action),
+		f.ActorID != uuid.Nil && e.Actor.AccountID != f.ActorID,
+		f.SubjectID != uuid.Nil && e.SubjectID != f.SubjectID,
+		f.RequestID != "" && e.Actor.RequestID != f.RequestID,
+		!f.From.IsZero() && e.CreatedAt.Before(f.From),
+		!f.To.IsZero() && e.CreatedAt.After(f.To):
+		return false
+	}
+
+	if c := f.Cursor; c != nil {
+		return e.CreatedAt.Before(c.CreatedAt) || (e.CreatedAt.Equal(c.CreatedAt) && e.ID.String() < c.ID.String())
+	}
+
+	return true
+}
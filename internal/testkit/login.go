@@ -0,0 +1,57 @@
+package testkit
+
+import (
+	"context"
+	"time"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+)
+
+func (s *Store) RecordLoginFailure(ctx context.Context, key string, at time.Time, window time.Duration) (*entity.LoginAttempts, error) {
+	var attempts entity.LoginAttempts
+	err := s.write(ctx, func(st *state) error {
+		since := at.Add(-window)
+		for k, a := range st.logins {
+			if a.LastFailureAt.Before(since) {
+				delete(st.logins, k)
+			}
+		}
+
+		a := st.logins[key]
+		a.Key = key
+		a.Failures++
+		a.LastFailureAt = at.UTC()
+		st.logins[key] = a
+		attempts = a
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &attempts, nil
+}
+
+func (s *Store) FindLoginAttempts(ctx context.Context, keys ...string) ([]*entity.LoginAttempts, error) {
+	var attempts []*entity.LoginAttempts
+	err := s.read(ctx, func(st *state) error {
+		for _, key := range keys {
+			if a, ok := st.logins[key]; ok {
+				attempts = append(attempts, &a)
+			}
+		}
+		return nil
+	})
+
+	return attempts, err
+}
+
+func (s *Store) DeleteLoginAttempts(ctx context.Context, keys ...string) error {
+	return s.write(ctx, func(st *state) error {
+		for _, key := range keys {
+			delete(st.logins, key)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,71 @@
+package testkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+)
+
+var errTOTPUsed = errors.New("testkit: totp code already used")
+
+func (s *Store) SaveTOTP(ctx context.Context, totp entity.TOTP) error {
+	totp.RecoveryCodes = slices.Clone(totp.RecoveryCodes)
+	return s.write(ctx, func(st *state) error {
+		if _, ok := st.accounts[totp.AccountID]; !ok {
+			return fmt.Errorf("%w: account %s does not exist", ErrConstraint, totp.AccountID)
+		}
+
+		st.totp[totp.AccountID] = totp
+		return nil
+	})
+}
+
+// UseTOTP fails the commit of its transaction when another one used a code of the credential
+// meanwhile, as the conditional update of the database would.
+func (s *Store) UseTOTP(ctx context.Context, totp entity.TOTP, lastStep int64, updatedAt time.Time) (bool, error) {
+	totp.RecoveryCodes = slices.Clone(totp.RecoveryCodes)
+	err := s.write(ctx, func(st *state) error {
+		t, ok := st.totp[totp.AccountID]
+		if !ok || t.LastStep != lastStep || !t.UpdatedAt.Equal(updatedAt) {
+			return errTOTPUsed
+		}
+
+		t.RecoveryCodes, t.LastStep, t.UpdatedAt = totp.RecoveryCodes, totp.LastStep, totp.UpdatedAt
+		st.totp[totp.AccountID] = t
+		return nil
+	})
+
+	if errors.Is(err, errTOTPUsed) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (s *Store) FindTOTP(ctx context.Context, accountID uuid.UUID) (*entity.TOTP, error) {
+	var totp *entity.TOTP
+	err := s.read(ctx, func(st *state) error {
+		t, ok := st.totp[accountID]
+		if !ok {
+			return notFound("totp")
+		}
+
+		t.RecoveryCodes = slices.Clone(t.RecoveryCodes)
+		totp = &t
+		return nil
+	})
+
+	return totp, err
+}
+
+func (s *Store) DeleteTOTP(ctx context.Context, accountID uuid.UUID) error {
+	return s.write(ctx, func(st *state) error {
+		delete(st.totp, accountID)
+		return nil
+	})
+}
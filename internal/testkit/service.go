@@ -0,0 +1,171 @@
+package testkit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+)
+
+var (
+	_ gateway.AuthorizationService = (*Authorizer)(nil)
+	_ gateway.NotificationService  = (*Notifier)(nil)
+	_ gateway.WebhookService       = (*WebhookSender)(nil)
+)
+
+// Authorizer is a fake gateway.AuthorizationService: it approves every account unless a failure
+// was set, and records the accounts it was asked about.
+type Authorizer struct {
+	mu    sync.Mutex
+	err   error
+	calls []entity.Account
+}
+
+func NewAuthorizer() *Authorizer {
+	return &Authorizer{}
+}
+
+// Fail makes every following call return err; Fail(nil) approves again.
+func (a *Authorizer) Fail(err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.err = err
+}
+
+func (a *Authorizer) Authorize(ctx context.Context, account entity.Account) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.calls = append(a.calls, account)
+	return a.err
+}
+
+func (a *Authorizer) Calls() []entity.Account {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]entity.Account(nil), a.calls...)
+}
+
+type Notification struct {
+	Account     entity.Account
+	Transaction entity.Transaction
+}
+
+// PasswordResetNotification is a password reset token sent to an account.
+type PasswordResetNotification struct {
+	Account   entity.Account
+	Token     string
+	ExpiresAt time.Time
+}
+
+// VerificationNotification is a verification code sent to a channel of an account.
+type VerificationNotification struct {
+	Account   entity.Account
+	Channel   entity.VerificationChannel
+	Code      string
+	ExpiresAt time.Time
+}
+
+// Notifier is a fake gateway.NotificationService recording every notification sent.
+type Notifier struct {
+	mu     sync.Mutex
+	err    error
+	calls  []Notification
+	resets []PasswordResetNotification
+	codes  []VerificationNotification
+}
+
+func NewNotifier() *Notifier {
+	return &Notifier{}
+}
+
+// Fail makes every following call return err; Fail(nil) delivers again.
+func (n *Notifier) Fail(err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.err = err
+}
+
+func (n *Notifier) Notify(ctx context.Context, account entity.Account, transaction entity.Transaction) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.calls = append(n.calls, Notification{Account: account, Transaction: transaction})
+	return n.err
+}
+
+func (n *Notifier) Calls() []Notification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]Notification(nil), n.calls...)
+}
+
+func (n *Notifier) NotifyPasswordReset(ctx context.Context, account entity.Account, token string, expiresAt time.Time) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.resets = append(n.resets, PasswordResetNotification{Account: account, Token: token, ExpiresAt: expiresAt})
+	return n.err
+}
+
+func (n *Notifier) Resets() []PasswordResetNotification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]PasswordResetNotification(nil), n.resets...)
+}
+
+func (n *Notifier) NotifyVerificationCode(ctx context.Context, account entity.Account, channel entity.VerificationChannel, code string, expiresAt time.Time) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.codes = append(n.codes, VerificationNotification{Account: account, Channel: channel, Code: code, ExpiresAt: expiresAt})
+	return n.err
+}
+
+func (n *Notifier) Codes() []VerificationNotification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]VerificationNotification(nil), n.codes...)
+}
+
+// WebhookSend is a delivery attempt made to a webhook.
+type WebhookSend struct {
+	Webhook  entity.Webhook
+	Delivery entity.WebhookDelivery
+}
+
+// WebhookSender is a fake gateway.WebhookService: receivers answer 200 unless a failure was set,
+// and every attempt is recorded.
+type WebhookSender struct {
+	mu         sync.Mutex
+	statusCode int
+	err        error
+	sends      []WebhookSend
+}
+
+func NewWebhookSender() *WebhookSender {
+	return &WebhookSender{}
+}
+
+// Fail makes every following attempt answer statusCode and return err; Fail(0, nil) delivers again.
+func (w *WebhookSender) Fail(statusCode int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.statusCode = statusCode
+	w.err = err
+}
+
+func (w *WebhookSender) Send(ctx context.Context, webhook entity.Webhook, delivery entity.WebhookDelivery) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.sends = append(w.sends, WebhookSend{Webhook: webhook, Delivery: delivery})
+	if w.err != nil {
+		return w.statusCode, w.err
+	}
+
+	return 200, nil
+}
+
+func (w *WebhookSender) Sends() []WebhookSend {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]WebhookSend(nil), w.sends...)
+}
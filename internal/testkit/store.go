@@ -0,0 +1,805 @@
+package testkit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+	"github.com/guilhermealvess/guicpay/domain/gateway"
+)
+
+var (
+	// ErrConstraint is returned when a write breaks one of the constraints the database schema enforces.
+	ErrConstraint = errors.New("testkit: constraint violation")
+	ErrTxDone     = errors.New("testkit: transaction already committed or rolled back")
+	// ErrConflict is returned by Commit when a transaction that locked the same account committed
+	// first; a database would have made the second one wait and read the changes of the first.
+	ErrConflict = errors.New("testkit: concurrent transaction conflict")
+)
+
+// Store is an in-memory gateway.AccountRepository, gateway.WebhookRepository,
+// gateway.TokenRepository, gateway.MFARepository, gateway.LoginAttemptRepository,
+// gateway.VerificationRepository, gateway.APIKeyRepository and gateway.AuditRepository. Writes made
+// with a transaction in the context are only visible through that transaction until Commit, and
+// are discarded by Rollback; writes without a transaction are applied right away.
+type Store struct {
+	mu    sync.RWMutex
+	state *state
+}
+
+var (
+	_ gateway.AccountRepository      = (*Store)(nil)
+	_ gateway.WebhookRepository      = (*Store)(nil)
+	_ gateway.TokenRepository        = (*Store)(nil)
+	_ gateway.MFARepository          = (*Store)(nil)
+	_ gateway.LoginAttemptRepository = (*Store)(nil)
+	_ gateway.VerificationRepository = (*Store)(nil)
+	_ gateway.APIKeyRepository       = (*Store)(nil)
+	_ gateway.AuditRepository        = (*Store)(nil)
+)
+
+func NewStore() *Store {
+	return &Store{state: newState()}
+}
+
+type state struct {
+	accounts     map[uuid.UUID]entity.Account
+	transactions []entity.Transaction
+	webhooks     map[uuid.UUID]entity.Webhook
+	deliveries   map[uuid.UUID]entity.WebhookDelivery
+	refresh      map[uuid.UUID]entity.RefreshToken
+	revoked      map[uuid.UUID]entity.RevokedToken
+	totp         map[uuid.UUID]entity.TOTP
+	logins       map[string]entity.LoginAttempts
+	resets       map[uuid.UUID]entity.PasswordResetToken
+	verification map[verificationKey]entity.Verification
+	apiKeys      map[uuid.UUID]entity.APIKey
+	audit        []entity.AuditEntry
+	locks        map[uuid.UUID]int
+}
+
+func newState() *state {
+	return &state{
+		accounts:     make(map[uuid.UUID]entity.Account),
+		webhooks:     make(map[uuid.UUID]entity.Webhook),
+		deliveries:   make(map[uuid.UUID]entity.WebhookDelivery),
+		refresh:      make(map[uuid.UUID]entity.RefreshToken),
+		revoked:      make(map[uuid.UUID]entity.RevokedToken),
+		totp:         make(map[uuid.UUID]entity.TOTP),
+		logins:       make(map[string]entity.LoginAttempts),
+		resets:       make(map[uuid.UUID]entity.PasswordResetToken),
+		verification: make(map[verificationKey]entity.Verification),
+		apiKeys:      make(map[uuid.UUID]entity.APIKey),
+		locks:        make(map[uuid.UUID]int),
+	}
+}
+
+func (s *state) clone() *state {
+	c := newState()
+	for k, v := range s.accounts {
+		c.accounts[k] = v
+	}
+	for k, v := range s.webhooks {
+		c.webhooks[k] = v
+	}
+	for k, v := range s.deliveries {
+		c.deliveries[k] = v
+	}
+	for k, v := range s.refresh {
+		c.refresh[k] = v
+	}
+	for k, v := range s.revoked {
+		c.revoked[k] = v
+	}
+	for k, v := range s.totp {
+		c.totp[k] = v
+	}
+	for k, v := range s.logins {
+		c.logins[k] = v
+	}
+	for k, v := range s.resets {
+		c.resets[k] = v
+	}
+	for k, v := range s.verification {
+		c.verification[k] = v
+	}
+	for k, v := range s.apiKeys {
+		c.apiKeys[k] = v
+	}
+	for k, v := range s.locks {
+		c.locks[k] = v
+	}
+	c.transactions = append(c.transactions, s.transactions...)
+	c.audit = append(c.audit, s.audit...)
+	return c
+}
+
+type storeTx struct {
+	store *Store
+	mu    sync.Mutex
+	state *state
+	ops   []func(*state) error
+	done  bool
+}
+
+func (s *Store) NewTransaction(ctx context.Context) (gateway.Tx, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return &storeTx{store: s, state: s.state.clone()}, nil
+}
+
+// Commit replays the transaction writes on the current store state; it fails without applying
+// anything when one of them now breaks a constraint.
+func (tx *storeTx) Commit() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+
+	tx.store.mu.Lock()
+	defer tx.store.mu.Unlock()
+
+	next := tx.store.state.clone()
+	for _, op := range tx.ops {
+		if err := op(next); err != nil {
+			return err
+		}
+	}
+
+	tx.store.state = next
+	return nil
+}
+
+func (tx *storeTx) Rollback() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if tx.done {
+		return ErrTxDone
+	}
+
+	tx.done = true
+	return nil
+}
+
+func (s *Store) transaction(ctx context.Context) *storeTx {
+	tx, ok := gateway.GetTransactionContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	if t, ok := tx.(*storeTx); ok && t.store == s {
+		return t
+	}
+
+	return nil
+}
+
+func (s *Store) read(ctx context.Context, fn func(*state) error) error {
+	if tx := s.transaction(ctx); tx != nil {
+		tx.mu.Lock()
+		defer tx.mu.Unlock()
+		if tx.done {
+			return ErrTxDone
+		}
+		return fn(tx.state)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(s.state)
+}
+
+func (s *Store) write(ctx context.Context, op func(*state) error) error {
+	if tx := s.transaction(ctx); tx != nil {
+		tx.mu.Lock()
+		defer tx.mu.Unlock()
+		if tx.done {
+			return ErrTxDone
+		}
+
+		next := tx.state.clone()
+		if err := op(next); err != nil {
+			return err
+		}
+
+		tx.state = next
+		tx.ops = append(tx.ops, op)
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := s.state.clone()
+	if err := op(next); err != nil {
+		return err
+	}
+
+	s.state = next
+	return nil
+}
+
+func notFound(what string) error {
+	return fmt.Errorf("testkit: %s: %w", what, sql.ErrNoRows)
+}
+
+// accountRecord keeps only the persisted fields, dropping the wallet and any recorded events.
+func accountRecord(a entity.Account) entity.Account {
+	return entity.Account{
+		ID:              a.ID,
+		AccountType:     a.AccountType,
+		Role:            a.Role,
+		CustomerName:    a.CustomerName,
+		DocumentNumber:  a.DocumentNumber,
+		Email:           a.Email,
+		PasswordEncoded: a.PasswordEncoded,
+		PhoneNumber:     a.PhoneNumber,
+		Status:          a.Status,
+		CreatedAt:       a.CreatedAt,
+		UpdatedAt:       a.UpdatedAt,
+	}
+}
+
+func (s *state) account(id uuid.UUID) (*entity.Account, error) {
+	record, ok := s.accounts[id]
+	if !ok {
+		return nil, notFound("account")
+	}
+
+	account := accountRecord(record)
+	for _, t := range s.transactions {
+		if t.AccountID == id && !t.SnapshotID.Valid {
+			t := t
+			account.Wallet = append(account.Wallet, &t)
+		}
+	}
+
+	return &account, nil
+}
+
+func (s *Store) CreateAccount(ctx context.Context, account entity.Account) error {
+	record := accountRecord(account)
+	return s.write(ctx, func(st *state) error {
+		for _, a := range st.accounts {
+			switch {
+			case a.ID == record.ID:
+				return fmt.Errorf("%w: account id %s", ErrConstraint, a.ID)
+			case a.Email == record.Email:
+				return entity.ErrDuplicateEmail.Wrap(fmt.Errorf("%w: email %s", ErrConstraint, a.Email))
+			case a.DocumentNumber == record.DocumentNumber:
+				return entity.ErrDuplicateDocument.Wrap(fmt.Errorf("%w: document %s", ErrConstraint, a.DocumentNumber))
+			}
+		}
+
+		st.accounts[record.ID] = record
+		return nil
+	})
+}
+
+func (s *Store) FindAccount(ctx context.Context, accountID uuid.UUID) (*entity.Account, error) {
+	var account *entity.Account
+	err := s.read(ctx, func(st *state) (err error) {
+		account, err = st.account(accountID)
+		return err
+	})
+
+	return account, err
+}
+
+func (s *Store) FindAccountByIDs(ctx context.Context, ids ...uuid.UUID) (map[uuid.UUID]*entity.Account, error) {
+	result := make(map[uuid.UUID]*entity.Account)
+	err := s.read(ctx, func(st *state) error {
+		for _, id := range ids {
+			account, err := st.account(id)
+			if err != nil {
+				return err
+			}
+			result[id] = account
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *Store) SaveAtomicTransactions(ctx context.Context, transactions ...entity.Transaction) error {
+	saved := append([]entity.Transaction(nil), transactions...)
+	return s.write(ctx, func(st *state) error {
+		for _, t := range saved {
+			if _, ok := st.accounts[t.AccountID]; !ok {
+				return fmt.Errorf("%w: account %s does not exist", ErrConstraint, t.AccountID)
+			}
+
+			for _, existing := range st.transactions {
+				if existing.ID == t.ID {
+					return fmt.Errorf("%w: transaction id %s", ErrConstraint, t.ID)
+				}
+
+				if t.ParentID.Valid && existing.AccountID == t.AccountID && existing.ParentID == t.ParentID {
+					return entity.ErrConcurrentUpdate.Wrap(fmt.Errorf("%w: account %s already has a transaction with parent %s", ErrConstraint, t.AccountID, t.ParentID.UUID))
+				}
+			}
+
+			t.SnapshotID = uuid.NullUUID{}
+			t.Sequence = st.feedSequence(t.AccountID) + 1
+			st.transactions = append(st.transactions, t)
+		}
+
+		return nil
+	})
+}
+
+// accountSummaries returns the summaries of the accounts matching the filter, newest first; the cursor
+// and limit are left to the caller.
+func (s *state) accountSummaries(filter entity.AccountFilter) []*entity.AccountSummary {
+	in := func(v string, values []string) bool {
+		if len(values) == 0 {
+			return true
+		}
+		for _, value := range values {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	}
+
+	types := make([]string, 0, len(filter.Types))
+	for _, t := range filter.Types {
+		types = append(types, string(t))
+	}
+
+	statuses := make([]string, 0, len(filter.Statuses))
+	for _, st := range filter.Statuses {
+		statuses = append(statuses, string(st))
+	}
+
+	summaries := make([]*entity.AccountSummary, 0)
+	for _, record := range s.accounts {
+		if !in(string(record.AccountType), types) || !in(string(record.Status), statuses) ||
+			!strings.HasPrefix(strings.ToLower(record.CustomerName), strings.ToLower(filter.NamePrefix)) ||
+			!strings.HasPrefix(strings.ToLower(record.Email), strings.ToLower(filter.EmailPrefix)) {
+			continue
+		}
+
+		summary := entity.AccountSummary{Account: accountRecord(record)}
+		for _, t := range s.transactions {
+			if t.AccountID == record.ID && !t.SnapshotID.Valid {
+				summary.Balance += t.Amount
+			}
+		}
+		summaries = append(summaries, &summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool { return newer(summaries[i].Account, summaries[j].CreatedAt, summaries[j].ID) })
+	return summaries
+}
+
+// newer tells whether the account comes before the (createdAt, id) position in a listing.
+func newer(a entity.Account, createdAt time.Time, id uuid.UUID) bool {
+	if !a.CreatedAt.Equal(createdAt) {
+		return a.CreatedAt.After(createdAt)
+	}
+
+	return strings.Compare(a.ID.String(), id.String()) > 0
+}
+
+func (s *Store) ListAccounts(ctx context.Context, filter entity.AccountFilter) ([]*entity.AccountSummary, error) {
+	accounts := make([]*entity.AccountSummary, 0)
+	err := s.read(ctx, func(st *state) error {
+		for _, summary := range st.accountSummaries(filter) {
+			// the page starts strictly after the cursor
+			if filter.Cursor != nil && (summary.ID == filter.Cursor.ID || newer(summary.Account, filter.Cursor.CreatedAt, filter.Cursor.ID)) {
+				continue
+			}
+
+			if filter.Limit > 0 && len(accounts) == filter.Limit {
+				break
+			}
+
+			accounts = append(accounts, summary)
+		}
+		return nil
+	})
+
+	return accounts, err
+}
+
+func (s *Store) CountAccounts(ctx context.Context, filter entity.AccountFilter) (int, error) {
+	var total int
+	err := s.read(ctx, func(st *state) error {
+		total = len(st.accountSummaries(filter))
+		return nil
+	})
+
+	return total, err
+}
+
+func (s *Store) SetSnapshotTransactions(ctx context.Context, snapshotID uuid.UUID, transactionIDs uuid.UUIDs) error {
+	ids := make(map[uuid.UUID]bool, len(transactionIDs))
+	for _, id := range transactionIDs {
+		ids[id] = true
+	}
+
+	return s.write(ctx, func(st *state) error {
+		marked := 0
+		transactions := append([]entity.Transaction(nil), st.transactions...)
+		for i := range transactions {
+			if ids[transactions[i].ID] {
+				transactions[i].SnapshotID = uuid.NullUUID{UUID: snapshotID, Valid: true}
+				marked++
+			}
+		}
+
+		if marked == 0 {
+			return notFound("transactions")
+		}
+
+		st.transactions = transactions
+		return nil
+	})
+}
+
+func (s *Store) findAccountBy(ctx context.Context, match func(entity.Account) bool) (*entity.Account, error) {
+	var account *entity.Account
+	err := s.read(ctx, func(st *state) (err error) {
+		for id, a := range st.accounts {
+			if match(a) {
+				account, err = st.account(id)
+				return err
+			}
+		}
+		return notFound("account")
+	})
+
+	return account, err
+}
+
+func (s *Store) FindAccountByEmail(ctx context.Context, email string) (*entity.Account, error) {
+	return s.findAccountBy(ctx, func(a entity.Account) bool { return a.Email == email })
+}
+
+func (s *Store) FindAccountByDocument(ctx context.Context, document string) (*entity.Account, error) {
+	return s.findAccountBy(ctx, func(a entity.Account) bool { return a.DocumentNumber == document })
+}
+
+func (s *Store) UpdateAccount(ctx context.Context, account entity.Account) error {
+	return s.write(ctx, func(st *state) error {
+		record, ok := st.accounts[account.ID]
+		if !ok {
+			return notFound("account")
+		}
+
+		record.PasswordEncoded = account.PasswordEncoded
+		record.Status = account.Status
+		record.Role = account.Role
+		record.UpdatedAt = account.UpdatedAt
+		st.accounts[account.ID] = record
+		return nil
+	})
+}
+
+// LockAccount counts the locks of the account; at commit the count must still be the one the
+// transaction saw, otherwise another transaction locked the account meanwhile and ErrConflict is
+// returned.
+func (s *Store) LockAccount(ctx context.Context, accountID uuid.UUID) error {
+	var locks int
+	err := s.read(ctx, func(st *state) error {
+		if _, ok := st.accounts[accountID]; !ok {
+			return notFound("account")
+		}
+
+		locks = st.locks[accountID]
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.write(ctx, func(st *state) error {
+		if st.locks[accountID] != locks {
+			return fmt.Errorf("%w: account %s", ErrConflict, accountID)
+		}
+
+		st.locks[accountID]++
+		return nil
+	})
+}
+
+func (s *Store) FindTransactionsByCorrelatedID(ctx context.Context, correlatedID uuid.UUID) ([]*entity.Transaction, error) {
+	transactions := make([]*entity.Transaction, 0)
+	err := s.read(ctx, func(st *state) error {
+		for _, t := range st.transactions {
+			if t.CorrelatedID.Valid && t.CorrelatedID.UUID == correlatedID {
+				t := t
+				transactions = append(transactions, &t)
+			}
+		}
+		return nil
+	})
+
+	return transactions, err
+}
+
+func (s *Store) HasTransferredTo(ctx context.Context, payer, payee uuid.UUID) (bool, error) {
+	var exists bool
+	err := s.read(ctx, func(st *state) error {
+		received := make(map[uuid.UUID]bool)
+		for _, t := range st.transactions {
+			if t.AccountID == payee && t.TransactionType == entity.TransferPayee && t.CorrelatedID.Valid {
+				received[t.CorrelatedID.UUID] = true
+			}
+		}
+
+		for _, t := range st.transactions {
+			if t.AccountID == payer && t.TransactionType == entity.TransferPayer && received[t.CorrelatedID.UUID] {
+				exists = true
+				break
+			}
+		}
+		return nil
+	})
+
+	return exists, err
+}
+
+func (s *Store) FindResumeAccount(ctx context.Context, email string) (*entity.ResumeAccount, error) {
+	account, err := s.FindAccountByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	return resumeAccount(account), nil
+}
+
+func (s *Store) FindResumeAccountByID(ctx context.Context, accountID uuid.UUID) (*entity.ResumeAccount, error) {
+	account, err := s.FindAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resumeAccount(account), nil
+}
+
+func resumeAccount(account *entity.Account) *entity.ResumeAccount {
+	return &entity.ResumeAccount{
+		ID:              account.ID,
+		AccountType:     account.AccountType,
+		Role:            account.Role,
+		Email:           account.Email,
+		Status:          account.Status,
+		PasswordEncoded: account.PasswordEncoded,
+	}
+}
+
+// statement mirrors the SQL statement query: running balance over every non SNAPSHOT transaction,
+// then filters, cursor, order and limit.
+func (s *state) statement(accountID uuid.UUID, filter entity.StatementFilter, ascending bool) []*entity.StatementLine {
+	history := make([]entity.Transaction, 0)
+	for _, t := range s.transactions {
+		if t.AccountID == accountID && t.TransactionType != entity.Snapshot {
+			history = append(history, t)
+		}
+	}
+	sort.Slice(history, func(i, j int) bool { return before(history[i], history[j]) })
+
+	var balance entity.Money
+	lines := make([]*entity.StatementLine, 0, len(history))
+	for _, t := range history {
+		balance += t.Amount
+		line := &entity.StatementLine{Transaction: t, Balance: balance}
+		if !matches(t, filter) {
+			continue
+		}
+
+		for _, other := range s.transactions {
+			if t.CorrelatedID.Valid && other.CorrelatedID == t.CorrelatedID && other.ID != t.ID {
+				a := s.accounts[other.AccountID]
+				line.Counterparty = &entity.Counterparty{AccountID: a.ID, AccountType: a.AccountType, CustomerName: a.CustomerName}
+			}
+		}
+
+		lines = append(lines, line)
+	}
+
+	if !ascending {
+		for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+			lines[i], lines[j] = lines[j], lines[i]
+		}
+	}
+
+	if c := filter.Cursor; c != nil {
+		cursor := entity.Transaction{ID: c.ID, Timestamp: c.Timestamp}
+		rest := make([]*entity.StatementLine, 0, len(lines))
+		for _, line := range lines {
+			if (ascending && before(cursor, line.Transaction)) || (!ascending && before(line.Transaction, cursor)) {
+				rest = append(rest, line)
+			}
+		}
+		lines = rest
+	}
+
+	if filter.Limit > 0 && len(lines) > filter.Limit {
+		lines = lines[:filter.Limit]
+	}
+
+	return lines
+}
+
+func before(a, b entity.Transaction) bool {
+	if !a.Timestamp.Equal(b.Timestamp) {
+		return a.Timestamp.Before(b.Timestamp)
+	}
+
+	return strings.Compare(a.ID.String(), b.ID.String()) < 0
+}
+
+func matches(t entity.Transaction, filter entity.StatementFilter) bool {
+	if !filter.From.IsZero() && t.Timestamp.Before(filter.From) {
+		return false
+	}
+
+	if !filter.To.IsZero() && t.Timestamp.After(filter.To) {
+		return false
+	}
+
+	if len(filter.Types) > 0 {
+		found := false
+		for _, typ := range filter.Types {
+			found = found || typ == t.TransactionType
+		}
+		if !found {
+			return false
+		}
+	}
+
+	amount := t.Amount.Absolute()
+	if filter.MinAmount > 0 && amount < filter.MinAmount {
+		return false
+	}
+
+	return filter.MaxAmount <= 0 || amount <= filter.MaxAmount
+}
+
+func (s *Store) FindStatement(ctx context.Context, accountID uuid.UUID, filter entity.StatementFilter) ([]*entity.StatementLine, error) {
+	var lines []*entity.StatementLine
+	err := s.read(ctx, func(st *state) error {
+		lines = st.statement(accountID, filter, false)
+		return nil
+	})
+
+	return lines, err
+}
+
+func (s *Store) StreamStatement(ctx context.Context, accountID uuid.UUID, filter entity.StatementFilter, fn func(*entity.StatementLine) error) error {
+	filter.Limit = 0
+	var lines []*entity.StatementLine
+	err := s.read(ctx, func(st *state) error {
+		lines = st.statement(accountID, filter, true)
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, line := range lines {
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) FindSnapshotBefore(ctx context.Context, accountID uuid.UUID, at time.Time) (*entity.Transaction, error) {
+	var snapshot *entity.Transaction
+	err := s.read(ctx, func(st *state) error {
+		for _, t := range st.transactions {
+			if t.AccountID != accountID || t.TransactionType != entity.Snapshot || t.Timestamp.After(at) {
+				continue
+			}
+
+			if snapshot == nil || t.Timestamp.After(snapshot.Timestamp) {
+				t := t
+				snapshot = &t
+			}
+		}
+		return nil
+	})
+
+	return snapshot, err
+}
+
+func (s *Store) FindTransactionsAfterSnapshot(ctx context.Context, accountID uuid.UUID, snapshot *entity.Transaction, at time.Time) (entity.Wallet, error) {
+	var snapshotAt time.Time
+	if snapshot != nil {
+		snapshotAt = snapshot.Timestamp
+	}
+
+	wallet := make(entity.Wallet, 0)
+	err := s.read(ctx, func(st *state) error {
+		snapshots := make(map[uuid.UUID]time.Time)
+		for _, t := range st.transactions {
+			if t.TransactionType == entity.Snapshot {
+				snapshots[t.ID] = t.Timestamp
+			}
+		}
+
+		for _, t := range st.transactions {
+			if t.AccountID != accountID || t.TransactionType == entity.Snapshot || t.Timestamp.After(at) {
+				continue
+			}
+
+			if t.SnapshotID.Valid && !snapshots[t.SnapshotID.UUID].After(snapshotAt) {
+				continue
+			}
+
+			t := t
+			wallet = append(wallet, &t)
+		}
+		return nil
+	})
+
+	sort.Slice(wallet, func(i, j int) bool { return before(*wallet[i], *wallet[j]) })
+	return wallet, err
+}
+
+// feedSequence is the last position taken in the feed of the account; writes are serialized, so
+// positions follow commit order as they do in the databases.
+func (s *state) feedSequence(accountID uuid.UUID) int64 {
+	var last int64
+	for _, t := range s.transactions {
+		if t.AccountID == accountID {
+			last = max(last, t.Sequence)
+		}
+	}
+
+	return last
+}
+
+func (s *Store) FindTransactionFeed(ctx context.Context, accountID uuid.UUID, cursor *entity.FeedCursor, limit int) ([]*entity.Transaction, error) {
+	transactions := make([]*entity.Transaction, 0)
+	err := s.read(ctx, func(st *state) error {
+		for _, t := range st.transactions {
+			if t.AccountID != accountID {
+				continue
+			}
+
+			if cursor != nil && t.Sequence <= cursor.Sequence {
+				continue
+			}
+
+			t := t
+			transactions = append(transactions, &t)
+		}
+		return nil
+	})
+
+	sort.Slice(transactions, func(i, j int) bool { return transactions[i].Sequence < transactions[j].Sequence })
+	if len(transactions) > limit {
+		if cursor != nil {
+			transactions = transactions[:limit]
+		} else {
+			transactions = transactions[len(transactions)-limit:]
+		}
+	}
+
+	return transactions, err
+}
@@ -0,0 +1,192 @@
+package testkit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+)
+
+var errTokenUsed = errors.New("testkit: refresh token already used")
+
+func (s *Store) SaveRefreshToken(ctx context.Context, token entity.RefreshToken) error {
+	return s.write(ctx, func(st *state) error {
+		if _, ok := st.accounts[token.AccountID]; !ok {
+			return fmt.Errorf("%w: account %s does not exist", ErrConstraint, token.AccountID)
+		}
+
+		for _, t := range st.refresh {
+			if t.ID == token.ID || t.TokenHash == token.TokenHash {
+				return fmt.Errorf("%w: refresh token %s", ErrConstraint, token.ID)
+			}
+		}
+
+		st.refresh[token.ID] = token
+		return nil
+	})
+}
+
+func (s *Store) FindRefreshToken(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	var token *entity.RefreshToken
+	err := s.read(ctx, func(st *state) error {
+		for _, t := range st.refresh {
+			if t.TokenHash == tokenHash {
+				token = &t
+				return nil
+			}
+		}
+
+		return notFound("refresh token")
+	})
+
+	return token, err
+}
+
+// UseRefreshToken fails the commit of its transaction when another one used the token meanwhile,
+// as the conditional update of the database would.
+func (s *Store) UseRefreshToken(ctx context.Context, tokenID uuid.UUID, at time.Time) (bool, error) {
+	err := s.write(ctx, func(st *state) error {
+		t, ok := st.refresh[tokenID]
+		if !ok || t.UsedAt.Valid || t.RevokedAt.Valid {
+			return errTokenUsed
+		}
+
+		t.UsedAt = sql.NullTime{Time: at, Valid: true}
+		st.refresh[tokenID] = t
+		return nil
+	})
+
+	if errors.Is(err, errTokenUsed) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (s *Store) RevokeTokenFamily(ctx context.Context, familyID uuid.UUID, at time.Time) error {
+	return s.write(ctx, func(st *state) error {
+		for id, t := range st.refresh {
+			if t.FamilyID == familyID && !t.RevokedAt.Valid {
+				t.RevokedAt = sql.NullTime{Time: at, Valid: true}
+				st.refresh[id] = t
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Store) RevokeTokens(ctx context.Context, tokens ...entity.RevokedToken) error {
+	saved := append([]entity.RevokedToken(nil), tokens...)
+	return s.write(ctx, func(st *state) error {
+		for _, t := range saved {
+			if _, ok := st.revoked[t.ID]; !ok {
+				st.revoked[t.ID] = t
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Store) IsTokenRevoked(ctx context.Context, ids ...uuid.UUID) (bool, error) {
+	now := time.Now()
+	revoked := false
+	err := s.read(ctx, func(st *state) error {
+		for _, id := range ids {
+			if t, ok := st.revoked[id]; ok && t.ExpiresAt.After(now) {
+				revoked = true
+			}
+		}
+		return nil
+	})
+
+	return revoked, err
+}
+
+func (s *Store) RevokeAccountSessions(ctx context.Context, accountID uuid.UUID, at time.Time) ([]uuid.UUID, error) {
+	var families []uuid.UUID
+	err := s.write(ctx, func(st *state) error {
+		families = families[:0]
+		seen := make(map[uuid.UUID]bool)
+		for id, t := range st.refresh {
+			if t.AccountID != accountID || t.RevokedAt.Valid {
+				continue
+			}
+
+			if t.ExpiresAt.After(at) && !seen[t.FamilyID] {
+				seen[t.FamilyID] = true
+				families = append(families, t.FamilyID)
+			}
+
+			t.RevokedAt = sql.NullTime{Time: at, Valid: true}
+			st.refresh[id] = t
+		}
+		return nil
+	})
+
+	return families, err
+}
+
+func (s *Store) SavePasswordResetToken(ctx context.Context, token entity.PasswordResetToken) error {
+	return s.write(ctx, func(st *state) error {
+		if _, ok := st.accounts[token.AccountID]; !ok {
+			return fmt.Errorf("%w: account %s does not exist", ErrConstraint, token.AccountID)
+		}
+
+		for _, t := range st.resets {
+			if t.TokenHash == token.TokenHash && t.AccountID != token.AccountID {
+				return fmt.Errorf("%w: password reset token of %s", ErrConstraint, token.AccountID)
+			}
+		}
+
+		st.resets[token.AccountID] = token
+		return nil
+	})
+}
+
+func (s *Store) FindPasswordResetToken(ctx context.Context, tokenHash string) (*entity.PasswordResetToken, error) {
+	var token *entity.PasswordResetToken
+	err := s.read(ctx, func(st *state) error {
+		for _, t := range st.resets {
+			if t.TokenHash == tokenHash {
+				token = &t
+				return nil
+			}
+		}
+
+		return notFound("password reset token")
+	})
+
+	return token, err
+}
+
+// UsePasswordResetToken fails the commit of its transaction when another one used the token
+// meanwhile, as the conditional update of the database would.
+func (s *Store) UsePasswordResetToken(ctx context.Context, tokenHash string, at time.Time) (bool, error) {
+	err := s.write(ctx, func(st *state) error {
+		for id, t := range st.resets {
+			if t.TokenHash != tokenHash {
+				continue
+			}
+
+			if t.UsedAt.Valid {
+				return errTokenUsed
+			}
+
+			t.UsedAt = sql.NullTime{Time: at, Valid: true}
+			st.resets[id] = t
+			return nil
+		}
+
+		return errTokenUsed
+	})
+
+	if errors.Is(err, errTokenUsed) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
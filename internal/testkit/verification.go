@@ -0,0 +1,67 @@
+package testkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+)
+
+var errNoAttemptsLeft = errors.New("testkit: verification has no attempts left")
+
+type verificationKey struct {
+	accountID uuid.UUID
+	channel   entity.VerificationChannel
+}
+
+func (s *Store) SaveVerification(ctx context.Context, v entity.Verification) error {
+	return s.write(ctx, func(st *state) error {
+		if _, ok := st.accounts[v.AccountID]; !ok {
+			return fmt.Errorf("%w: account %s does not exist", ErrConstraint, v.AccountID)
+		}
+
+		st.verification[verificationKey{accountID: v.AccountID, channel: v.Channel}] = v
+		return nil
+	})
+}
+
+func (s *Store) FindVerifications(ctx context.Context, accountID uuid.UUID) ([]*entity.Verification, error) {
+	var verifications []*entity.Verification
+	err := s.read(ctx, func(st *state) error {
+		verifications = nil
+		for k, v := range st.verification {
+			if k.accountID == accountID {
+				verifications = append(verifications, &v)
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(verifications, func(i, j int) bool { return verifications[i].Channel < verifications[j].Channel })
+	return verifications, err
+}
+
+// CountVerificationAttempt fails the commit of its transaction when another one used the last
+// attempt meanwhile, as the conditional update of the database would.
+func (s *Store) CountVerificationAttempt(ctx context.Context, accountID uuid.UUID, channel entity.VerificationChannel, maxAttempts int) (bool, error) {
+	key := verificationKey{accountID: accountID, channel: channel}
+	err := s.write(ctx, func(st *state) error {
+		v, ok := st.verification[key]
+		if !ok || v.Verified() || v.Attempts >= maxAttempts {
+			return errNoAttemptsLeft
+		}
+
+		v.Attempts++
+		st.verification[key] = v
+		return nil
+	})
+
+	if errors.Is(err, errNoAttemptsLeft) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
@@ -0,0 +1,172 @@
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guilhermealvess/guicpay/domain/entity"
+)
+
+func (s *Store) CreateWebhook(ctx context.Context, webhook entity.Webhook) error {
+	return s.write(ctx, func(st *state) error {
+		if _, ok := st.accounts[webhook.AccountID]; !ok {
+			return fmt.Errorf("%w: account %s does not exist", ErrConstraint, webhook.AccountID)
+		}
+
+		if _, ok := st.webhooks[webhook.ID]; ok {
+			return fmt.Errorf("%w: webhook id %s", ErrConstraint, webhook.ID)
+		}
+
+		st.webhooks[webhook.ID] = webhook
+		return nil
+	})
+}
+
+func (s *Store) FindWebhook(ctx context.Context, webhookID uuid.UUID) (*entity.Webhook, error) {
+	var webhook *entity.Webhook
+	err := s.read(ctx, func(st *state) error {
+		w, ok := st.webhooks[webhookID]
+		if !ok {
+			return notFound("webhook")
+		}
+
+		webhook = &w
+		return nil
+	})
+
+	return webhook, err
+}
+
+func (s *Store) FindWebhooksByAccount(ctx context.Context, accountID uuid.UUID) ([]*entity.Webhook, error) {
+	webhooks := make([]*entity.Webhook, 0)
+	err := s.read(ctx, func(st *state) error {
+		for _, w := range st.webhooks {
+			if w.AccountID == accountID {
+				w := w
+				webhooks = append(webhooks, &w)
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt) })
+	return webhooks, err
+}
+
+func (s *Store) DeleteWebhook(ctx context.Context, webhookID uuid.UUID) error {
+	return s.write(ctx, func(st *state) error {
+		if _, ok := st.webhooks[webhookID]; !ok {
+			return notFound("webhook")
+		}
+
+		delete(st.webhooks, webhookID)
+		for id, d := range st.deliveries {
+			if d.WebhookID == webhookID {
+				delete(st.deliveries, id)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Store) SaveWebhookDeliveries(ctx context.Context, deliveries ...entity.WebhookDelivery) error {
+	saved := append([]entity.WebhookDelivery(nil), deliveries...)
+	return s.write(ctx, func(st *state) error {
+		for _, d := range saved {
+			if _, ok := st.webhooks[d.WebhookID]; !ok {
+				return fmt.Errorf("%w: webhook %s does not exist", ErrConstraint, d.WebhookID)
+			}
+
+			if _, ok := st.deliveries[d.ID]; ok {
+				return fmt.Errorf("%w: delivery id %s", ErrConstraint, d.ID)
+			}
+
+			st.deliveries[d.ID] = d
+		}
+		return nil
+	})
+}
+
+func (s *Store) UpdateWebhookDelivery(ctx context.Context, delivery entity.WebhookDelivery) error {
+	return s.write(ctx, func(st *state) error {
+		d, ok := st.deliveries[delivery.ID]
+		if !ok {
+			return nil
+		}
+
+		d.Status = delivery.Status
+		d.Attempts = delivery.Attempts
+		d.LastStatusCode = delivery.LastStatusCode
+		d.LastError = delivery.LastError
+		d.NextAttemptAt = delivery.NextAttemptAt
+		d.UpdatedAt = delivery.UpdatedAt
+		st.deliveries[d.ID] = d
+		return nil
+	})
+}
+
+func (s *Store) FindWebhookDelivery(ctx context.Context, deliveryID uuid.UUID) (*entity.WebhookDelivery, error) {
+	var delivery *entity.WebhookDelivery
+	err := s.read(ctx, func(st *state) error {
+		d, ok := st.deliveries[deliveryID]
+		if !ok {
+			return notFound("webhook delivery")
+		}
+
+		delivery = &d
+		return nil
+	})
+
+	return delivery, err
+}
+
+func (s *Store) FindWebhookDeliveries(ctx context.Context, webhookID uuid.UUID) ([]*entity.WebhookDelivery, error) {
+	deliveries := make([]*entity.WebhookDelivery, 0)
+	err := s.read(ctx, func(st *state) error {
+		for _, d := range st.deliveries {
+			if d.WebhookID == webhookID {
+				d := d
+				deliveries = append(deliveries, &d)
+			}
+		}
+		return nil
+	})
+
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt) })
+	if len(deliveries) > 100 {
+		deliveries = deliveries[:100]
+	}
+
+	return deliveries, err
+}
+
+func (s *Store) ClaimWebhookDeliveries(ctx context.Context, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error) {
+	now := time.Now().UTC()
+	claimed := make([]*entity.WebhookDelivery, 0)
+	err := s.write(ctx, func(st *state) error {
+		due := make([]entity.WebhookDelivery, 0)
+		for _, d := range st.deliveries {
+			if d.Status == entity.WebhookDeliveryPending && !d.NextAttemptAt.After(now) {
+				due = append(due, d)
+			}
+		}
+
+		sort.Slice(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(due[j].NextAttemptAt) })
+		if len(due) > limit {
+			due = due[:limit]
+		}
+
+		claimed = claimed[:0]
+		for _, d := range due {
+			d.NextAttemptAt = now.Add(lease)
+			st.deliveries[d.ID] = d
+			claimed = append(claimed, &d)
+		}
+		return nil
+	})
+
+	return claimed, err
+}
@@ -0,0 +1,210 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// Key is one signing key. It signs from ActiveFrom until the next key of its set becomes active,
+// and keeps verifying for the overlap window of the set after that, so tokens signed just before
+// a rotation stay valid until they expire.
+type Key struct {
+	ID         string
+	Algorithm  string
+	ActiveFrom time.Time
+
+	method   jwt.SigningMethod
+	private  any
+	public   any
+	retireAt time.Time
+}
+
+// NewKey builds a key for the algorithm: private is a []byte secret for HS256, an *rsa.PrivateKey
+// for RS256 and an ed25519.PrivateKey for EdDSA.
+func NewKey(id, algorithm string, private any, activeFrom time.Time) (*Key, error) {
+	key := &Key{ID: id, Algorithm: algorithm, ActiveFrom: activeFrom.UTC(), private: private}
+
+	switch k := private.(type) {
+	case []byte:
+		if algorithm != AlgorithmHS256 {
+			return nil, fmt.Errorf("key %q: a secret cannot sign %s", id, algorithm)
+		}
+		key.method, key.public = jwt.SigningMethodHS256, k
+
+	case *rsa.PrivateKey:
+		if algorithm != AlgorithmRS256 {
+			return nil, fmt.Errorf("key %q: an RSA key cannot sign %s", id, algorithm)
+		}
+		key.method, key.public = jwt.SigningMethodRS256, &k.PublicKey
+
+	case ed25519.PrivateKey:
+		if algorithm != AlgorithmEdDSA {
+			return nil, fmt.Errorf("key %q: an Ed25519 key cannot sign %s", id, algorithm)
+		}
+		key.method, key.public = jwt.SigningMethodEdDSA, k.Public()
+
+	default:
+		return nil, fmt.Errorf("key %q: unsupported key type %T", id, private)
+	}
+
+	if algorithm != AlgorithmHS256 && id == "" {
+		return nil, errors.New("asymmetric keys need an id to be published")
+	}
+
+	return key, nil
+}
+
+// Asymmetric reports whether the key can be published: only its public half verifies tokens.
+func (k *Key) Asymmetric() bool {
+	return k.Algorithm != AlgorithmHS256
+}
+
+func (k *Key) retired(now time.Time) bool {
+	return !k.retireAt.IsZero() && !now.Before(k.retireAt)
+}
+
+// KeySet holds the keys of a rotation schedule ordered by activation.
+type KeySet struct {
+	keys []*Key
+}
+
+func NewKeySet(overlap time.Duration, keys ...*Key) (*KeySet, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("key set is empty")
+	}
+
+	sorted := make([]*Key, 0, len(keys))
+	for _, key := range keys {
+		k := *key
+		sorted = append(sorted, &k)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ActiveFrom.Before(sorted[j].ActiveFrom) })
+
+	seen := make(map[string]bool, len(sorted))
+	for i, key := range sorted {
+		if seen[key.ID] {
+			return nil, fmt.Errorf("key id %q is used twice", key.ID)
+		}
+		seen[key.ID] = true
+
+		if i+1 < len(sorted) {
+			key.retireAt = sorted[i+1].ActiveFrom.Add(overlap)
+		}
+	}
+
+	return &KeySet{keys: sorted}, nil
+}
+
+// signing returns the key active at now: the last one activated.
+func (s *KeySet) signing(now time.Time) (*Key, error) {
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		if !s.keys[i].ActiveFrom.After(now) {
+			return s.keys[i], nil
+		}
+	}
+
+	return nil, errors.New("no signing key active yet")
+}
+
+// verifying finds the key a token names in its kid header. Keys scheduled but not active yet
+// are accepted too, as replicas with a clock slightly ahead may already sign with them.
+func (s *KeySet) verifying(id string, now time.Time) (*Key, bool) {
+	for _, key := range s.keys {
+		if key.ID == id && !key.retired(now) {
+			return key, true
+		}
+	}
+
+	return nil, false
+}
+
+// Published returns the public keys downstream services need: asymmetric ones not retired,
+// scheduled ones included so their caches know them before the rotation.
+func (s *KeySet) Published(now time.Time) []*Key {
+	keys := make([]*Key, 0, len(s.keys))
+	for _, key := range s.keys {
+		if key.Asymmetric() && !key.retired(now) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+type keyManifest struct {
+	Keys []struct {
+		ID             string    `json:"kid"`
+		Algorithm      string    `json:"alg"`
+		PrivateKeyFile string    `json:"private_key_file"`
+		ActiveFrom     time.Time `json:"active_from"`
+	} `json:"keys"`
+}
+
+// LoadKeys reads a rotation schedule: a JSON manifest listing the kid, alg (RS256 or EdDSA),
+// PEM private key file and activation instant of each key. Key files are relative to the manifest.
+// Extra keys join the schedule, such as the HMAC secret being rotated out.
+func LoadKeys(path string, overlap time.Duration, extra ...*Key) (*KeySet, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest keyManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, fmt.Errorf("key manifest %s: %w", path, err)
+	}
+
+	keys := append(make([]*Key, 0, len(manifest.Keys)+len(extra)), extra...)
+	for _, entry := range manifest.Keys {
+		file := entry.PrivateKeyFile
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(filepath.Dir(path), file)
+		}
+
+		pem, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", entry.ID, err)
+		}
+
+		private, err := parsePrivateKey(entry.Algorithm, pem)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", entry.ID, err)
+		}
+
+		key, err := NewKey(entry.ID, entry.Algorithm, private, entry.ActiveFrom)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(overlap, keys...)
+}
+
+func parsePrivateKey(algorithm string, pem []byte) (crypto.PrivateKey, error) {
+	switch algorithm {
+	case AlgorithmRS256:
+		return jwt.ParseRSAPrivateKeyFromPEM(pem)
+	case AlgorithmEdDSA:
+		return jwt.ParseEdPrivateKeyFromPEM(pem)
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", algorithm)
+	}
+}
@@ -0,0 +1,177 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeySet(t *testing.T) {
+	now := time.Now()
+
+	_, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	auth := func(t *testing.T, overlap time.Duration, keys ...*Key) *jwtAuth {
+		set, err := NewKeySet(overlap, keys...)
+		require.NoError(t, err)
+		return &jwtAuth{keys: set, expire: time.Minute}
+	}
+
+	newKey := func(t *testing.T, id, alg string, private any, activeFrom time.Time) *Key {
+		key, err := NewKey(id, alg, private, activeFrom)
+		require.NoError(t, err)
+		return key
+	}
+
+	t.Run("sign and verify with kid", func(t *testing.T) {
+		for _, key := range []*Key{
+			newKey(t, "ed-1", AlgorithmEdDSA, edPrivate, now.Add(-time.Hour)),
+			newKey(t, "rsa-1", AlgorithmRS256, rsaPrivate, now.Add(-time.Hour)),
+		} {
+			j := auth(t, time.Hour, key)
+			tokenString, err := j.Generate(json.RawMessage(`{"account_id":"abc"}`))
+			require.NoError(t, err)
+
+			parsed, _, err := new(jwt.Parser).ParseUnverified(tokenString, jwt.MapClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, key.ID, parsed.Header["kid"])
+			assert.Equal(t, key.Algorithm, parsed.Header["alg"])
+
+			var claims struct {
+				AccountID string `json:"account_id"`
+			}
+			require.NoError(t, j.Validate(tokenString, &claims))
+			assert.Equal(t, "abc", claims.AccountID)
+		}
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		old := newKey(t, "old", AlgorithmEdDSA, edPrivate, now.Add(-2*time.Hour))
+		next := newKey(t, "next", AlgorithmRS256, rsaPrivate, now.Add(-time.Hour))
+
+		before := auth(t, 2*time.Hour, old)
+		tokenString, err := before.Generate(json.RawMessage(`{}`))
+		require.NoError(t, err)
+
+		within := auth(t, 2*time.Hour, old, next)
+		signing, err := within.keys.signing(now)
+		require.NoError(t, err)
+		assert.Equal(t, "next", signing.ID)
+		assert.NoError(t, within.Validate(tokenString, &jwt.MapClaims{}))
+
+		after := auth(t, 30*time.Minute, old, next)
+		assert.Error(t, after.Validate(tokenString, &jwt.MapClaims{}))
+		assert.Len(t, after.keys.Published(now), 1)
+	})
+
+	t.Run("scheduled key is not used to sign yet", func(t *testing.T) {
+		j := auth(t, time.Hour,
+			newKey(t, "current", AlgorithmEdDSA, edPrivate, now.Add(-time.Hour)),
+			newKey(t, "scheduled", AlgorithmRS256, rsaPrivate, now.Add(time.Hour)),
+		)
+
+		signing, err := j.keys.signing(now)
+		require.NoError(t, err)
+		assert.Equal(t, "current", signing.ID)
+		assert.Len(t, j.JWKS().Keys, 2)
+	})
+
+	t.Run("algorithm confusion", func(t *testing.T) {
+		key := newKey(t, "rsa-1", AlgorithmRS256, rsaPrivate, now.Add(-time.Hour))
+		j := auth(t, time.Hour, key)
+
+		// an HMAC token keyed with the published public key must not verify
+		der, err := x509.MarshalPKIXPublicKey(&rsaPrivate.PublicKey)
+		require.NoError(t, err)
+		forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": now.Add(time.Minute).Unix()})
+		forged.Header["kid"] = "rsa-1"
+		tokenString, err := forged.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+		require.NoError(t, err)
+
+		assert.Error(t, j.Validate(tokenString, &jwt.MapClaims{}))
+	})
+
+	t.Run("jwks", func(t *testing.T) {
+		j := auth(t, time.Hour,
+			newKey(t, "", AlgorithmHS256, []byte("secret"), time.Time{}),
+			newKey(t, "ed-1", AlgorithmEdDSA, edPrivate, now.Add(-time.Hour)),
+			newKey(t, "rsa-1", AlgorithmRS256, rsaPrivate, now.Add(-time.Minute)),
+		)
+
+		set := j.JWKS()
+		require.Len(t, set.Keys, 2)
+
+		assert.Equal(t, JWK{
+			KeyType:   "OKP",
+			KeyID:     "ed-1",
+			Use:       "sig",
+			Algorithm: AlgorithmEdDSA,
+			Curve:     "Ed25519",
+			X:         jwt.EncodeSegment(edPrivate.Public().(ed25519.PublicKey)),
+		}, set.Keys[0])
+
+		assert.Equal(t, "RSA", set.Keys[1].KeyType)
+		assert.Equal(t, "rsa-1", set.Keys[1].KeyID)
+		assert.Equal(t, "AQAB", set.Keys[1].E)
+		assert.Equal(t, jwt.EncodeSegment(rsaPrivate.N.Bytes()), set.Keys[1].N)
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		_, err := NewKey("ed-1", AlgorithmRS256, edPrivate, now)
+		assert.Error(t, err)
+
+		_, err = NewKey("", AlgorithmEdDSA, edPrivate, now)
+		assert.Error(t, err)
+
+		_, err = NewKeySet(0,
+			newKey(t, "dup", AlgorithmEdDSA, edPrivate, now),
+			newKey(t, "dup", AlgorithmRS256, rsaPrivate, now.Add(time.Hour)),
+		)
+		assert.Error(t, err)
+	})
+
+	t.Run("load manifest", func(t *testing.T) {
+		dir := t.TempDir()
+		writePEM := func(name string, private any) {
+			der, err := x509.MarshalPKCS8PrivateKey(private)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+		}
+		writePEM("ed.pem", edPrivate)
+		writePEM("rsa.pem", rsaPrivate)
+
+		manifest := `{"keys":[
+			{"kid":"rsa-1","alg":"RS256","private_key_file":"rsa.pem","active_from":"` + now.Add(time.Hour).Format(time.RFC3339) + `"},
+			{"kid":"ed-1","alg":"EdDSA","private_key_file":"ed.pem","active_from":"` + now.Add(-time.Hour).Format(time.RFC3339) + `"}
+		]}`
+		path := filepath.Join(dir, "keys.json")
+		require.NoError(t, os.WriteFile(path, []byte(manifest), 0o600))
+
+		legacy := newKey(t, "", AlgorithmHS256, []byte("secret"), time.Time{})
+		set, err := LoadKeys(path, 2*time.Hour, legacy)
+		require.NoError(t, err)
+
+		signing, err := set.signing(now)
+		require.NoError(t, err)
+		assert.Equal(t, "ed-1", signing.ID)
+
+		_, ok := set.verifying("", now)
+		assert.True(t, ok, "legacy secret verifies within the overlap")
+		_, ok = set.verifying("", now.Add(time.Hour))
+		assert.False(t, ok)
+	})
+}
@@ -1,20 +1,103 @@
 package token
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"strings"
+
+	"github.com/google/uuid"
 )
 
-func Middle(tokenString string, target any) error {
+var ErrRevoked = errors.New("token revoked")
+
+// RevocationStore tells whether a token id (jti) or session id (sid) was revoked before expiring.
+type RevocationStore interface {
+	IsTokenRevoked(ctx context.Context, ids ...uuid.UUID) (bool, error)
+}
+
+var revocations RevocationStore
+
+// UseRevocationStore makes Middle reject tokens revoked in the store.
+func UseRevocationStore(store RevocationStore) {
+	revocations = store
+}
+
+// APIKeyAuthenticator checks an API key and returns the claims of the account it belongs to, as
+// they would appear in an access token.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (json.RawMessage, error)
+}
+
+var apiKeys APIKeyAuthenticator
+
+// UseAPIKeys makes Middle accept API keys as Bearer tokens alongside JWTs.
+func UseAPIKeys(authenticator APIKeyAuthenticator) {
+	apiKeys = authenticator
+}
+
+type revocableClaims struct {
+	ID        uuid.UUID `json:"jti"`
+	SessionID uuid.UUID `json:"sid"`
+}
+
+func Middle(ctx context.Context, tokenString string, target any) error {
 	if tokenString == "" {
-		return errors.New("TODO:")
+		return errors.New("missing authorization token")
 	}
 
 	parts := strings.Split(tokenString, " ")
 	if len(parts) < 2 || parts[0] != "Bearer" {
-		return errors.New("TODO:")
+		return errors.New("authorization must be a Bearer token")
+	}
+
+	// a JWT always has dots between its segments, an API key never does
+	if apiKeys != nil && !strings.Contains(parts[1], ".") {
+		raw, err := apiKeys.AuthenticateAPIKey(ctx, parts[1])
+		if err != nil {
+			return err
+		}
+
+		return json.Unmarshal(raw, target)
+	}
+
+	raw, err := JWT.parse(parts[1])
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(raw, target); err != nil {
+		return err
+	}
+
+	if revocations == nil {
+		return nil
+	}
+
+	var claims revocableClaims
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		return err
+	}
+
+	ids := make([]uuid.UUID, 0, 2)
+	for _, id := range []uuid.UUID{claims.ID, claims.SessionID} {
+		if id != uuid.Nil {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	revoked, err := revocations.IsTokenRevoked(ctx, ids...)
+	if err != nil {
+		return err
+	}
+
+	if revoked {
+		return ErrRevoked
 	}
 
-	tokenString = parts[1]
-	return JWT.Validate(tokenString, target)
+	return nil
 }
@@ -11,17 +11,26 @@ import (
 var JWT *jwtAuth
 
 type jwtAuth struct {
-	secret string
+	keys   *KeySet
 	expire time.Duration
 }
 
 func (j *jwtAuth) Generate(data json.RawMessage) (string, error) {
-	expiredAt := time.Now().UTC().Add(j.expire)
-	token := jwt.New(jwt.SigningMethodHS256)
+	now := time.Now().UTC()
+	key, err := j.keys.signing(now)
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.New(key.method)
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
+	}
+
 	claims := token.Claims.(jwt.MapClaims)
-	claims["exp"] = expiredAt.Unix()
+	claims["exp"] = now.Add(j.expire).Unix()
 	json.Unmarshal(data, &claims)
-	tokenString, err := token.SignedString([]byte(j.secret))
+	tokenString, err := token.SignedString(key.private)
 	if err != nil {
 		return "", err
 	}
@@ -30,39 +39,62 @@ func (j *jwtAuth) Generate(data json.RawMessage) (string, error) {
 }
 
 func (j *jwtAuth) Validate(tokenString string, target any) error {
+	raw, err := j.parse(tokenString)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, target)
+}
+
+// parse checks the signature and expiration of the token and returns its claims as JSON.
+func (j *jwtAuth) parse(tokenString string) (json.RawMessage, error) {
 	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := t.Header["kid"].(string)
+		key, ok := j.keys.verifying(kid, time.Now())
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+
+		// the key decides the algorithm, never the token: an RSA public key must not pass as an HMAC secret
+		if t.Method.Alg() != key.method.Alg() {
 			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
 		}
-		return []byte(j.secret), nil
+		return key.public, nil
 	})
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		expirationTime := time.Unix(int64(claims["exp"].(float64)), 0)
-		if time.Now().After(expirationTime) {
-			return fmt.Errorf("token has expired")
-		}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
 
-		raw, err := json.Marshal(claims)
-		if err != nil {
-			return err
-		}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("token has no expiration")
+	}
 
-		if err := json.Unmarshal(raw, target); err != nil {
-			return err
-		}
+	if time.Now().After(time.Unix(int64(exp), 0)) {
+		return nil, fmt.Errorf("token has expired")
 	}
 
-	return nil
+	return json.Marshal(claims)
 }
 
+// InitJWT signs tokens with a shared HMAC secret and no kid, as every replica did before keys
+// could be rotated; UseKeys switches to a key set.
 func InitJWT(secret string, tokenExpire time.Duration) {
+	key, _ := NewKey("", AlgorithmHS256, []byte(secret), time.Time{})
+	keys, _ := NewKeySet(0, key)
 	JWT = &jwtAuth{
-		secret: secret,
+		keys:   keys,
 		expire: tokenExpire,
 	}
 }
+
+func UseKeys(keys *KeySet) {
+	JWT.keys = keys
+}
@@ -25,6 +25,8 @@ type AccountsClient interface {
 	Create(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*CreateAccountResponse, error)
 	Fetch(ctx context.Context, in *FetchAccountRequest, opts ...grpc.CallOption) (*FetchAccountResponse, error)
 	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	Statement(ctx context.Context, in *StatementRequest, opts ...grpc.CallOption) (*StatementResponse, error)
+	Balance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceResponse, error)
 }
 
 type accountsClient struct {
@@ -62,6 +64,24 @@ func (c *accountsClient) List(ctx context.Context, in *ListRequest, opts ...grpc
 	return out, nil
 }
 
+func (c *accountsClient) Statement(ctx context.Context, in *StatementRequest, opts ...grpc.CallOption) (*StatementResponse, error) {
+	out := new(StatementResponse)
+	err := c.cc.Invoke(ctx, "/pb.Accounts/Statement", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) Balance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceResponse, error) {
+	out := new(BalanceResponse)
+	err := c.cc.Invoke(ctx, "/pb.Accounts/Balance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AccountsServer is the server API for Accounts service.
 // All implementations must embed UnimplementedAccountsServer
 // for forward compatibility
@@ -69,6 +89,8 @@ type AccountsServer interface {
 	Create(context.Context, *CreateAccountRequest) (*CreateAccountResponse, error)
 	Fetch(context.Context, *FetchAccountRequest) (*FetchAccountResponse, error)
 	List(context.Context, *ListRequest) (*ListResponse, error)
+	Statement(context.Context, *StatementRequest) (*StatementResponse, error)
+	Balance(context.Context, *BalanceRequest) (*BalanceResponse, error)
 	mustEmbedUnimplementedAccountsServer()
 }
 
@@ -85,6 +107,12 @@ func (UnimplementedAccountsServer) Fetch(context.Context, *FetchAccountRequest)
 func (UnimplementedAccountsServer) List(context.Context, *ListRequest) (*ListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
 }
+func (UnimplementedAccountsServer) Statement(context.Context, *StatementRequest) (*StatementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Statement not implemented")
+}
+func (UnimplementedAccountsServer) Balance(context.Context, *BalanceRequest) (*BalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Balance not implemented")
+}
 func (UnimplementedAccountsServer) mustEmbedUnimplementedAccountsServer() {}
 
 // UnsafeAccountsServer may be embedded to opt out of forward compatibility for this service.
@@ -152,6 +180,42 @@ func _Accounts_List_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Accounts_Statement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).Statement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Accounts/Statement",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).Statement(ctx, req.(*StatementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_Balance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).Balance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Accounts/Balance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).Balance(ctx, req.(*BalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Accounts_ServiceDesc is the grpc.ServiceDesc for Accounts service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -171,6 +235,14 @@ var Accounts_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "List",
 			Handler:    _Accounts_List_Handler,
 		},
+		{
+			MethodName: "Statement",
+			Handler:    _Accounts_Statement_Handler,
+		},
+		{
+			MethodName: "Balance",
+			Handler:    _Accounts_Balance_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/pb/application.proto",
@@ -182,6 +254,7 @@ var Accounts_ServiceDesc = grpc.ServiceDesc{
 type TransactionsClient interface {
 	Deposit(ctx context.Context, in *DepositRequest, opts ...grpc.CallOption) (*TransactionResponse, error)
 	Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransactionResponse, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Transactions_WatchClient, error)
 }
 
 type transactionsClient struct {
@@ -210,12 +283,45 @@ func (c *transactionsClient) Transfer(ctx context.Context, in *TransferRequest,
 	return out, nil
 }
 
+func (c *transactionsClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Transactions_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Transactions_ServiceDesc.Streams[0], "/pb.Transactions/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &transactionsWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Transactions_WatchClient interface {
+	Recv() (*TransactionEvent, error)
+	grpc.ClientStream
+}
+
+type transactionsWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *transactionsWatchClient) Recv() (*TransactionEvent, error) {
+	m := new(TransactionEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // TransactionsServer is the server API for Transactions service.
 // All implementations must embed UnimplementedTransactionsServer
 // for forward compatibility
 type TransactionsServer interface {
 	Deposit(context.Context, *DepositRequest) (*TransactionResponse, error)
 	Transfer(context.Context, *TransferRequest) (*TransactionResponse, error)
+	Watch(*WatchRequest, Transactions_WatchServer) error
 	mustEmbedUnimplementedTransactionsServer()
 }
 
@@ -229,6 +335,9 @@ func (UnimplementedTransactionsServer) Deposit(context.Context, *DepositRequest)
 func (UnimplementedTransactionsServer) Transfer(context.Context, *TransferRequest) (*TransactionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Transfer not implemented")
 }
+func (UnimplementedTransactionsServer) Watch(*WatchRequest, Transactions_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 func (UnimplementedTransactionsServer) mustEmbedUnimplementedTransactionsServer() {}
 
 // UnsafeTransactionsServer may be embedded to opt out of forward compatibility for this service.
@@ -278,6 +387,27 @@ func _Transactions_Transfer_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Transactions_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TransactionsServer).Watch(m, &transactionsWatchServer{stream})
+}
+
+type Transactions_WatchServer interface {
+	Send(*TransactionEvent) error
+	grpc.ServerStream
+}
+
+type transactionsWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *transactionsWatchServer) Send(m *TransactionEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // Transactions_ServiceDesc is the grpc.ServiceDesc for Transactions service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -294,7 +424,13 @@ var Transactions_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _Transactions_Transfer_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _Transactions_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "pkg/pb/application.proto",
 }
 